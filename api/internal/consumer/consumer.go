@@ -112,3 +112,7 @@ func (c *Consumer) Name() string {
 func (c *Consumer) PluginConfig(name string) api.PluginConsumerConfig {
 	return c.ConsumerConfigs[name]
 }
+
+func (c *Consumer) Metadata() map[string]string {
+	return c.Consumer.Metadata
+}
@@ -16,6 +16,7 @@ package consumer
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -79,7 +80,15 @@ func UpdateConsumers(value *structpb.Struct) {
 	scopeIndex = make(map[string]map[string]map[string]*Consumer)
 	for ns, nsValue := range resourceIndex {
 		nsScopeIdx := make(map[string]map[string]*Consumer)
-		for _, value := range nsValue {
+		// When the indexes collide, the consumer with the smaller name wins, so the result doesn't
+		// depend on the map iteration order.
+		names := make([]string, 0, len(nsValue))
+		for name := range nsValue {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := nsValue[name]
 			for pluginName, cfg := range value.ConsumerConfigs {
 				pluginScopeIdx := nsScopeIdx[pluginName]
 				if pluginScopeIdx == nil {
@@ -87,15 +96,23 @@ func UpdateConsumers(value *structpb.Struct) {
 					nsScopeIdx[pluginName] = pluginScopeIdx
 				}
 
-				idx := cfg.Index()
-				if pluginScopeIdx[idx] != nil {
-					// TODO: find an effective way to detect collision in the control plane
-					err := fmt.Errorf("duplicate index %s", value.name)
-					logger.Error(err, fmt.Sprintf("ignore consumer %s for plugin %s", pluginName, idx),
-						"namespace", ns, "existing consumer", pluginScopeIdx[idx].name)
-					continue
+				var indexes []string
+				if multiIdxCfg, ok := cfg.(api.PluginConsumerMultiIndexConfig); ok {
+					indexes = multiIdxCfg.Indexes()
+				} else {
+					indexes = []string{cfg.Index()}
+				}
+
+				for _, idx := range indexes {
+					if pluginScopeIdx[idx] != nil {
+						// TODO: find an effective way to detect collision in the control plane
+						err := fmt.Errorf("duplicate index %s", value.name)
+						logger.Error(err, fmt.Sprintf("ignore consumer %s for plugin %s", pluginName, idx),
+							"namespace", ns, "existing consumer", pluginScopeIdx[idx].name)
+						continue
+					}
+					pluginScopeIdx[idx] = value
 				}
-				pluginScopeIdx[idx] = value
 			}
 		}
 		scopeIndex[ns] = nsScopeIdx
@@ -103,7 +120,25 @@ func UpdateConsumers(value *structpb.Struct) {
 }
 
 // LookupConsumer returns the consumer config for the given namespace, plugin name and key.
+// If the consumer is not pushed from the control plane, it will be looked up from the store if configured.
 func LookupConsumer(ns, pluginName, key string) (api.Consumer, bool) {
+	c, ok := lookupIndex(ns, pluginName, key)
+	if ok {
+		return c, true
+	}
+	return lookupStore(ns, pluginName, key)
+}
+
+// GetConsumer returns the consumer pushed from the control plane with the given namespace and name.
+func GetConsumer(ns, name string) (api.Consumer, bool) {
+	indexMutex.RLock()
+	defer indexMutex.RUnlock()
+
+	c, ok := resourceIndex[ns][name]
+	return c, ok
+}
+
+func lookupIndex(ns, pluginName, key string) (api.Consumer, bool) {
 	indexMutex.RLock()
 	defer indexMutex.RUnlock()
 
@@ -104,3 +104,83 @@ func TestUpdateConsumer(t *testing.T) {
 	r, _ = LookupConsumer("ns", "consumerPluginX", "two")
 	require.Equal(t, "you", r.Name())
 }
+
+func TestUpdateConsumerWithMultipleIndexes(t *testing.T) {
+	plugins.RegisterPlugin("consumerPluginMultiIndex", &multiIndexConsumerPlugin{})
+
+	// clean index
+	resourceIndex = make(map[string]map[string]*Consumer)
+
+	c := &Consumer{
+		name:       "me",
+		generation: 1,
+		Consumer: model.Consumer{
+			Auth: map[string]string{
+				"consumerPluginMultiIndex": "{\"key\": \"old,new\"}",
+			},
+		},
+	}
+	c2 := &Consumer{
+		name:       "you",
+		generation: 1,
+		Consumer: model.Consumer{
+			Auth: map[string]string{
+				"consumerPluginMultiIndex": "{\"key\": \"yours,new\"}",
+			},
+		},
+	}
+	v := newConsumerTest().Add("ns", c).Build()
+	UpdateConsumers(v)
+
+	for _, key := range []string{"old", "new"} {
+		r, _ := LookupConsumer("ns", "consumerPluginMultiIndex", key)
+		require.NotNil(t, r)
+		require.Equal(t, "me", r.Name())
+	}
+	r, _ := LookupConsumer("ns", "consumerPluginMultiIndex", "old,new")
+	require.Nil(t, r)
+
+	// rotate
+	c.generation = 2
+	c.Auth["consumerPluginMultiIndex"] = "{\"key\": \"new\"}"
+	v = newConsumerTest().Add("ns", c).Add("ns", c2).Build()
+	UpdateConsumers(v)
+	r, _ = LookupConsumer("ns", "consumerPluginMultiIndex", "old")
+	require.Nil(t, r)
+	r, _ = LookupConsumer("ns", "consumerPluginMultiIndex", "yours")
+	require.Equal(t, "you", r.Name())
+	// the conflicted index is kept by the consumer with the smaller name
+	for i := 0; i < 10; i++ {
+		UpdateConsumers(v)
+		r, _ = LookupConsumer("ns", "consumerPluginMultiIndex", "new")
+		require.Equal(t, "me", r.Name())
+	}
+}
+
+func TestGetConsumer(t *testing.T) {
+	plugins.RegisterPlugin("consumerPluginX", &consumerPlugin{})
+
+	// clean index
+	resourceIndex = make(map[string]map[string]*Consumer)
+
+	c := &Consumer{
+		name:       "anonymous",
+		generation: 1,
+		Consumer: model.Consumer{
+			Auth: map[string]string{
+				"consumerPluginX": "{\"key\": \"anonymous\"}",
+			},
+		},
+	}
+	v := newConsumerTest().Add("ns", c).Build()
+	UpdateConsumers(v)
+
+	r, ok := GetConsumer("ns", "anonymous")
+	require.True(t, ok)
+	require.Equal(t, "anonymous", r.Name())
+
+	_, ok = GetConsumer("ns", "not_found")
+	require.False(t, ok)
+	_, ok = GetConsumer("other_ns", "anonymous")
+	require.False(t, ok)
+}
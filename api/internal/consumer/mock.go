@@ -15,6 +15,8 @@
 package consumer
 
 import (
+	"strings"
+
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/api/pkg/plugins"
 )
@@ -51,6 +53,22 @@ func (conf *ConsumerConfig) Index() string {
 	return conf.Key
 }
 
+type multiIndexConsumerPlugin struct {
+	consumerPlugin
+}
+
+func (p *multiIndexConsumerPlugin) ConsumerConfig() api.PluginConsumerConfig {
+	return &multiIndexConsumerConfig{}
+}
+
+type multiIndexConsumerConfig struct {
+	ConsumerConfig
+}
+
+func (conf *multiIndexConsumerConfig) Indexes() []string {
+	return strings.Split(conf.Key, ",")
+}
+
 type filterPlugin struct {
 	plugins.PluginMethodDefaultImpl
 }
@@ -85,3 +103,7 @@ func (c *MockConsumer) Name() string {
 func (c *MockConsumer) PluginConfig(_ string) api.PluginConsumerConfig {
 	return &ConsumerConfig{}
 }
+
+func (c *MockConsumer) Metadata() map[string]string {
+	return nil
+}
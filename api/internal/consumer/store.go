@@ -0,0 +1,228 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	csModel "mosn.io/htnn/api/pkg/consumer/model"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+)
+
+// Store resolves the consumers which are not pushed from the control plane.
+type Store interface {
+	// Get returns the name and the configuration of the consumer which owns the given key of the authn plugin
+	// in the namespace. It returns a nil consumer if the consumer is not found.
+	Get(ns, pluginName, key string) (name string, consumer *csModel.Consumer, err error)
+}
+
+type StoreOptions struct {
+	// CacheSize is the max number of cached lookup results, including the negative ones.
+	CacheSize int
+	// TTL is the duration to cache the found consumer.
+	TTL time.Duration
+	// NegativeTTL is the duration to cache the missing consumer.
+	NegativeTTL time.Duration
+}
+
+var (
+	currentStore atomic.Pointer[cachedStore]
+)
+
+// SetStore sets the store which is used when the consumer is not found in the index pushed from the
+// control plane. Setting a nil store removes the current store.
+func SetStore(s Store, opts StoreOptions) {
+	if s == nil {
+		currentStore.Store(nil)
+		return
+	}
+	currentStore.Store(newCachedStore(s, opts))
+}
+
+type cacheKey struct {
+	ns         string
+	pluginName string
+	key        string
+}
+
+type cacheEntry struct {
+	key      cacheKey
+	consumer *Consumer // nil means the consumer is not found
+	expireAt time.Time
+}
+
+// inflightLookup is a store lookup in progress. The concurrent lookups of the same key wait for it
+// instead of querying the store again.
+type inflightLookup struct {
+	done     chan struct{}
+	consumer *Consumer
+}
+
+type cachedStore struct {
+	store Store
+	opts  StoreOptions
+
+	lock     sync.Mutex
+	items    map[cacheKey]*list.Element
+	lru      *list.List
+	inflight map[cacheKey]*inflightLookup
+}
+
+func newCachedStore(s Store, opts StoreOptions) *cachedStore {
+	return &cachedStore{
+		store:    s,
+		opts:     opts,
+		items:    make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[cacheKey]*inflightLookup),
+	}
+}
+
+func (cs *cachedStore) getFromCache(k cacheKey, now time.Time) (*Consumer, bool) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	elem, ok := cs.items[k]
+	if !ok {
+		return nil, false
+	}
+	entry, _ := elem.Value.(*cacheEntry)
+	if !now.Before(entry.expireAt) {
+		cs.lru.Remove(elem)
+		delete(cs.items, k)
+		return nil, false
+	}
+	cs.lru.MoveToFront(elem)
+	return entry.consumer, true
+}
+
+func (cs *cachedStore) addToCache(k cacheKey, c *Consumer, now time.Time) {
+	ttl := cs.opts.TTL
+	if c == nil {
+		ttl = cs.opts.NegativeTTL
+	}
+	if ttl <= 0 || cs.opts.CacheSize <= 0 {
+		return
+	}
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	entry := &cacheEntry{
+		key:      k,
+		consumer: c,
+		expireAt: now.Add(ttl),
+	}
+	if elem, ok := cs.items[k]; ok {
+		elem.Value = entry
+		cs.lru.MoveToFront(elem)
+		return
+	}
+
+	cs.items[k] = cs.lru.PushFront(entry)
+	for cs.lru.Len() > cs.opts.CacheSize {
+		oldest := cs.lru.Back()
+		cs.lru.Remove(oldest)
+		evicted, _ := oldest.Value.(*cacheEntry)
+		delete(cs.items, evicted.key)
+	}
+}
+
+func (cs *cachedStore) lookup(ns, pluginName, key string) (*Consumer, bool) {
+	k := cacheKey{ns: ns, pluginName: pluginName, key: key}
+	now := time.Now()
+	if c, ok := cs.getFromCache(k, now); ok {
+		return c, c != nil
+	}
+
+	cs.lock.Lock()
+	if call, ok := cs.inflight[k]; ok {
+		cs.lock.Unlock()
+		<-call.done
+		return call.consumer, call.consumer != nil
+	}
+	call := &inflightLookup{done: make(chan struct{})}
+	cs.inflight[k] = call
+	cs.lock.Unlock()
+
+	defer func() {
+		cs.lock.Lock()
+		delete(cs.inflight, k)
+		cs.lock.Unlock()
+		close(call.done)
+	}()
+
+	call.consumer = cs.fetch(k, now)
+	return call.consumer, call.consumer != nil
+}
+
+func (cs *cachedStore) fetch(k cacheKey, now time.Time) *Consumer {
+	ns, pluginName, key := k.ns, k.pluginName, k.key
+	name, data, err := cs.store.Get(ns, pluginName, key)
+	if err != nil {
+		// don't cache the error so that we can retry in the next request
+		logger.Error(err, "failed to get consumer from store", "namespace", ns, "plugin", pluginName)
+		return nil
+	}
+
+	var c *Consumer
+	if data != nil {
+		c = &Consumer{
+			Consumer:  *data,
+			name:      name,
+			namespace: ns,
+		}
+		err = c.InitConfigs()
+		if err != nil {
+			logger.Error(err, "failed to init consumer from store", "name", name, "namespace", ns)
+			c = nil
+		} else if !hasIndex(c, pluginName, key) {
+			api.LogWarnf("consumer %s from store doesn't own the key of plugin %s, namespace: %s",
+				name, pluginName, ns)
+			c = nil
+		}
+	}
+
+	cs.addToCache(k, c, now)
+	return c
+}
+
+func hasIndex(c *Consumer, pluginName, key string) bool {
+	cfg, ok := c.ConsumerConfigs[pluginName]
+	if !ok {
+		return false
+	}
+	if multiIdxCfg, ok := cfg.(api.PluginConsumerMultiIndexConfig); ok {
+		return slices.Contains(multiIdxCfg.Indexes(), key)
+	}
+	return cfg.Index() == key
+}
+
+func lookupStore(ns, pluginName, key string) (api.Consumer, bool) {
+	cs := currentStore.Load()
+	if cs == nil {
+		return nil, false
+	}
+
+	c, ok := cs.lookup(ns, pluginName, key)
+	if !ok {
+		return nil, false
+	}
+	return c, true
+}
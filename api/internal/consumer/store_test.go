@@ -0,0 +1,224 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"mosn.io/htnn/api/pkg/consumer/model"
+	"mosn.io/htnn/api/pkg/plugins"
+)
+
+type mockStore struct {
+	consumers map[string]*model.Consumer
+	err       error
+	calls     int
+}
+
+func (s *mockStore) Get(_, _, key string) (string, *model.Consumer, error) {
+	s.calls++
+	if s.err != nil {
+		return "", nil, s.err
+	}
+	c, ok := s.consumers[key]
+	if !ok {
+		return "", nil, nil
+	}
+	return key, c, nil
+}
+
+func TestLookupConsumerFromStore(t *testing.T) {
+	plugins.RegisterPlugin("consumerPluginX", &consumerPlugin{})
+	// clean index
+	scopeIndex = nil
+
+	store := &mockStore{
+		consumers: map[string]*model.Consumer{
+			"rick": {
+				Auth: map[string]string{
+					"consumerPluginX": `{"key":"rick"}`,
+				},
+			},
+			"fake": {
+				Auth: map[string]string{
+					"consumerPluginX": `{"key":"morty"}`,
+				},
+			},
+		},
+	}
+	SetStore(store, StoreOptions{
+		CacheSize:   2,
+		TTL:         time.Hour,
+		NegativeTTL: time.Hour,
+	})
+	defer SetStore(nil, StoreOptions{})
+
+	c, ok := LookupConsumer("ns", "consumerPluginX", "rick")
+	require.True(t, ok)
+	require.Equal(t, "rick", c.Name())
+	require.Equal(t, 1, store.calls)
+	// cached
+	c, ok = LookupConsumer("ns", "consumerPluginX", "rick")
+	require.True(t, ok)
+	require.Equal(t, "rick", c.Name())
+	require.Equal(t, 1, store.calls)
+
+	// negative cache
+	_, ok = LookupConsumer("ns", "consumerPluginX", "summer")
+	require.False(t, ok)
+	_, ok = LookupConsumer("ns", "consumerPluginX", "summer")
+	require.False(t, ok)
+	require.Equal(t, 2, store.calls)
+
+	// the consumer doesn't own the key
+	_, ok = LookupConsumer("ns", "consumerPluginX", "fake")
+	require.False(t, ok)
+	require.Equal(t, 3, store.calls)
+
+	// evicted by LRU
+	_, ok = LookupConsumer("ns", "consumerPluginX", "rick")
+	require.True(t, ok)
+	require.Equal(t, 4, store.calls)
+
+	// error is not cached
+	store.err = errors.New("ouch")
+	_, ok = LookupConsumer("ns", "consumerPluginX", "error")
+	require.False(t, ok)
+	_, ok = LookupConsumer("ns", "consumerPluginX", "error")
+	require.False(t, ok)
+	require.Equal(t, 6, store.calls)
+}
+
+func TestLookupConsumerFromStoreTTL(t *testing.T) {
+	plugins.RegisterPlugin("consumerPluginX", &consumerPlugin{})
+	// clean index
+	scopeIndex = nil
+
+	store := &mockStore{
+		consumers: map[string]*model.Consumer{
+			"rick": {
+				Auth: map[string]string{
+					"consumerPluginX": `{"key":"rick"}`,
+				},
+			},
+		},
+	}
+	SetStore(store, StoreOptions{
+		CacheSize:   10,
+		TTL:         20 * time.Millisecond,
+		NegativeTTL: 10 * time.Millisecond,
+	})
+	defer SetStore(nil, StoreOptions{})
+
+	LookupConsumer("ns", "consumerPluginX", "rick")
+	LookupConsumer("ns", "consumerPluginX", "summer")
+	require.Equal(t, 2, store.calls)
+	time.Sleep(15 * time.Millisecond)
+	LookupConsumer("ns", "consumerPluginX", "rick")
+	LookupConsumer("ns", "consumerPluginX", "summer")
+	require.Equal(t, 3, store.calls)
+	time.Sleep(10 * time.Millisecond)
+	LookupConsumer("ns", "consumerPluginX", "rick")
+	require.Equal(t, 4, store.calls)
+}
+
+func TestLookupConsumerPreferIndex(t *testing.T) {
+	plugins.RegisterPlugin("consumerPluginX", &consumerPlugin{})
+	resourceIndex = make(map[string]map[string]*Consumer)
+
+	c := &Consumer{
+		name:       "me",
+		generation: 1,
+		Consumer: model.Consumer{
+			Auth: map[string]string{
+				"consumerPluginX": `{"key":"rick"}`,
+			},
+		},
+	}
+	UpdateConsumers(newConsumerTest().Add("ns", c).Build())
+
+	store := &mockStore{}
+	SetStore(store, StoreOptions{})
+	defer SetStore(nil, StoreOptions{})
+
+	r, ok := LookupConsumer("ns", "consumerPluginX", "rick")
+	require.True(t, ok)
+	require.Equal(t, "me", r.Name())
+	require.Equal(t, 0, store.calls)
+
+	_, ok = LookupConsumer("ns", "consumerPluginX", "morty")
+	require.False(t, ok)
+	require.Equal(t, 1, store.calls)
+}
+
+type blockingStore struct {
+	mockStore
+	lock    sync.Mutex
+	release chan struct{}
+}
+
+func (s *blockingStore) Get(ns, pluginName, key string) (string, *model.Consumer, error) {
+	<-s.release
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.mockStore.Get(ns, pluginName, key)
+}
+
+func TestLookupConsumerFromStoreConcurrently(t *testing.T) {
+	plugins.RegisterPlugin("consumerPluginX", &consumerPlugin{})
+	// clean index
+	scopeIndex = nil
+
+	store := &blockingStore{
+		mockStore: mockStore{
+			consumers: map[string]*model.Consumer{
+				"rick": {
+					Auth: map[string]string{
+						"consumerPluginX": `{"key":"rick"}`,
+					},
+				},
+			},
+		},
+		release: make(chan struct{}),
+	}
+	SetStore(store, StoreOptions{
+		CacheSize:   10,
+		TTL:         time.Hour,
+		NegativeTTL: time.Hour,
+	})
+	defer SetStore(nil, StoreOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, ok := LookupConsumer("ns", "consumerPluginX", "rick")
+			require.True(t, ok)
+			require.Equal(t, "rick", c.Name())
+		}()
+	}
+	// wait for the lookups to be blocked
+	time.Sleep(50 * time.Millisecond)
+	close(store.release)
+	wg.Wait()
+	// the concurrent lookups of the same key share one query to the store
+	require.Equal(t, 1, store.calls)
+}
@@ -23,6 +23,8 @@ import (
 type Consumer struct {
 	Auth    map[string]string              `json:"auth"`
 	Filters map[string]*model.FilterConfig `json:"filters,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 func (c *Consumer) Marshal() string {
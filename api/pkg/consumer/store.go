@@ -0,0 +1,33 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	internalConsumer "mosn.io/htnn/api/internal/consumer"
+)
+
+// Store resolves the consumers which are not pushed from the control plane, for example, the consumers
+// saved in an external database. The consumer returned from the Store is the same as the one pushed from
+// the control plane, so the authn plugins don't need to know where the consumer comes from.
+type Store = internalConsumer.Store
+
+// StoreOptions controls how the lookup results from the Store are cached in the data plane.
+type StoreOptions = internalConsumer.StoreOptions
+
+// SetStore sets the Store used when the consumer is not found in the configuration pushed from the
+// control plane. Setting a nil Store removes the current one.
+func SetStore(s Store, opts StoreOptions) {
+	internalConsumer.SetStore(s, opts)
+}
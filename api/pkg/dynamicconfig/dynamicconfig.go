@@ -17,11 +17,13 @@ package dynamicconfig
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	xds "github.com/cncf/xds/go/xds/type/v3"
 	capi "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"mosn.io/htnn/api/internal/proto"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
@@ -31,10 +33,25 @@ import (
 var (
 	logger = log.DefaultLogger.WithName("dynamicconfig")
 
-	dynamicConfigProviders = map[string]DynamicConfigProvider{}
-	dynamicConfigHandlers  = map[string]DynamicConfigHandler{}
+	dynamicConfigProviders        = map[string]DynamicConfigProvider{}
+	dynamicConfigHandlers         = map[string]DynamicConfigHandler{}
+	dynamicConfigHandlerFactories = map[string]DynamicConfigHandlerFactory{}
+
+	namespacedHandlersLock sync.Mutex
+	// namespace/name -> handler created by the factory
+	namespacedHandlers = map[string]DynamicConfigHandler{}
+
+	appliedLock           sync.Mutex
+	appliedDynamicConfigs = map[string]*appliedDynamicConfig{}
 )
 
+type appliedDynamicConfig struct {
+	namespace string
+	name      string
+	version   string
+	config    DynamicConfig
+}
+
 type dynamicConfigFilter struct {
 	capi.PassThroughStreamFilter
 
@@ -68,42 +85,131 @@ func (p *DynamicConfigParser) Parse(any *anypb.Any, callbacks capi.ConfigCallbac
 	}
 
 	fields := configStruct.Value.GetFields()
+	// The golang filter doesn't provide a hook when the config is destroyed. So the control plane
+	// dispatches the index of all the DynamicConfigs, and the removed ones are purged explicitly.
+	if index := fields["index"]; index != nil {
+		purgeDynamicConfigs(index.GetStructValue())
+		return placeholder, nil
+	}
+
 	name := fields["name"].GetStringValue()
 	cfg := fields["config"]
 	if name == "" || cfg == nil {
 		return nil, fmt.Errorf("invalid dynamic config format: %s", configStruct.Value.String())
 	}
 
-	cb, ok := dynamicConfigHandlers[name]
+	update := &DynamicConfigUpdate{
+		Namespace: fields["namespace"].GetStringValue(),
+		Version:   fields["version"].GetStringValue(),
+		Rollback:  fields["rollback"].GetBoolValue(),
+	}
+	cb, ok := loadDynamicConfigHandler(name, update.Namespace)
 	if !ok {
 		// ignore unknown dynamic config as like ignoring unknown plugin
 		api.LogInfof("no callback for dynamic config %s", name)
 		return placeholder, nil
 	}
 
+	// the DynamicConfig from the root namespace can be referred from all namespaces
+	root := fields["root"].GetBoolValue()
+	err := applyDynamicConfig(name, cfg, cb, update, root)
+	recordStatus(update.Namespace, name, update.Version, err)
+	if err != nil {
+		return nil, err
+	}
+	return placeholder, nil
+}
+
+func applyDynamicConfig(name string, cfg *structpb.Value, cb DynamicConfigHandler, update *DynamicConfigUpdate,
+	root bool) error {
 	conf := cb.Config()
 	data, err := cfg.MarshalJSON()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	api.LogInfof("receive dynamic config %s, configuration: %s", name, data)
+	api.LogInfof("receive dynamic config %s, namespace: %s, version: %s, configuration: %s",
+		name, update.Namespace, update.Version, data)
 	err = proto.UnmarshalJSON(data, conf)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	err = conf.Validate()
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	key := update.Namespace + "/" + name
+	appliedLock.Lock()
+	defer appliedLock.Unlock()
+
+	if prev, ok := appliedDynamicConfigs[key]; ok {
+		update.Previous = prev.config
+		update.PreviousVersion = prev.version
+	}
+	update.Config = conf
+
+	if v, ok := cb.(DynamicConfigValidator); ok {
+		// Reject the update before the handler commits it, so the previous config is still in use
+		if err = v.ValidateUpdate(update); err != nil {
+			return err
+		}
 	}
 
-	err = cb.OnUpdate(conf)
+	if u, ok := cb.(DynamicConfigUpdater); ok {
+		err = u.OnDynamicConfigUpdate(update)
+	} else {
+		err = cb.OnUpdate(conf)
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return placeholder, nil
+	updateSeq.Add(1)
+	appliedDynamicConfigs[key] = &appliedDynamicConfig{
+		namespace: update.Namespace,
+		name:      name,
+		version:   update.Version,
+		config:    conf,
+	}
+	if root {
+		rootApplied[name] = key
+	} else if rootApplied[name] == key {
+		delete(rootApplied, name)
+	}
+	return nil
+}
+
+// purgeDynamicConfigs removes the applied configs which are not in the index. The index maps
+// the namespace to the names of the DynamicConfigs dispatched from it.
+func purgeDynamicConfigs(index *structpb.Struct) {
+	dispatched := map[string]bool{}
+	for ns, names := range index.GetFields() {
+		for _, name := range names.GetListValue().GetValues() {
+			dispatched[ns+"/"+name.GetStringValue()] = true
+		}
+	}
+
+	appliedLock.Lock()
+	defer appliedLock.Unlock()
+
+	for key, c := range appliedDynamicConfigs {
+		if dispatched[key] {
+			continue
+		}
+
+		api.LogInfof("purge dynamic config %s, namespace: %s, version: %s", c.name, c.namespace, c.version)
+		delete(appliedDynamicConfigs, key)
+		if rootApplied[c.name] == key {
+			delete(rootApplied, c.name)
+		}
+		updateSeq.Add(1)
+	}
+	// the DynamicConfig which fails to apply also has a status
+	removeStatuses(func(key string) bool {
+		return !dispatched[key]
+	})
 }
 
 func (p *DynamicConfigParser) Merge(parent interface{}, child interface{}) interface{} {
@@ -125,13 +231,46 @@ type DynamicConfigHandler interface {
 	OnUpdate(config any) error
 }
 
+// DynamicConfigUpdate describes an update of the DynamicConfig. The same type of DynamicConfig
+// from different namespaces are updated separately.
+type DynamicConfigUpdate struct {
+	// Namespace is the namespace of the DynamicConfig
+	Namespace string
+	// Version is the version of the new config
+	Version string
+	// Rollback is true if the new config is rolled back to a prior version
+	Rollback bool
+	// Config is the new config
+	Config DynamicConfig
+	// Previous is the config applied successfully last time. It is nil if there is no one.
+	Previous DynamicConfig
+	// PreviousVersion is the version of the previous config
+	PreviousVersion string
+}
+
+// DynamicConfigValidator can be implemented by the DynamicConfigHandler to validate the update
+// against the previous config before committing it. The update is rejected if an error is returned.
+type DynamicConfigValidator interface {
+	ValidateUpdate(update *DynamicConfigUpdate) error
+}
+
+// DynamicConfigUpdater can be implemented by the DynamicConfigHandler to receive the previous config
+// and the new config together. If it is implemented, OnDynamicConfigUpdate is called instead of OnUpdate.
+type DynamicConfigUpdater interface {
+	OnDynamicConfigUpdate(update *DynamicConfigUpdate) error
+}
+
 // We extra RegisterDynamicConfigProvider out of RegisterDynamicConfigHandler, so that
 // the control plane can register the definition of the DynamicConfigHandler, and only the
 // data plane needs to know the implementation. Of course, you can also call
 // RegisterDynamicConfigHandler only, which is more convenient for the developer.
 
 func RegisterDynamicConfigProvider(name string, c DynamicConfigProvider) {
-	if _, ok := dynamicConfigHandlers[name]; !ok {
+	_, ok := dynamicConfigHandlers[name]
+	if !ok {
+		_, ok = dynamicConfigHandlerFactories[name]
+	}
+	if !ok {
 		// As RegisterDynamicConfigHandler also calls RegisterDynamicConfigProvider, we only log for the first time.
 		// Otherwise, we will log twice for the load in the data plane.
 		logger.Info("register dynamic config provider", "name", name)
@@ -150,3 +289,36 @@ func RegisterDynamicConfigHandler(name string, c DynamicConfigHandler) {
 	// We don't force developer to divide their dynamic configs into two parts for better DX.
 	RegisterDynamicConfigProvider(name, c)
 }
+
+// DynamicConfigHandlerFactory creates the DynamicConfigHandler for the DynamicConfig from the given namespace
+type DynamicConfigHandlerFactory func(namespace string) DynamicConfigHandler
+
+// RegisterNamespacedDynamicConfigHandler registers a factory to create a DynamicConfigHandler per namespace,
+// so that the same type of DynamicConfig from different namespaces are handled by different handlers.
+// The handler is created when the DynamicConfig from the namespace is received for the first time.
+// It takes precedence over the handler registered via RegisterDynamicConfigHandler with the same name.
+func RegisterNamespacedDynamicConfigHandler(name string, provider DynamicConfigProvider, factory DynamicConfigHandlerFactory) {
+	logger.Info("register namespaced dynamic config handler", "name", name)
+
+	dynamicConfigHandlerFactories[name] = factory
+	RegisterDynamicConfigProvider(name, provider)
+}
+
+func loadDynamicConfigHandler(name string, namespace string) (DynamicConfigHandler, bool) {
+	factory, ok := dynamicConfigHandlerFactories[name]
+	if !ok {
+		cb, ok := dynamicConfigHandlers[name]
+		return cb, ok
+	}
+
+	key := namespace + "/" + name
+	namespacedHandlersLock.Lock()
+	defer namespacedHandlersLock.Unlock()
+
+	cb, ok := namespacedHandlers[key]
+	if !ok {
+		cb = factory(namespace)
+		namespacedHandlers[key] = cb
+	}
+	return cb, true
+}
@@ -15,10 +15,19 @@
 package dynamicconfig
 
 import (
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	xds "github.com/cncf/xds/go/xds/type/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -84,3 +93,202 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+type testConfig struct {
+	*structpb.Struct
+}
+
+func (c *testConfig) Validate() error {
+	if c.Fields["invalid"] != nil {
+		return errors.New("invalid field")
+	}
+	return nil
+}
+
+type testHandler struct {
+}
+
+func (h *testHandler) Config() DynamicConfig {
+	return &testConfig{Struct: &structpb.Struct{}}
+}
+
+func (h *testHandler) OnUpdate(config any) error {
+	c := config.(*testConfig)
+	if c.Fields["reject"] != nil {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func TestApplyStatus(t *testing.T) {
+	reports := make(chan *StatusReport, 10)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var report StatusReport
+		_ = json.NewDecoder(r.Body).Decode(&report)
+		reports <- &report
+	}))
+	defer srv.Close()
+	t.Setenv(EnvStatusReportURL, srv.URL)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token\n"), 0644))
+	t.Setenv(EnvStatusReportTokenFile, tokenFile)
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644))
+	t.Setenv(EnvStatusReportCAFile, caFile)
+	t.Setenv("POD_NAMESPACE", "istio-system")
+	t.Setenv("POD_NAME", "gateway")
+
+	RegisterDynamicConfigHandler("status_test", &testHandler{})
+
+	parse := func(version string, cfg map[string]interface{}) error {
+		ts := xds.TypedStruct{}
+		ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+			"name":      "status_test",
+			"namespace": "default",
+			"version":   version,
+			"config":    cfg,
+		})
+		parser := &DynamicConfigParser{}
+		_, err := parser.Parse(proto.MessageToAny(&ts), nil)
+		return err
+	}
+
+	assert.Nil(t, parse("1", map[string]interface{}{}))
+	sts := GetApplyStatuses()
+	assert.Equal(t, 1, len(sts))
+	assert.Equal(t, "default", sts[0].Namespace)
+	assert.Equal(t, "status_test", sts[0].Name)
+	assert.Equal(t, "1", sts[0].Version)
+	assert.Equal(t, "", sts[0].Error)
+
+	select {
+	case report := <-reports:
+		assert.Equal(t, "istio-system/gateway", report.Proxy)
+		assert.Equal(t, 1, len(report.Statuses))
+		assert.Equal(t, "1", report.Statuses[0].Version)
+	case <-time.After(3 * time.Second):
+		t.Fatal("status is not reported")
+	}
+
+	assert.NotNil(t, parse("2", map[string]interface{}{"invalid": true}))
+	sts = GetApplyStatuses()
+	assert.Equal(t, "2", sts[0].Version)
+	assert.Equal(t, "invalid field", sts[0].Error)
+
+	assert.NotNil(t, parse("3", map[string]interface{}{"reject": true}))
+	sts = GetApplyStatuses()
+	assert.Equal(t, "3", sts[0].Version)
+	assert.Equal(t, "rejected", sts[0].Error)
+
+	select {
+	case report := <-reports:
+		assert.Equal(t, "status_test", report.Statuses[0].Name)
+		assert.NotEqual(t, "", report.Statuses[0].Error)
+	case <-time.After(3 * time.Second):
+		t.Fatal("status is not reported")
+	}
+
+	// the token is not sent in plaintext
+	assert.ErrorContains(t, reportStatus(http.DefaultClient, "http://127.0.0.1:15014", &StatusReport{}), "only HTTPS URL is allowed")
+}
+
+type versionedHandler struct {
+	testHandler
+
+	updates []*DynamicConfigUpdate
+}
+
+func (h *versionedHandler) ValidateUpdate(update *DynamicConfigUpdate) error {
+	if update.Config.(*testConfig).Fields["downgrade"] != nil && !update.Rollback {
+		return errors.New("downgrade is only allowed in rollback")
+	}
+	return nil
+}
+
+func (h *versionedHandler) OnDynamicConfigUpdate(update *DynamicConfigUpdate) error {
+	h.updates = append(h.updates, update)
+	return nil
+}
+
+func TestVersionedHandler(t *testing.T) {
+	h := &versionedHandler{}
+	RegisterDynamicConfigHandler("versioned_test", h)
+
+	parse := func(ns, version string, rollback bool, cfg map[string]interface{}) error {
+		ts := xds.TypedStruct{}
+		ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+			"name":      "versioned_test",
+			"namespace": ns,
+			"version":   version,
+			"rollback":  rollback,
+			"config":    cfg,
+		})
+		parser := &DynamicConfigParser{}
+		_, err := parser.Parse(proto.MessageToAny(&ts), nil)
+		return err
+	}
+
+	assert.Nil(t, parse("ns", "v1", false, map[string]interface{}{"a": 1}))
+	assert.Equal(t, 1, len(h.updates))
+	u := h.updates[0]
+	assert.Equal(t, "ns", u.Namespace)
+	assert.Equal(t, "v1", u.Version)
+	assert.Nil(t, u.Previous)
+	assert.Equal(t, "", u.PreviousVersion)
+
+	// namespaces are isolated
+	assert.Nil(t, parse("other", "v1", false, map[string]interface{}{"b": 1}))
+	assert.Nil(t, h.updates[1].Previous)
+
+	assert.Nil(t, parse("ns", "v2", false, map[string]interface{}{"a": 2}))
+	u = h.updates[2]
+	assert.Equal(t, "v1", u.PreviousVersion)
+	assert.Equal(t, float64(1), u.Previous.(*testConfig).Fields["a"].GetNumberValue())
+	assert.Equal(t, float64(2), u.Config.(*testConfig).Fields["a"].GetNumberValue())
+
+	// rejected by ValidateUpdate, the previous one is kept
+	err := parse("ns", "v3", false, map[string]interface{}{"downgrade": true})
+	assert.ErrorContains(t, err, "downgrade is only allowed in rollback")
+	assert.Equal(t, 3, len(h.updates))
+	// roll back to v1
+	assert.Nil(t, parse("ns", "v1", true, map[string]interface{}{"downgrade": true}))
+	u = h.updates[3]
+	assert.True(t, u.Rollback)
+	assert.Equal(t, "v2", u.PreviousVersion)
+}
+
+func TestNamespacedHandler(t *testing.T) {
+	handlers := map[string]*versionedHandler{}
+	RegisterNamespacedDynamicConfigHandler("namespaced_test", &testHandler{}, func(namespace string) DynamicConfigHandler {
+		h := &versionedHandler{}
+		handlers[namespace] = h
+		return h
+	})
+
+	parse := func(ns string, cfg map[string]interface{}) error {
+		ts := xds.TypedStruct{}
+		ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+			"name":      "namespaced_test",
+			"namespace": ns,
+			"version":   "v1",
+			"config":    cfg,
+		})
+		parser := &DynamicConfigParser{}
+		_, err := parser.Parse(proto.MessageToAny(&ts), nil)
+		return err
+	}
+
+	assert.Nil(t, parse("ns", map[string]interface{}{"a": 1}))
+	assert.Nil(t, parse("other", map[string]interface{}{"b": 1}))
+	assert.Nil(t, parse("ns", map[string]interface{}{"a": 2}))
+
+	require.Equal(t, 2, len(handlers))
+	assert.Equal(t, 2, len(handlers["ns"].updates))
+	assert.Equal(t, float64(2), handlers["ns"].updates[1].Config.(*testConfig).Fields["a"].GetNumberValue())
+	require.Equal(t, 1, len(handlers["other"].updates))
+	assert.Equal(t, "other", handlers["other"].updates[0].Namespace)
+}
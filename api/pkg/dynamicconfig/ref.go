@@ -0,0 +1,92 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamicconfig
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// updateSeq is increased each time a DynamicConfig is applied or purged
+	updateSeq atomic.Uint64
+	// the key of the DynamicConfig applied from the root namespace, by name
+	rootApplied = map[string]string{}
+)
+
+// GetDynamicConfig returns the DynamicConfig with the given name applied in the given namespace.
+// If there is no such one in the namespace, the one from the root namespace is returned, as the
+// DynamicConfig in the root namespace takes effect for all data planes. DynamicConfigs from other
+// namespaces are never returned. It returns nil if the DynamicConfig is not applied yet.
+func GetDynamicConfig(namespace, name string) DynamicConfig {
+	appliedLock.Lock()
+	defer appliedLock.Unlock()
+
+	if c, ok := appliedDynamicConfigs[namespace+"/"+name]; ok {
+		return c.config
+	}
+	if key, ok := rootApplied[name]; ok {
+		return appliedDynamicConfigs[key].config
+	}
+	return nil
+}
+
+// Ref refers a DynamicConfig from the plugin configuration
+type Ref struct {
+	namespace string
+	name      string
+	onUpdate  func(config DynamicConfig)
+
+	lock     sync.Mutex
+	seen     atomic.Uint64
+	resolved DynamicConfig
+}
+
+// NewRef creates a Ref to the DynamicConfig with the given name. The onUpdate is called in Refresh
+// when the resolved DynamicConfig is changed.
+func NewRef(namespace, name string, onUpdate func(config DynamicConfig)) *Ref {
+	return &Ref{
+		namespace: namespace,
+		name:      name,
+		onUpdate:  onUpdate,
+	}
+}
+
+func (r *Ref) Name() string {
+	return r.name
+}
+
+// Refresh resolves the DynamicConfig again if any DynamicConfig is applied since the last call.
+// It is cheap when nothing is changed, so it can be called per request. We don't push the update
+// to the Ref, because the golang filter doesn't tell us when the plugin configuration is destroyed.
+func (r *Ref) Refresh() {
+	seq := updateSeq.Load()
+	if r.seen.Load() == seq {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.seen.Load() == seq {
+		return
+	}
+	conf := GetDynamicConfig(r.namespace, r.name)
+	if conf != r.resolved {
+		r.resolved = conf
+		r.onUpdate(conf)
+	}
+	r.seen.Store(seq)
+}
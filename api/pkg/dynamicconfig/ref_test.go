@@ -0,0 +1,114 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamicconfig
+
+import (
+	"testing"
+
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"mosn.io/htnn/api/internal/proto"
+)
+
+func TestRef(t *testing.T) {
+	RegisterDynamicConfigHandler("ref_test", &testHandler{})
+	parse := func(ns string, cfg map[string]interface{}) {
+		ts := xds.TypedStruct{}
+		ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+			"name":      "ref_test",
+			"namespace": ns,
+			"root":      ns == "root",
+			"config":    cfg,
+		})
+		parser := &DynamicConfigParser{}
+		_, err := parser.Parse(proto.MessageToAny(&ts), nil)
+		assert.Nil(t, err)
+	}
+	purge := func(index map[string]interface{}) {
+		ts := xds.TypedStruct{}
+		ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+			"index": index,
+		})
+		parser := &DynamicConfigParser{}
+		_, err := parser.Parse(proto.MessageToAny(&ts), nil)
+		assert.Nil(t, err)
+	}
+
+	var received []DynamicConfig
+	ref := NewRef("ns", "ref_test", func(config DynamicConfig) {
+		received = append(received, config)
+	})
+	assert.Equal(t, "ref_test", ref.Name())
+
+	ref.Refresh()
+	assert.Equal(t, 0, len(received))
+	assert.Nil(t, GetDynamicConfig("ns", "ref_test"))
+
+	// the config from other tenant is invisible
+	parse("tenant", map[string]interface{}{"from": "tenant"})
+	ref.Refresh()
+	assert.Equal(t, 0, len(received))
+	assert.Nil(t, GetDynamicConfig("ns", "ref_test"))
+
+	// fallback to the root namespace
+	parse("root", map[string]interface{}{"from": "root"})
+	ref.Refresh()
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, "root", received[0].(*testConfig).Fields["from"].GetStringValue())
+
+	// nothing changed
+	ref.Refresh()
+	assert.Equal(t, 1, len(received))
+
+	parse("ns", map[string]interface{}{"from": "ns"})
+	ref.Refresh()
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, "ns", received[1].(*testConfig).Fields["from"].GetStringValue())
+
+	// the config from other namespace doesn't override the one from the same namespace
+	parse("root", map[string]interface{}{"from": "root2"})
+	ref.Refresh()
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, "root2", GetDynamicConfig("other", "ref_test").(*testConfig).Fields["from"].GetStringValue())
+
+	// the dispatched configs are not purged
+	purge(map[string]interface{}{
+		"root":   []interface{}{"ref_test"},
+		"ns":     []interface{}{"ref_test"},
+		"tenant": []interface{}{"ref_test"},
+	})
+	assert.NotNil(t, GetDynamicConfig("other", "ref_test"))
+	assert.Equal(t, "ns", GetDynamicConfig("ns", "ref_test").(*testConfig).Fields["from"].GetStringValue())
+
+	// the removed config is purged
+	purge(map[string]interface{}{
+		"root":   []interface{}{"ref_test"},
+		"tenant": []interface{}{"ref_test"},
+	})
+	ref.Refresh()
+	assert.Equal(t, 3, len(received))
+	for _, st := range GetApplyStatuses() {
+		assert.False(t, st.Namespace == "ns" && st.Name == "ref_test", "the status should be purged")
+	}
+	assert.Equal(t, "root2", received[2].(*testConfig).Fields["from"].GetStringValue())
+
+	purge(map[string]interface{}{})
+	ref.Refresh()
+	assert.Equal(t, 4, len(received))
+	assert.Nil(t, received[3])
+	assert.Nil(t, GetDynamicConfig("other", "ref_test"))
+}
@@ -0,0 +1,244 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamicconfig
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+)
+
+const (
+	// EnvStatusReportURL is the env variable which contains the HTTPS URL the apply status is reported to.
+	// The status won't be reported if it is not set.
+	EnvStatusReportURL = "HTNN_DYNAMIC_CONFIG_STATUS_URL"
+	// EnvStatusReportTokenFile is the env variable which contains the path of the service account token
+	// used to authenticate the report. The token should be projected with the StatusReportAudience.
+	EnvStatusReportTokenFile = "HTNN_DYNAMIC_CONFIG_STATUS_TOKEN_FILE"
+	// EnvStatusReportCAFile is the env variable which contains the path of the CA certificates used to
+	// verify the control plane. Default to the system's CA certificates.
+	EnvStatusReportCAFile = "HTNN_DYNAMIC_CONFIG_STATUS_CA_FILE"
+
+	// StatusReportAudience is the audience of the token used to authenticate the report, so that
+	// the token can't be replayed to the Kubernetes API server and vice versa.
+	StatusReportAudience = "htnn-dynamicconfig-status"
+
+	defaultStatusReportTokenFile = "/var/run/secrets/htnn/dynamicconfig-status/token"
+
+	// StatusReportInterval is the interval to resend the apply status, so that the control plane
+	// can find out the proxies which are gone and recover from the lost reports.
+	StatusReportInterval = 30 * time.Second
+)
+
+// ApplyStatus is the result of applying a DynamicConfig in the data plane
+type ApplyStatus struct {
+	// Namespace is the namespace of the DynamicConfig
+	Namespace string `json:"namespace"`
+	// Name is the type of the DynamicConfig
+	Name string `json:"name"`
+	// Version is the version of the DynamicConfig which is received
+	Version string `json:"version,omitempty"`
+	// Error is the reason why the DynamicConfig can't be applied. Empty if it is applied successfully.
+	Error string `json:"error,omitempty"`
+	// UpdatedAt is the time when the DynamicConfig is received
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// StatusReport is the payload reported to the control plane
+type StatusReport struct {
+	// Proxy identifies the reporter, in the format of "namespace/name"
+	Proxy    string        `json:"proxy"`
+	Statuses []ApplyStatus `json:"statuses"`
+}
+
+var (
+	statusLock sync.Mutex
+	statuses   = map[string]ApplyStatus{}
+
+	reporterOnce sync.Once
+	reportNotify = make(chan struct{}, 1)
+)
+
+// removeStatuses removes the statuses whose namespace/name key matches
+func removeStatuses(match func(key string) bool) {
+	statusLock.Lock()
+	for key := range statuses {
+		if match(key) {
+			delete(statuses, key)
+		}
+	}
+	statusLock.Unlock()
+}
+
+func recordStatus(ns, name, version string, err error) {
+	st := ApplyStatus{
+		Namespace: ns,
+		Name:      name,
+		Version:   version,
+		UpdatedAt: time.Now(),
+	}
+	if err != nil {
+		st.Error = err.Error()
+	}
+
+	statusLock.Lock()
+	statuses[ns+"/"+name] = st
+	statusLock.Unlock()
+
+	if os.Getenv(EnvStatusReportURL) == "" {
+		return
+	}
+	reporterOnce.Do(func() {
+		go runReporter()
+	})
+	select {
+	case reportNotify <- struct{}{}:
+	default:
+		// a report is already pending
+	}
+}
+
+// GetApplyStatuses returns the apply status of the received DynamicConfigs, sorted by namespace and name
+func GetApplyStatuses() []ApplyStatus {
+	statusLock.Lock()
+	res := make([]ApplyStatus, 0, len(statuses))
+	for _, st := range statuses {
+		res = append(res, st)
+	}
+	statusLock.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Namespace != res[j].Namespace {
+			return res[i].Namespace < res[j].Namespace
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+func proxyID() string {
+	name := os.Getenv("POD_NAME")
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	return os.Getenv("POD_NAMESPACE") + "/" + name
+}
+
+func newReportClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if file := os.Getenv(EnvStatusReportCAFile); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no CA certificate found in %s", file)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+func runReporter() {
+	ticker := time.NewTicker(StatusReportInterval)
+	defer ticker.Stop()
+
+	var client *http.Client
+	for {
+		select {
+		case <-reportNotify:
+		case <-ticker.C:
+		}
+
+		var err error
+		if client == nil {
+			// retry in the next round if the CA certificates are not ready
+			client, err = newReportClient()
+			if err != nil {
+				api.LogErrorf("failed to create dynamic config status reporter: %v", err)
+				continue
+			}
+		}
+
+		url := os.Getenv(EnvStatusReportURL)
+		err = reportStatus(client, url, &StatusReport{
+			Proxy:    proxyID(),
+			Statuses: GetApplyStatuses(),
+		})
+		if err != nil {
+			api.LogWarnf("failed to report dynamic config status to %s: %v", url, err)
+		}
+	}
+}
+
+func reportToken() (string, error) {
+	file := os.Getenv(EnvStatusReportTokenFile)
+	if file == "" {
+		file = defaultStatusReportTokenFile
+	}
+	// read the token every time as it is rotated
+	token, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+func reportStatus(client *http.Client, url string, report *StatusReport) error {
+	// the token must not be sent in plaintext
+	if !strings.HasPrefix(url, "https://") {
+		return errors.New("only HTTPS URL is allowed")
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	token, err := reportToken()
+	if err != nil {
+		return fmt.Errorf("failed to read token: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
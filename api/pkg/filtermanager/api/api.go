@@ -190,9 +190,20 @@ type PluginConsumerConfig interface {
 	Index() string
 }
 
+// PluginConsumerMultiIndexConfig is the optional interface implemented by the PluginConsumerConfig
+// which carries multiple credentials, for example, to rotate the key without downtime.
+// When it's implemented, the consumer is indexed by each of the values returned from Indexes instead of Index.
+type PluginConsumerMultiIndexConfig interface {
+	PluginConsumerConfig
+	Indexes() []string
+}
+
 type Consumer interface {
 	Name() string
 	PluginConfig(name string) PluginConsumerConfig
+	// Metadata returns the free-form attributes of the consumer, like tenant, tier and org.
+	// The returned map should not be modified.
+	Metadata() map[string]string
 }
 
 // StreamFilterCallbacks provides API that is used during request processing
@@ -26,6 +26,7 @@ import (
 	capi "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	"google.golang.org/protobuf/types/known/anypb"
 
+	"mosn.io/htnn/api/pkg/dynamicconfig"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/api/pkg/filtermanager/model"
 	pkgPlugins "mosn.io/htnn/api/pkg/plugins"
@@ -60,6 +61,11 @@ type filterManagerConfig struct {
 	namespace string
 
 	enableDebugMode bool
+	// the name of the consumer to use when no consumer is set by the Consumer plugins
+	anonymousConsumer string
+	authnMode         pkgPlugins.AuthnMode
+	// the map from the consumer metadata key to the request header name
+	consumerMetadataHeaders map[string]string
 }
 
 func initFilterManagerConfig(namespace string) *filterManagerConfig {
@@ -106,6 +112,19 @@ func (conf *filterManagerConfig) Merge(another *filterManagerConfig) *filterMana
 		cp.enableDebugMode = true
 	}
 
+	cp.anonymousConsumer = conf.anonymousConsumer
+	if cp.anonymousConsumer == "" {
+		cp.anonymousConsumer = another.anonymousConsumer
+	}
+	cp.authnMode = conf.authnMode
+	if cp.authnMode == pkgPlugins.AuthnModeDefault {
+		cp.authnMode = another.authnMode
+	}
+	cp.consumerMetadataHeaders = conf.consumerMetadataHeaders
+	if cp.consumerMetadataHeaders == nil {
+		cp.consumerMetadataHeaders = another.consumerMetadataHeaders
+	}
+
 	cp.parsed = make([]*model.ParsedFilterConfig, 0, len(conf.parsed)+len(another.parsed))
 	// For now, we don't deepcopy the config. The config may contain connection to the external
 	// service, for example, a Redis cluster. Not sure if it is safe to deepcopy them. So far,
@@ -135,12 +154,9 @@ func (conf *filterManagerConfig) Merge(another *filterManagerConfig) *filterMana
 	})
 
 	// recompute fields which will be different after merging
-	cp.consumerFiltersEndAt = len(cp.parsed)
 	for i, fc := range cp.parsed {
-		_, ok := pkgPlugins.LoadPlugin(fc.Name).(pkgPlugins.ConsumerPlugin)
-		if !ok {
-			cp.consumerFiltersEndAt = i
-			break
+		if isConsumerFilter(fc) {
+			cp.consumerFiltersEndAt = i + 1
 		}
 	}
 
@@ -153,6 +169,26 @@ func (conf *filterManagerConfig) Merge(another *filterManagerConfig) *filterMana
 	return cp
 }
 
+// isConsumerFilter returns true if the filter runs before the consumer is finally determined
+func isConsumerFilter(fc *model.ParsedFilterConfig) bool {
+	if _, ok := pkgPlugins.LoadPlugin(fc.Name).(pkgPlugins.ConsumerPlugin); ok {
+		return true
+	}
+	_, ok := fc.ParsedConfig.(pkgPlugins.AnonymousConsumerConfig)
+	return ok
+}
+
+func newDynamicConfigRefs(namespace string, sub pkgPlugins.DynamicConfigSubscriber) []*dynamicconfig.Ref {
+	names := sub.DynamicConfigRefs()
+	refs := make([]*dynamicconfig.Ref, 0, len(names))
+	for _, name := range names {
+		refs = append(refs, dynamicconfig.NewRef(namespace, name, func(config dynamicconfig.DynamicConfig) {
+			sub.OnDynamicConfigUpdate(name, config)
+		}))
+	}
+	return refs
+}
+
 func (conf *filterManagerConfig) InitOnce() {
 	if conf.initOnce == nil {
 		return
@@ -238,10 +274,22 @@ func (p *FilterManagerConfigParser) Parse(any *anypb.Any, callbacks capi.ConfigC
 					SyncRunPhases: plugin.ConfigParser.NonBlockingPhases(),
 				})
 
-				_, ok := pkgPlugins.LoadPlugin(name).(pkgPlugins.ConsumerPlugin)
-				if ok {
+				fc := conf.parsed[len(conf.parsed)-1]
+				if isConsumerFilter(fc) {
 					consumerFiltersEndAt = i + 1
 				}
+				if ac, ok := config.(pkgPlugins.AnonymousConsumerConfig); ok {
+					conf.anonymousConsumer = ac.AnonymousConsumer()
+				}
+				if ap, ok := config.(pkgPlugins.AuthnPolicyConfig); ok {
+					conf.authnMode = ap.AuthnMode()
+				}
+				if mh, ok := config.(pkgPlugins.ConsumerMetadataHeadersConfig); ok {
+					conf.consumerMetadataHeaders = mh.ConsumerMetadataHeaders()
+				}
+				if sub, ok := config.(pkgPlugins.DynamicConfigSubscriber); ok {
+					fc.DynamicConfigRefs = newDynamicConfigRefs(fmConfig.Namespace, sub)
+				}
 
 				if parser, ok := config.(pkgPlugins.Parser); ok {
 					// For now, we have nothing to provide as config callbacks
@@ -167,6 +167,9 @@ func FilterManagerFactory(c interface{}, cb capi.FilterCallbackHandler) (streamF
 	for i, fc := range parsedConfig {
 		factory := fc.Factory
 		config := fc.ParsedConfig
+		for _, ref := range fc.DynamicConfigRefs {
+			ref.Refresh()
+		}
 		f := factory(config, fm.callbacks)
 		// Technically, the factory might create different f for different calls. We don't support this edge case for now.
 		if fm.canSkipMethods == nil {
@@ -229,7 +232,10 @@ func FilterManagerFactory(c interface{}, cb capi.FilterCallbackHandler) (streamF
 
 	// The skip check is based on the compiled code. So if the DecodeRequest is defined,
 	// even it is not called, DecodeData will not be skipped. Same as EncodeResponse.
-	fm.canSkipDecodeHeaders = fm.canSkipMethods["DecodeHeaders"] && fm.canSkipMethods["DecodeRequest"] && fm.config.initOnce == nil
+	// The consumer metadata headers from the client must be removed in DecodeHeaders, otherwise they
+	// are forwarded to the upstream as if they are set by us.
+	fm.canSkipDecodeHeaders = fm.canSkipMethods["DecodeHeaders"] && fm.canSkipMethods["DecodeRequest"] && fm.config.initOnce == nil &&
+		len(fm.config.consumerMetadataHeaders) == 0
 	fm.canSkipDecodeData = fm.canSkipMethods["DecodeData"] && fm.canSkipMethods["DecodeRequest"]
 	fm.canSkipDecodeTrailers = fm.canSkipMethods["DecodeTrailers"] && fm.canSkipMethods["DecodeRequest"]
 	fm.canSkipEncodeHeaders = fm.canSkipMethods["EncodeHeaders"]
@@ -386,6 +392,67 @@ func (m *filterManager) DecodeHeaders(headers capi.RequestHeaderMap, endStream b
 	return capi.Running
 }
 
+// runConsumerFiltersWithAuthnMode runs the filters before the consumer is determined, and combines
+// the results of the Consumer plugins according to the authn mode.
+func (m *filterManager) runConsumerFiltersWithAuthnMode(endStream bool) (needReturn bool) {
+	var firstFailure *api.LocalResponse
+	var firstFailureFilter *model.FilterWrapper
+	for i := 0; i < m.config.consumerFiltersEndAt; i++ {
+		f := m.filters[i]
+		if _, ok := pkgPlugins.LoadPlugin(f.Name).(pkgPlugins.ConsumerPlugin); !ok {
+			res := f.DecodeHeaders(m.reqHdr, endStream)
+			if m.handleAction(res, api.PhaseDecodeHeaders, f) {
+				return true
+			}
+			continue
+		}
+
+		if m.config.authnMode == pkgPlugins.AuthnModeAny {
+			if m.callbacks.consumer != nil {
+				// already authenticated by the previous Consumer plugin
+				continue
+			}
+
+			res := f.DecodeHeaders(m.reqHdr, endStream)
+			if lr, ok := res.(*api.LocalResponse); ok {
+				api.LogInfof("authn plugin %s failed with %d, try the next one", f.Name, lr.Code)
+				if firstFailure == nil {
+					firstFailure = lr
+					firstFailureFilter = f
+				}
+				continue
+			}
+			if m.handleAction(res, api.PhaseDecodeHeaders, f) {
+				return true
+			}
+			continue
+		}
+
+		// AuthnModeAll
+		prev := m.callbacks.consumer
+		m.callbacks.consumer = nil
+		res := f.DecodeHeaders(m.reqHdr, endStream)
+		if m.handleAction(res, api.PhaseDecodeHeaders, f) {
+			return true
+		}
+		cur := m.callbacks.consumer
+		if cur == nil {
+			api.LogInfof("authn plugin %s doesn't set consumer", f.Name)
+			return m.handleAction(&api.LocalResponse{Code: 401, Msg: "missing credential"}, api.PhaseDecodeHeaders, f)
+		}
+		if prev != nil && prev.Name() != cur.Name() {
+			api.LogInfof("authn plugin %s sets consumer %s, which is different from consumer %s",
+				f.Name, cur.Name(), prev.Name())
+			return m.handleAction(&api.LocalResponse{Code: 401, Msg: "consumer mismatch"}, api.PhaseDecodeHeaders, f)
+		}
+	}
+
+	if firstFailure != nil && m.callbacks.consumer == nil {
+		return m.handleAction(firstFailure, api.PhaseDecodeHeaders, firstFailureFilter)
+	}
+	return false
+}
+
 func (m *filterManager) decodeHeaders(headers capi.RequestHeaderMap, endStream bool) capi.StatusType {
 	var res api.ResultAction
 
@@ -406,19 +473,44 @@ func (m *filterManager) decodeHeaders(headers capi.RequestHeaderMap, endStream b
 		}
 	}
 	m.hdrLock.Unlock()
+	for _, header := range m.config.consumerMetadataHeaders {
+		// remove the header from the client so that the upstream can trust it, even if
+		// the request is not authenticated
+		m.reqHdr.Del(header)
+	}
 	if m.config.consumerFiltersEndAt != 0 {
-		for i := 0; i < m.config.consumerFiltersEndAt; i++ {
-			f := m.filters[i]
-			// We don't support DecodeRequest for now
-			res = f.DecodeHeaders(m.reqHdr, endStream)
-			if m.handleAction(res, api.PhaseDecodeHeaders, f) {
-				return capi.LocalReply
+		if m.config.authnMode == pkgPlugins.AuthnModeDefault {
+			for i := 0; i < m.config.consumerFiltersEndAt; i++ {
+				f := m.filters[i]
+				// We don't support DecodeRequest for now
+				res = f.DecodeHeaders(m.reqHdr, endStream)
+				if m.handleAction(res, api.PhaseDecodeHeaders, f) {
+					return capi.LocalReply
+				}
 			}
+		} else if m.runConsumerFiltersWithAuthnMode(endStream) {
+			return capi.LocalReply
 		}
 
 		// we check consumer at the end of authn filters, so we can have multiple authn filters
 		// configured and the consumer will be set by any of them
+		if m.callbacks.consumer == nil && m.config.anonymousConsumer != "" {
+			ac, ok := consumer.GetConsumer(m.config.namespace, m.config.anonymousConsumer)
+			if ok {
+				m.callbacks.SetConsumer(ac)
+			} else {
+				api.LogWarnf("anonymous consumer %s not found, namespace: %s",
+					m.config.anonymousConsumer, m.config.namespace)
+			}
+		}
 		c, ok := m.callbacks.consumer.(*consumer.Consumer)
+		if ok {
+			for key, header := range m.config.consumerMetadataHeaders {
+				if v, found := c.Consumer.Metadata[key]; found {
+					m.reqHdr.Set(header, v)
+				}
+			}
+		}
 		if ok && len(c.FilterConfigs) > 0 {
 			api.LogDebugf("merge filters from consumer: %s", c.Name())
 
@@ -23,12 +23,18 @@ import (
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
+	xds "github.com/cncf/xds/go/xds/type/v3"
 	capi "github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	internalConsumer "mosn.io/htnn/api/internal/consumer"
+	"mosn.io/htnn/api/internal/proto"
+	csModel "mosn.io/htnn/api/pkg/consumer/model"
+	"mosn.io/htnn/api/pkg/dynamicconfig"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/api/pkg/filtermanager/model"
+	pkgPlugins "mosn.io/htnn/api/pkg/plugins"
 	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
 )
 
@@ -433,8 +439,10 @@ type setConsumerFilter struct {
 
 func (f *setConsumerFilter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api.ResultAction {
 	key, _ := headers.Get("Consumer")
-	c := f.conf.Consumers[key]
-	f.callbacks.SetConsumer(c)
+	c, ok := f.conf.Consumers[key]
+	if ok {
+		f.callbacks.SetConsumer(c)
+	}
 	return api.Continue
 }
 
@@ -526,6 +534,290 @@ func TestFiltersFromConsumer(t *testing.T) {
 	wg.Wait()
 }
 
+func TestConsumerMetadataHeaders(t *testing.T) {
+	consumers := map[string]*internalConsumer.Consumer{
+		"0": {
+			Consumer: csModel.Consumer{
+				Metadata: map[string]string{
+					"tenant": "t1",
+					"tier":   "gold",
+				},
+			},
+		},
+		"1": {},
+	}
+
+	tests := []struct {
+		name     string
+		consumer string
+		tenant   []string
+	}{
+		{
+			name:     "authenticated",
+			consumer: "0",
+			tenant:   []string{"t1"},
+		},
+		{
+			name:     "metadata not found",
+			consumer: "1",
+		},
+		{
+			name: "unauthenticated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := initFilterManagerConfig("ns")
+			config.consumerFiltersEndAt = 1
+			config.consumerMetadataHeaders = map[string]string{
+				"tenant": "x-consumer-tenant",
+			}
+			config.parsed = []*model.ParsedFilterConfig{
+				{
+					Name:    "1_set_consumer",
+					Factory: setConsumerFactory,
+					ParsedConfig: setConsumerConf{
+						Consumers: consumers,
+					},
+				},
+			}
+
+			cb := envoy.NewCAPIFilterCallbackHandler()
+			m := unwrapFilterManager(FilterManagerFactory(config, cb))
+			h := http.Header{}
+			if tt.consumer != "" {
+				h.Add("consumer", tt.consumer)
+			}
+			h.Add("x-consumer-tenant", "forged")
+			h.Add("x-consumer-tier", "forged")
+			hdr := envoy.NewRequestHeaderMap(h)
+			m.DecodeHeaders(hdr, true)
+			cb.WaitContinued()
+
+			assert.Equal(t, tt.tenant, hdr.Values("x-consumer-tenant"))
+			// not in the mapping, left as is
+			assert.Equal(t, []string{"forged"}, hdr.Values("x-consumer-tier"))
+		})
+	}
+}
+
+func TestConsumerMetadataHeadersOnly(t *testing.T) {
+	// the plugin which does nothing in DecodeHeaders is the only one configured
+	config := initFilterManagerConfig("ns")
+	config.consumerMetadataHeaders = map[string]string{
+		"tenant": "x-consumer-tenant",
+	}
+	config.parsed = []*model.ParsedFilterConfig{
+		{
+			Name: "consumerMetadataHeaders",
+			Factory: func(interface{}, api.FilterCallbackHandler) api.Filter {
+				return &struct{ api.PassThroughFilter }{}
+			},
+		},
+	}
+
+	cb := envoy.NewCAPIFilterCallbackHandler()
+	m := unwrapFilterManager(FilterManagerFactory(config, cb))
+	h := http.Header{}
+	h.Add("x-consumer-tenant", "forged")
+	hdr := envoy.NewRequestHeaderMap(h)
+	if m.DecodeHeaders(hdr, true) == capi.Running {
+		cb.WaitContinued()
+	}
+
+	assert.Empty(t, hdr.Values("x-consumer-tenant"))
+}
+
+func TestAnonymousConsumer(t *testing.T) {
+	st, _ := structpb.NewStruct(map[string]interface{}{
+		"ns": map[string]interface{}{
+			"anonymous": map[string]interface{}{
+				"d": `{"auth":{}}`,
+				"v": 1,
+			},
+		},
+	})
+	internalConsumer.UpdateConsumers(st)
+
+	for _, name := range []string{"anonymous", "not_found"} {
+		config := initFilterManagerConfig("ns")
+		config.consumerFiltersEndAt = 1
+		config.anonymousConsumer = name
+		config.parsed = []*model.ParsedFilterConfig{
+			{
+				Name:    "passthrough",
+				Factory: PassThroughFactory,
+			},
+		}
+
+		cb := envoy.NewCAPIFilterCallbackHandler()
+		m := unwrapFilterManager(FilterManagerFactory(config, cb))
+		hdr := envoy.NewRequestHeaderMap(http.Header{})
+		m.DecodeHeaders(hdr, true)
+		cb.WaitContinued()
+
+		if name == "anonymous" {
+			assert.Equal(t, "anonymous", m.callbacks.GetConsumer().Name())
+		} else {
+			assert.Nil(t, m.callbacks.GetConsumer())
+		}
+	}
+}
+
+type namedConsumer struct {
+	name string
+}
+
+func (c *namedConsumer) Name() string {
+	return c.name
+}
+
+func (c *namedConsumer) PluginConfig(_ string) api.PluginConsumerConfig {
+	return nil
+}
+
+func (c *namedConsumer) Metadata() map[string]string {
+	return nil
+}
+
+// authnFilter reads the credential from the header which has the same name as the filter.
+// An empty credential means the credential is missing.
+type authnFilter struct {
+	api.PassThroughFilter
+
+	name      string
+	callbacks api.FilterCallbackHandler
+}
+
+func (f *authnFilter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api.ResultAction {
+	cred, _ := headers.Get(f.name)
+	if cred == "" {
+		return api.Continue
+	}
+	if cred == "bad" {
+		return &api.LocalResponse{Code: 401, Msg: f.name}
+	}
+	f.callbacks.SetConsumer(&namedConsumer{name: cred})
+	return api.Continue
+}
+
+func TestAuthnMode(t *testing.T) {
+	for _, name := range []string{"authn_a", "authn_b"} {
+		pkgPlugins.RegisterPlugin(name, &pkgPlugins.MockConsumerPlugin{})
+	}
+	newAuthnFactory := func(name string) api.FilterFactory {
+		return func(_ interface{}, callbacks api.FilterCallbackHandler) api.Filter {
+			return &authnFilter{name: name, callbacks: callbacks}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mode     pkgPlugins.AuthnMode
+		headers  map[string]string
+		code     int
+		msg      string
+		consumer string
+	}{
+		{
+			name:     "any, first wins",
+			mode:     pkgPlugins.AuthnModeAny,
+			headers:  map[string]string{"authn_a": "alice", "authn_b": "bad"},
+			consumer: "alice",
+		},
+		{
+			name:     "any, fallback to the next",
+			mode:     pkgPlugins.AuthnModeAny,
+			headers:  map[string]string{"authn_a": "bad", "authn_b": "bob"},
+			consumer: "bob",
+		},
+		{
+			name:    "any, all failed",
+			mode:    pkgPlugins.AuthnModeAny,
+			headers: map[string]string{"authn_a": "bad"},
+			code:    401,
+			msg:     "authn_a",
+		},
+		{
+			name:    "any, no credential",
+			mode:    pkgPlugins.AuthnModeAny,
+			headers: map[string]string{},
+		},
+		{
+			name:     "all",
+			mode:     pkgPlugins.AuthnModeAll,
+			headers:  map[string]string{"authn_a": "alice", "authn_b": "alice"},
+			consumer: "alice",
+		},
+		{
+			name:    "all, missing credential",
+			mode:    pkgPlugins.AuthnModeAll,
+			headers: map[string]string{"authn_a": "alice"},
+			code:    401,
+			msg:     "missing credential",
+		},
+		{
+			name:    "all, consumer mismatch",
+			mode:    pkgPlugins.AuthnModeAll,
+			headers: map[string]string{"authn_a": "alice", "authn_b": "bob"},
+			code:    401,
+			msg:     "consumer mismatch",
+		},
+		{
+			name:    "all, failed",
+			mode:    pkgPlugins.AuthnModeAll,
+			headers: map[string]string{"authn_a": "alice", "authn_b": "bad"},
+			code:    401,
+			msg:     "authn_b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := initFilterManagerConfig("ns")
+			config.consumerFiltersEndAt = 2
+			config.authnMode = tt.mode
+			config.parsed = []*model.ParsedFilterConfig{
+				{
+					Name:    "authn_a",
+					Factory: newAuthnFactory("authn_a"),
+				},
+				{
+					Name:    "authn_b",
+					Factory: newAuthnFactory("authn_b"),
+				},
+			}
+
+			cb := envoy.NewCAPIFilterCallbackHandler()
+			m := unwrapFilterManager(FilterManagerFactory(config, cb))
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			hdr := envoy.NewRequestHeaderMap(h)
+			m.DecodeHeaders(hdr, true)
+			res := cb.WaitContinued()
+
+			if tt.code != 0 {
+				assert.Equal(t, capi.LocalReply, res)
+				lr := cb.LocalResponse()
+				assert.Equal(t, tt.code, lr.Code)
+				assert.Equal(t, fmt.Sprintf(`{"msg":"%s"}`, tt.msg), lr.Body)
+				return
+			}
+
+			assert.Equal(t, capi.Continue, res)
+			c := m.callbacks.GetConsumer()
+			if tt.consumer == "" {
+				assert.Nil(t, c)
+			} else {
+				assert.Equal(t, tt.consumer, c.Name())
+			}
+		})
+	}
+}
+
 func accessFieldOnLogFactory(c interface{}, callbacks api.FilterCallbackHandler) api.Filter {
 	return &accessFieldOnLogFilter{
 		cb: callbacks,
@@ -875,3 +1167,81 @@ func TestWaitData(t *testing.T) {
 	res = cb.WaitContinued()
 	assert.Equal(t, capi.StopAndBufferWatermark, res)
 }
+
+type subscriberConfig struct {
+	lock    sync.Mutex
+	updates map[string]any
+}
+
+func (c *subscriberConfig) DynamicConfigRefs() []string {
+	return []string{"sub_test", "sub_test_missing"}
+}
+
+func (c *subscriberConfig) OnDynamicConfigUpdate(name string, config any) {
+	c.lock.Lock()
+	c.updates[name] = config
+	c.lock.Unlock()
+}
+
+type subTestConfig struct {
+	*structpb.Struct
+}
+
+func (c *subTestConfig) Validate() error {
+	return nil
+}
+
+type subTestHandler struct {
+}
+
+func (h *subTestHandler) Config() dynamicconfig.DynamicConfig {
+	return &subTestConfig{Struct: &structpb.Struct{}}
+}
+
+func (h *subTestHandler) OnUpdate(_ any) error {
+	return nil
+}
+
+func TestDynamicConfigSubscriber(t *testing.T) {
+	dynamicconfig.RegisterDynamicConfigHandler("sub_test", &subTestHandler{})
+
+	sub := &subscriberConfig{updates: map[string]any{}}
+	config := initFilterManagerConfig("ns")
+	config.parsed = []*model.ParsedFilterConfig{
+		{
+			Name:              "passthrough",
+			ParsedConfig:      sub,
+			Factory:           PassThroughFactory,
+			DynamicConfigRefs: newDynamicConfigRefs("ns", sub),
+		},
+	}
+
+	cb := envoy.NewCAPIFilterCallbackHandler()
+	FilterManagerFactory(config, cb)
+	assert.Equal(t, 0, len(sub.updates))
+
+	ts := xds.TypedStruct{}
+	ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+		"name":      "sub_test",
+		"namespace": "ns",
+		"config":    map[string]interface{}{"key": "value"},
+	})
+	parser := &dynamicconfig.DynamicConfigParser{}
+	_, err := parser.Parse(proto.MessageToAny(&ts), nil)
+	assert.Nil(t, err)
+
+	FilterManagerFactory(config, cb)
+	assert.Equal(t, 1, len(sub.updates))
+	c := sub.updates["sub_test"].(*subTestConfig)
+	assert.Equal(t, "value", c.Fields["key"].GetStringValue())
+
+	// the applied config is purged once it is removed from the index
+	ts.Value, _ = structpb.NewStruct(map[string]interface{}{
+		"index": map[string]interface{}{},
+	})
+	_, err = parser.Parse(proto.MessageToAny(&ts), nil)
+	assert.Nil(t, err)
+
+	FilterManagerFactory(config, cb)
+	assert.Nil(t, sub.updates["sub_test"])
+}
@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"mosn.io/htnn/api/pkg/dynamicconfig"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 )
 
@@ -36,6 +37,8 @@ type ParsedFilterConfig struct {
 	InitFailure   error
 	Factory       api.FilterFactory
 	SyncRunPhases api.Phase
+	// DynamicConfigRefs are the DynamicConfigs referred by the ParsedConfig
+	DynamicConfigRefs []*dynamicconfig.Ref
 }
 
 type FilterWrapper struct {
@@ -106,3 +106,26 @@ func (m *MockNetworkNativePlugin) Order() PluginOrder {
 }
 
 var _ NativePlugin = &MockNativePlugin{}
+
+type MockWasmPlugin struct {
+	PluginMethodDefaultImpl
+}
+
+func (m *MockWasmPlugin) Config() api.PluginConfig {
+	return &MockPluginConfig{}
+}
+
+func (m *MockWasmPlugin) Order() PluginOrder {
+	return PluginOrder{
+		Position: OrderPositionOuter,
+	}
+}
+
+func (m *MockWasmPlugin) Module() WasmModule {
+	return WasmModule{
+		URL:    "oci://ghcr.io/mosn/htnn-wasm-demo:latest",
+		SHA256: "2c6f0b38e7e8e5f1d4b8a2c3e9f0a1b2c3d4e5f60718293a4b5c6d7e8f901234",
+	}
+}
+
+var _ WasmPlugin = &MockWasmPlugin{}
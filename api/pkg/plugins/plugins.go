@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"runtime/debug"
+	"strings"
 
 	"mosn.io/htnn/api/internal/proto"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
@@ -61,10 +62,13 @@ func LoadHTTPFilterFactoryAndParser(name string) *FilterFactoryAndParser {
 
 const (
 	errNilPlugin                  = "plugin should not be nil"
-	errUnknownPluginType          = "a plugin should be either Go plugin or Native plugin"
+	errUnknownPluginType          = "a plugin should be either Go plugin, Native plugin or Wasm plugin"
 	errInvalidGoPluginOrder       = "invalid plugin order position: Go plugin should not use OrderPositionOuter or OrderPositionInner"
 	errInvalidNativePluginOrder   = "invalid plugin order position: Native plugin should use OrderPositionOuter or OrderPositionInner"
 	errInvalidConsumerPluginOrder = "invalid plugin order position: Consumer plugin should use OrderPositionAuthn"
+	errInvalidWasmPluginOrder     = "invalid plugin order position: Wasm plugin should use OrderPositionOuter or OrderPositionInner"
+	errInvalidWasmModuleURL       = "invalid Wasm module: the URL should start with oci://, http://, https:// or file://"
+	errMissingWasmModuleSHA256    = "invalid Wasm module: SHA256 is required for the OCI and HTTP module"
 )
 
 func RegisterPluginType(name string, plugin Plugin) {
@@ -113,6 +117,11 @@ func RegisterPlugin(name string, plugin Plugin) {
 		default:
 			panic(errInvalidNativePluginOrder)
 		}
+	} else if wasmPlugin, ok := plugin.(WasmPlugin); ok {
+		if order.Position != OrderPositionOuter && order.Position != OrderPositionInner {
+			panic(errInvalidWasmPluginOrder)
+		}
+		validateWasmModule(wasmPlugin.Module())
 	} else {
 		panic(errUnknownPluginType)
 	}
@@ -131,6 +140,20 @@ func RegisterPlugin(name string, plugin Plugin) {
 	RegisterPluginType(name, plugin)
 }
 
+func validateWasmModule(module WasmModule) {
+	url := module.URL
+	switch {
+	case strings.HasPrefix(url, "oci://"), strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "https://"):
+		// The tag of OCI image is mutable, so the checksum is required to pin the module
+		if module.SHA256 == "" {
+			panic(errMissingWasmModuleSHA256)
+		}
+	case strings.HasPrefix(url, "file://"):
+	default:
+		panic(errInvalidWasmModuleURL)
+	}
+}
+
 func LoadPlugin(name string) Plugin {
 	return plugins[name]
 }
@@ -171,6 +171,27 @@ func (p *nativePluginWrapper) Order() PluginOrder {
 	return p.order
 }
 
+type wasmPluginWrapper struct {
+	WasmPlugin
+
+	order  *PluginOrder
+	module *WasmModule
+}
+
+func (p *wasmPluginWrapper) Order() PluginOrder {
+	if p.order != nil {
+		return *p.order
+	}
+	return p.WasmPlugin.Order()
+}
+
+func (p *wasmPluginWrapper) Module() WasmModule {
+	if p.module != nil {
+		return *p.module
+	}
+	return p.WasmPlugin.Module()
+}
+
 func TestComparePluginOrder(t *testing.T) {
 	plugin := &MockPlugin{}
 
@@ -270,6 +291,46 @@ func TestRejectBadPluginDef(t *testing.T) {
 			},
 			err: errInvalidConsumerPluginOrder,
 		},
+		{
+			name: "invalid Wasm plugin order",
+			input: &wasmPluginWrapper{
+				WasmPlugin: &MockWasmPlugin{},
+				order: &PluginOrder{
+					Position: OrderPositionAuthz,
+				},
+			},
+			err: errInvalidWasmPluginOrder,
+		},
+		{
+			name: "invalid Wasm module URL",
+			input: &wasmPluginWrapper{
+				WasmPlugin: &MockWasmPlugin{},
+				module: &WasmModule{
+					URL: "ftp://example.com/plugin.wasm",
+				},
+			},
+			err: errInvalidWasmModuleURL,
+		},
+		{
+			name: "missing Wasm module SHA256",
+			input: &wasmPluginWrapper{
+				WasmPlugin: &MockWasmPlugin{},
+				module: &WasmModule{
+					URL: "https://example.com/plugin.wasm",
+				},
+			},
+			err: errMissingWasmModuleSHA256,
+		},
+		{
+			name: "missing OCI Wasm module SHA256",
+			input: &wasmPluginWrapper{
+				WasmPlugin: &MockWasmPlugin{},
+				module: &WasmModule{
+					URL: "oci://ghcr.io/mosn/htnn-wasm-demo:latest",
+				},
+			},
+			err: errMissingWasmModuleSHA256,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -284,4 +345,9 @@ func TestRegisterPluginWithType(t *testing.T) {
 	RegisterPlugin("mock", &MockPlugin{})
 	assert.NotNil(t, LoadPlugin("mock"))
 	assert.NotNil(t, LoadPluginType("mock"))
+
+	RegisterPlugin("mockWasm", &MockWasmPlugin{})
+	assert.NotNil(t, LoadPlugin("mockWasm"))
+	assert.NotNil(t, LoadPluginType("mockWasm"))
+	DisablePlugin("mockWasm")
 }
@@ -153,6 +153,46 @@ type Initer interface {
 	Init(cb api.ConfigCallbackHandler) error
 }
 
+// AnonymousConsumerConfig is implemented by the configuration which names the consumer to use
+// when no consumer is set by the Consumer plugins.
+type AnonymousConsumerConfig interface {
+	AnonymousConsumer() string
+}
+
+// ConsumerMetadataHeadersConfig is implemented by the configuration which maps the consumer metadata
+// to the request headers. The headers are always removed before the Consumer plugins are run.
+type ConsumerMetadataHeadersConfig interface {
+	ConsumerMetadataHeaders() map[string]string
+}
+
+// AuthnMode decides how the Consumer plugins configured in the same route are combined.
+type AuthnMode int
+
+const (
+	// AuthnModeDefault means each Consumer plugin rejects the request independently
+	AuthnModeDefault AuthnMode = iota
+	// AuthnModeAny means the first Consumer plugin which sets the consumer wins, the others are skipped
+	AuthnModeAny
+	// AuthnModeAll means all the Consumer plugins must set the same consumer
+	AuthnModeAll
+)
+
+// AuthnPolicyConfig is implemented by the configuration which decides the AuthnMode of the route.
+type AuthnPolicyConfig interface {
+	AuthnMode() AuthnMode
+}
+
+// DynamicConfigSubscriber is implemented by the configuration which refers DynamicConfigs,
+// usually via the `dynamicConfigRef` field.
+type DynamicConfigSubscriber interface {
+	// DynamicConfigRefs returns the names of the referred DynamicConfigs
+	DynamicConfigRefs() []string
+	// OnDynamicConfigUpdate is called before handling the next request once the referred DynamicConfig
+	// is applied. The config is resolved from the namespace of the configuration, and is nil if the
+	// DynamicConfig doesn't exist. It may be called concurrently with the running requests.
+	OnDynamicConfigUpdate(name string, config any)
+}
+
 type NativePlugin interface {
 	Plugin
 
@@ -173,6 +213,45 @@ type HTTPNativePluginHasRouteConfigWrapper interface {
 	ToRouteConfig(raw map[string]interface{}) map[string]interface{}
 }
 
+// HTTPNativePluginHasFilterConfig is implemented by the native plugin which can't be fully configured
+// in the route, like jwt_authn whose providers can only be defined in the HTTP filter. The filter
+// configurations extracted from all the routes are merged and delivered via ECDS.
+type HTTPNativePluginHasFilterConfig interface {
+	// FilterConfigTypeURL returns the type URL of the HTTP filter configuration
+	FilterConfigTypeURL() string
+	// ToFilterConfig extracts the part of the HTTP filter configuration from the raw config.
+	// The returned top-level fields of map type are merged with the ones from the other routes,
+	// so the keys of them should be unique for different configuration.
+	ToFilterConfig(raw map[string]interface{}) map[string]interface{}
+}
+
+// WasmModule describes the proxy-wasm module run by a WasmPlugin
+type WasmModule struct {
+	// URL is the location of the module. The supported schemes are `oci://`, `http://`, `https://`
+	// and `file://`. The OCI and HTTP modules are fetched by istio-agent, while the local file
+	// is loaded by Envoy directly.
+	URL string
+	// SHA256 is the checksum of the module. It's required for the OCI and HTTP module.
+	SHA256 string
+	// RootID is the root_id of the plugin in the module. Optional.
+	RootID string
+	// Configuration is passed to the plugin when it's configured. Optional.
+	Configuration string
+	// FailOpen lets the request pass through the plugin when the module is not loaded yet or
+	// fails to load. By default, the request is rejected with 503 in this case. Only enable it
+	// for the plugin which is not used for security, like authentication or WAF.
+	FailOpen bool
+}
+
+// WasmPlugin runs a proxy-wasm module in Envoy's wasm HTTP filter. As the wasm filter doesn't
+// support per-route configuration, the filter is only enabled in the route, and the configuration
+// of the route is put into the route's metadata under the filter's name.
+type WasmPlugin interface {
+	Plugin
+
+	Module() WasmModule
+}
+
 type GoPlugin interface {
 	Plugin
 
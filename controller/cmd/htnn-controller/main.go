@@ -0,0 +1,79 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// htnn-controller runs the HTNN controller as a standalone process outside istiod.
+// By default, the generated EnvoyFilters are written to the Kubernetes API server, so HTNN can be upgraded
+// independently of Istio.
+package main
+
+import (
+	"flag"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/metrics"
+	"mosn.io/htnn/controller/internal/standalone"
+)
+
+func main() {
+	var opts standalone.Options
+	var logEncoding string
+	flag.StringVar(&opts.MetricsBindAddress, "metrics-bind-address", ":15014",
+		"The address the metrics endpoint binds to. The DynamicConfig status is also received on it. Set it to 0 to disable the server.")
+	flag.StringVar(&opts.HealthProbeBindAddress, "health-probe-bind-address", ":8081",
+		"The address the /healthz and /readyz endpoints bind to.")
+	flag.BoolVar(&opts.LeaderElection, "leader-elect", false,
+		"Enable leader election to ensure there is only one active controller. Required when running multiple replicas.")
+	flag.StringVar(&opts.LeaderElectionID, "leader-election-id", "htnn-controller-leader",
+		"The name of the Lease used in the leader election.")
+	flag.StringVar(&opts.LeaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the Lease used in the leader election. Default to the Istio root namespace.")
+	flag.StringVar(&opts.WebhookCertDir, "webhook-cert-dir", "",
+		"The directory containing tls.crt and tls.key to serve the validating webhook. The webhook is disabled if it's empty, unless HTNN_WEBHOOK_SELF_SIGNED_CERT is true.")
+	flag.IntVar(&opts.WebhookPort, "webhook-port", 9443,
+		"The port of the validating webhook served with the certificate from --webhook-cert-dir.")
+	flag.StringVar(&opts.Output, "output", standalone.OutputK8s,
+		"Where the generated configuration goes. k8s writes EnvoyFilters to the API server, xds serves the configuration to Envoy via xDS.")
+	flag.StringVar(&opts.XDSAddress, "xds-address", ":15010",
+		"The address the xDS server listens to when --output is xds.")
+	flag.StringVar(&opts.XDSRouteConfigFile, "xds-route-config-file", "",
+		"The YAML file of the base route configurations served via RDS when --output is xds.")
+	flag.StringVar(&opts.XDSNamespace, "xds-namespace", "",
+		"The namespace of the gateways served via xDS when --output is xds. All the namespaces are served if it's empty.")
+	flag.StringVar(&logEncoding, "log-encoding", "console", "The log encoding, console or json.")
+	flag.Parse()
+
+	log.InitLogger(logEncoding)
+	ctrl.SetLogger(log.Logger())
+	// the HTNN_* environment variables are read here
+	config.Init()
+	// the metrics are exposed by the metrics server of the manager
+	metrics.InitMetrics(metrics.NewPrometheusProvider(ctrlmetrics.Registry))
+
+	mgr, err := standalone.NewManager(ctrl.GetConfigOrDie(), opts)
+	if err != nil {
+		log.Errorf("failed to create manager: %v", err)
+		os.Exit(1)
+	}
+
+	log.Info("starting htnn-controller")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		log.Errorf("failed to run manager: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/offline"
+)
+
+func printExplanation(w io.Writer, exp *offline.Explanation) {
+	fmt.Fprintf(w, "Gateway:      %s\n", exp.Gateway)
+	fmt.Fprintf(w, "Listener:     %s\n", exp.Listener)
+	fmt.Fprintf(w, "Virtual host: %s\n", exp.VirtualHost)
+	fmt.Fprintf(w, "Route:        %s (%s %s/%s)\n", exp.Route.RouteName, exp.Route.Kind, exp.Route.Namespace, exp.Route.Name)
+
+	fmt.Fprintf(w, "\nPlugins (in execution order):\n")
+	if len(exp.Plugins) == 0 {
+		fmt.Fprintf(w, "  <none>\n")
+	}
+	for _, p := range exp.Plugins {
+		fmt.Fprintf(w, "  %-20s from %s (%s)\n", p.Name, p.Policy, p.Level)
+	}
+
+	if len(exp.Policies) > 0 {
+		fmt.Fprintf(w, "\nFilterPolicies:\n")
+		for _, p := range exp.Policies {
+			fmt.Fprintf(w, "  %s (%s): %s\n", p.Policy, p.Level, strings.Join(p.Plugins, ", "))
+		}
+	}
+
+	if len(exp.Overridden) > 0 {
+		fmt.Fprintf(w, "\nOverridden:\n")
+		for _, o := range exp.Overridden {
+			fmt.Fprintf(w, "  %s from %s is overridden by %s\n", o.Name, o.Policy, o.By)
+		}
+	}
+
+	if len(exp.Notes) > 0 {
+		fmt.Fprintf(w, "\nNotes:\n")
+		for _, n := range exp.Notes {
+			fmt.Fprintf(w, "  %s\n", n)
+		}
+	}
+}
+
+func runExplain(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var files stringsFlag
+	fs.Var(&files, "f", "the file or directory which contains the resources, can be specified multiple times")
+	gateway := fs.String("gateway", "", "the namespace/name of the Istio Gateway or the Kubernetes Gateway")
+	host := fs.String("host", "", "the host of the request")
+	path := fs.String("path", "/", "the path of the request")
+	method := fs.String("method", "GET", "the method of the request")
+	port := fs.Uint("port", 0, "the port of the gateway which receives the request, all ports are matched if not specified")
+	output := fs.String("o", "text", "the output format, one of text, json and yaml")
+	verbose := fs.Bool("v", false, "output the log of the controller to stderr")
+	strict := fs.Bool("strict", false, "exit with error if the matched route can't be determined exactly")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: htnnctl explain -f <file> --gateway <namespace/name> --host <host> [flags]\n\n"+
+			"Show which route the request hits, which FilterPolicies contribute to it, the plugins which will be executed\n"+
+			"and the configurations which are overridden.\n\n"+
+			"Flags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(files) == 0 || *gateway == "" || *host == "" {
+		fs.Usage()
+		return errors.New("-f, --gateway and --host are required")
+	}
+	ns, name, found := strings.Cut(*gateway, "/")
+	if !found {
+		ns, name = "default", *gateway
+	}
+	if *output != "text" && *output != "json" && *output != "yaml" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	config.Init()
+	if *verbose {
+		log.InitLogger("console")
+	}
+
+	loaded, err := offline.Load(files)
+	if err != nil {
+		return err
+	}
+	for _, res := range loaded.Skipped {
+		fmt.Fprintf(stderr, "Skip unsupported resource %s\n", res)
+	}
+
+	exp, err := offline.Explain(context.Background(), loaded.Objects, &offline.ExplainRequest{
+		Gateway: types.NamespacedName{Namespace: ns, Name: name},
+		Port:    uint32(*port),
+		Host:    *host,
+		Path:    *path,
+		Method:  strings.ToUpper(*method),
+	})
+	if err != nil {
+		return err
+	}
+	for _, rejected := range exp.Rejected {
+		fmt.Fprintf(stderr, "Warning: %s\n", rejected)
+	}
+	for _, warning := range exp.Warnings {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
+	}
+	if *strict && len(exp.Warnings) > 0 {
+		return fmt.Errorf("the matched route can't be determined exactly, %d warning(s) found", len(exp.Warnings))
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(exp)
+	case "yaml":
+		d, err := yaml.Marshal(exp)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(d)
+		return err
+	default:
+		printExplanation(stdout, exp)
+	}
+	return nil
+}
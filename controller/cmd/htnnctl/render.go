@@ -0,0 +1,94 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/offline"
+)
+
+func runRender(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var files stringsFlag
+	fs.Var(&files, "f", "the file or directory which contains the resources, can be specified multiple times")
+	strict := fs.Bool("strict", false, "exit with error if any resource is not accepted")
+	verbose := fs.Bool("v", false, "output the log of the controller to stderr")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: htnnctl render -f <file> [-f <file>...] [flags]\n\n"+
+			"Read Gateways, VirtualServices, HTTPRoutes, FilterPolicies and Consumers from the files, and print the generated EnvoyFilters.\n"+
+			"The controller options are read from the HTNN_* environment variables, like the one running in the istiod.\n\n"+
+			"Flags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(files) == 0 {
+		fs.Usage()
+		return errors.New("no file is specified")
+	}
+
+	config.Init()
+	if *verbose {
+		log.InitLogger("console")
+	}
+
+	loaded, err := offline.Load(files)
+	if err != nil {
+		return err
+	}
+	for _, res := range loaded.Skipped {
+		fmt.Fprintf(stderr, "Skip unsupported resource %s\n", res)
+	}
+
+	res, err := offline.Render(context.Background(), loaded.Objects)
+	if err != nil {
+		return err
+	}
+	for _, rejected := range res.Rejected {
+		fmt.Fprintf(stderr, "Warning: %s\n", rejected)
+	}
+
+	for i, ef := range res.EnvoyFilters {
+		d, err := yaml.Marshal(ef)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout, "---")
+		}
+		if _, err := stdout.Write(d); err != nil {
+			return err
+		}
+	}
+
+	if *strict && len(res.Rejected) > 0 {
+		return fmt.Errorf("%d resource(s) are not accepted", len(res.Rejected))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	_ "mosn.io/htnn/controller/plugins" // register plugin types
+	"mosn.io/htnn/types/pkg/jsonschema"
+)
+
+func writeSchema(w io.Writer, schema jsonschema.Schema) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
+
+func writeSchemaFile(path string, schema jsonschema.Schema) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeSchema(f, schema)
+}
+
+// writeSchemaDir writes the schema of FilterPolicy filters, Consumer auth and each plugin to the dir
+func writeSchemaDir(dir string) error {
+	files := map[string]jsonschema.Schema{
+		"filters.json":       jsonschema.Filters(),
+		"consumer-auth.json": jsonschema.ConsumerAuth(),
+	}
+	for name, schema := range jsonschema.PluginConfigs() {
+		files[filepath.Join("plugins", name+".json")] = schema
+	}
+	for name, schema := range jsonschema.ConsumerConfigs() {
+		files[filepath.Join("consumers", name+".json")] = schema
+	}
+
+	for name, schema := range files {
+		if err := writeSchemaFile(filepath.Join(dir, name), schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSchema(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	plugin := fs.String("plugin", "", "print the schema of the given plugin's configuration")
+	consumer := fs.Bool("consumer", false, "print the schema of the consumer configuration instead, like the `auth` field in Consumer")
+	dir := fs.String("o", "", "write all the schemas to the given directory instead of printing")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: htnnctl schema [flags] [plugin]\n\n"+
+			"Print the JSON Schema of the plugin configuration, generated from the protobuf definitions and their validation rules.\n"+
+			"By default, the schema of the `filters` field in FilterPolicy is printed.\n"+
+			"The plugin can be given as the argument, which is the same as --plugin.\n\n"+
+			"Flags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		if *plugin != "" && *plugin != fs.Arg(0) {
+			return fmt.Errorf("the plugin is given twice: %q and %q", *plugin, fs.Arg(0))
+		}
+		*plugin = fs.Arg(0)
+	default:
+		fs.Usage()
+		return fmt.Errorf("too many arguments: %v", fs.Args())
+	}
+
+	if *dir != "" {
+		if *plugin != "" {
+			return errors.New("-o writes all the schemas and can't be used with a plugin")
+		}
+		return writeSchemaDir(*dir)
+	}
+
+	if *plugin == "" {
+		if *consumer {
+			return writeSchema(stdout, jsonschema.ConsumerAuth())
+		}
+		return writeSchema(stdout, jsonschema.Filters())
+	}
+
+	schemas := jsonschema.PluginConfigs()
+	if *consumer {
+		schemas = jsonschema.ConsumerConfigs()
+	}
+	schema, ok := schemas[*plugin]
+	if !ok {
+		if *consumer {
+			return fmt.Errorf("unknown consumer plugin %q", *plugin)
+		}
+		return fmt.Errorf("unknown plugin %q", *plugin)
+	}
+	return writeSchema(stdout, schema)
+}
@@ -17,6 +17,7 @@ package config
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -38,6 +39,20 @@ func updateBoolIfSet(vp *viper.Viper, key string, item *bool) {
 	}
 }
 
+func updateIntIfSet(vp *viper.Viper, key string, item *int) {
+	if vp.IsSet(key) {
+		*item = vp.GetInt(key)
+		return
+	}
+}
+
+func updateDurationIfSet(vp *viper.Viper, key string, item *time.Duration) {
+	if vp.IsSet(key) {
+		*item = vp.GetDuration(key)
+		return
+	}
+}
+
 var (
 	configLock sync.RWMutex
 )
@@ -73,6 +88,17 @@ func EnableGatewayAPI() bool {
 	return enableGatewayAPI
 }
 
+var enableAlphaGatewayAPI = false
+
+// If this is set to true, FilterPolicy can target to the gateway-api resources which are only available
+// in the experimental channel, like GRPCRoute, TCPRoute and TLSRoute. The experimental CRDs need to be installed.
+// This option only takes effect when the gateway-api is enabled.
+func EnableAlphaGatewayAPI() bool {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return enableGatewayAPI && enableAlphaGatewayAPI
+}
+
 var enableEmbeddedMode = true
 
 // Enable embedded mode to configure the FilterPolicy directly via the target resource's annotation.
@@ -123,6 +149,128 @@ func UseWildcardIPv6InLDSName() bool {
 	return useWildcardIPv6InLDSName
 }
 
+var envoyFilterWriteDelay time.Duration
+
+// The EnvoyFilters are written after the output is quiet for the given delay, so that the writes
+// during bulk changes are batched. It is disabled by default.
+// Only works when the EnvoyFilters are written to Kubernetes.
+func EnvoyFilterWriteDelay() time.Duration {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return envoyFilterWriteDelay
+}
+
+var envoyFilterWriteMaxDelay = 5 * time.Second
+
+// The max delay of writing the EnvoyFilters when the write delay is enabled. The batched writes
+// are flushed once the first one is delayed for this long, even if the output is still busy.
+func EnvoyFilterWriteMaxDelay() time.Duration {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return envoyFilterWriteMaxDelay
+}
+
+var envoyFilterShardSize = 512 * 1024
+
+// The route EnvoyFilters generated for the same domain are split into multiple EnvoyFilters once their
+// size in JSON exceeds this, to stay away from the size limit of the objects stored in etcd.
+func EnvoyFilterShardSize() int {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return envoyFilterShardSize
+}
+
+var envoyFilterShardMaxRoutes = 0
+
+// The max number of routes in each route EnvoyFilter. The EnvoyFilters generated for the same domain are
+// split into multiple EnvoyFilters once the number of routes exceeds this. It is disabled by default.
+func EnvoyFilterShardMaxRoutes() int {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return envoyFilterShardMaxRoutes
+}
+
+var envoyFilterMaxRouteSize = 256 * 1024
+
+// The max size in JSON of the configuration generated for a route. The policies pushing the configuration
+// of a route over it are excluded from the route and rejected. A warning condition is reported
+// to the policies once the size of the configuration is over 80% of the limit. Set it to 0 to disable the limit.
+func EnvoyFilterMaxRouteSize() int {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return envoyFilterMaxRouteSize
+}
+
+var webhookSelfSignedCert = false
+
+// Serve the validating webhook with a dedicated server which uses a self-signed certificate.
+// The certificate is generated at startup and the caBundle of the ValidatingWebhookConfiguration is
+// patched accordingly. Turn this on if the webhook can't be served by the istiod's https server.
+func WebhookSelfSignedCert() bool {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return webhookSelfSignedCert
+}
+
+var webhookPort = 9443
+
+// The port of the dedicated webhook server. Only works when the self-signed certificate is enabled.
+func WebhookPort() int {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return webhookPort
+}
+
+var webhookCertSecret = "htnn-webhook-cert"
+
+// The name of the Secret in the root namespace which stores the self-signed certificate.
+// The certificate is shared by all the replicas and reused if it's still valid.
+func WebhookCertSecret() string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return webhookCertSecret
+}
+
+var webhookService = "istiod"
+
+// The name of the Service in the root namespace which exposes the webhook. It's used as the DNS name of
+// the self-signed certificate.
+func WebhookService() string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return webhookService
+}
+
+var webhookConfigName = "istiod-htnn-validator"
+
+// The name of the ValidatingWebhookConfiguration whose caBundle is patched with the self-signed certificate.
+func WebhookConfigName() string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return webhookConfigName
+}
+
+var rateLimitServiceCluster = "outbound|8081||ratelimit.default.svc.cluster.local"
+
+// The cluster of the rate limit service used by the ratelimit plugin. The rate limit service is shared
+// by all the routes, as it can't be configured per route.
+func RateLimitServiceCluster() string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return rateLimitServiceCluster
+}
+
+var dynamicConfigStatusReporters []string
+
+// The service accounts allowed to report the apply status of DynamicConfig, in the format of
+// "namespace/name". Use "namespace/*" to allow all the service accounts in the namespace.
+// No reporter is allowed by default.
+func DynamicConfigStatusReporters() []string {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return dynamicConfigStatusReporters
+}
+
 type envStringReplacer struct {
 }
 
@@ -142,9 +290,29 @@ func Init() {
 	updateStringIfSet(vp, "envoy.go_so_path", &goSoPath)
 
 	updateBoolIfSet(vp, "enable_embedded_mode", &enableEmbeddedMode)
+	updateBoolIfSet(vp, "enable_alpha_gateway_api", &enableAlphaGatewayAPI)
 	updateBoolIfSet(vp, "enable_native_plugin", &enableNativePlugin)
 	updateBoolIfSet(vp, "enable_lds_plugin_via_ecds", &enableLDSPluginViaECDS)
 	updateBoolIfSet(vp, "use_wildcard_ipv6_in_lds_name", &useWildcardIPv6InLDSName)
+	updateDurationIfSet(vp, "envoyfilter.write_delay", &envoyFilterWriteDelay)
+	updateDurationIfSet(vp, "envoyfilter.write_max_delay", &envoyFilterWriteMaxDelay)
+	updateIntIfSet(vp, "envoyfilter.shard_size", &envoyFilterShardSize)
+	updateIntIfSet(vp, "envoyfilter.shard_max_routes", &envoyFilterShardMaxRoutes)
+	updateIntIfSet(vp, "envoyfilter.max_route_size", &envoyFilterMaxRouteSize)
+	updateBoolIfSet(vp, "webhook.self_signed_cert", &webhookSelfSignedCert)
+	updateIntIfSet(vp, "webhook.port", &webhookPort)
+	updateStringIfSet(vp, "webhook.cert_secret", &webhookCertSecret)
+	updateStringIfSet(vp, "webhook.service", &webhookService)
+	updateStringIfSet(vp, "webhook.config_name", &webhookConfigName)
+	updateStringIfSet(vp, "ratelimit.service_cluster", &rateLimitServiceCluster)
+	if vp.IsSet("dynamic_config.status_reporters") {
+		dynamicConfigStatusReporters = nil
+		for _, reporter := range strings.Split(vp.GetString("dynamic_config.status_reporters"), ",") {
+			if reporter = strings.TrimSpace(reporter); reporter != "" {
+				dynamicConfigStatusReporters = append(dynamicConfigStatusReporters, reporter)
+			}
+		}
+	}
 
 	// The configuration below is set via the Istio directly, not via the environment variables
 	// provided when starting the Istio.
@@ -155,6 +323,10 @@ func Init() {
 }
 
 func postInit() {
+	if envoyFilterWriteMaxDelay < envoyFilterWriteDelay {
+		envoyFilterWriteMaxDelay = envoyFilterWriteDelay
+	}
+
 	if !enableNativePlugin {
 		log.Infof("native plugin disabled by configured")
 		plugins.IteratePlugin(func(key string, value plugins.Plugin) bool {
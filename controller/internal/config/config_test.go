@@ -17,6 +17,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +30,18 @@ func setEnvForTest() {
 	os.Setenv("HTNN_ISTIO_ROOT_NAMESPACE", "htnn")
 	os.Setenv("HTNN_ENABLE_LDS_PLUGIN_VIA_ECDS", "true")
 	os.Setenv("HTNN_USE_WILDCARD_IPV6_IN_LDS_NAME", "true")
+	os.Setenv("HTNN_ENVOYFILTER_WRITE_DELAY", "10s")
+	os.Setenv("HTNN_ENVOYFILTER_WRITE_MAX_DELAY", "1s")
+	os.Setenv("HTNN_ENVOYFILTER_SHARD_SIZE", "1024")
+	os.Setenv("HTNN_ENVOYFILTER_SHARD_MAX_ROUTES", "100")
+	os.Setenv("HTNN_ENVOYFILTER_MAX_ROUTE_SIZE", "0")
+	os.Setenv("HTNN_ENABLE_ALPHA_GATEWAY_API", "true")
+	os.Setenv("HTNN_WEBHOOK_SELF_SIGNED_CERT", "true")
+	os.Setenv("HTNN_WEBHOOK_PORT", "8443")
+	os.Setenv("HTNN_WEBHOOK_CERT_SECRET", "webhook-cert")
+	os.Setenv("HTNN_WEBHOOK_SERVICE", "htnn-controller")
+	os.Setenv("HTNN_WEBHOOK_CONFIG_NAME", "htnn-validator")
+	os.Setenv("HTNN_DYNAMIC_CONFIG_STATUS_REPORTERS", "istio-system/istio-ingressgateway, gateway/*,")
 }
 
 func TestInit(t *testing.T) {
@@ -36,21 +49,47 @@ func TestInit(t *testing.T) {
 
 	// Check default values
 	assert.Equal(t, true, EnableGatewayAPI())
+	assert.Equal(t, false, EnableAlphaGatewayAPI())
 	assert.Equal(t, true, EnableEmbeddedMode())
 	assert.Equal(t, true, EnableNativePlugin())
 	assert.Equal(t, "/etc/libgolang.so", GoSoPath())
 	assert.Equal(t, "istio-system", RootNamespace())
 	assert.Equal(t, false, EnableLDSPluginViaECDS())
 	assert.Equal(t, false, UseWildcardIPv6InLDSName())
+	assert.Equal(t, time.Duration(0), EnvoyFilterWriteDelay())
+	assert.Equal(t, 5*time.Second, EnvoyFilterWriteMaxDelay())
+	assert.Equal(t, 512*1024, EnvoyFilterShardSize())
+	assert.Equal(t, 0, EnvoyFilterShardMaxRoutes())
+	assert.Equal(t, 256*1024, EnvoyFilterMaxRouteSize())
+	assert.Equal(t, false, WebhookSelfSignedCert())
+	assert.Equal(t, 9443, WebhookPort())
+	assert.Equal(t, "htnn-webhook-cert", WebhookCertSecret())
+	assert.Equal(t, "istiod", WebhookService())
+	assert.Equal(t, "istiod-htnn-validator", WebhookConfigName())
+	assert.Empty(t, DynamicConfigStatusReporters())
 
 	setEnvForTest()
 	Init()
 
 	assert.Equal(t, false, EnableGatewayAPI())
+	// alpha gateway API requires the gateway API
+	assert.Equal(t, false, EnableAlphaGatewayAPI())
 	assert.Equal(t, false, EnableEmbeddedMode())
 	assert.Equal(t, false, EnableNativePlugin())
 	assert.Equal(t, "/usr/local/golang.so", GoSoPath())
 	assert.Equal(t, "htnn", RootNamespace())
 	assert.Equal(t, true, EnableLDSPluginViaECDS())
 	assert.Equal(t, true, UseWildcardIPv6InLDSName())
+	assert.Equal(t, 10*time.Second, EnvoyFilterWriteDelay())
+	// the max delay can't be less than the delay
+	assert.Equal(t, 10*time.Second, EnvoyFilterWriteMaxDelay())
+	assert.Equal(t, 1024, EnvoyFilterShardSize())
+	assert.Equal(t, 100, EnvoyFilterShardMaxRoutes())
+	assert.Equal(t, 0, EnvoyFilterMaxRouteSize())
+	assert.Equal(t, true, WebhookSelfSignedCert())
+	assert.Equal(t, 8443, WebhookPort())
+	assert.Equal(t, "webhook-cert", WebhookCertSecret())
+	assert.Equal(t, "htnn-controller", WebhookService())
+	assert.Equal(t, "htnn-validator", WebhookConfigName())
+	assert.Equal(t, []string{"istio-system/istio-ingressgateway", "gateway/*"}, DynamicConfigStatusReporters())
 }
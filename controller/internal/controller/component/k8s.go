@@ -17,45 +17,236 @@ package component
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"google.golang.org/protobuf/proto"
 	istioapi "istio.io/api/networking/v1alpha3"
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
+	"mosn.io/htnn/controller/internal/config"
 	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/metrics"
 	"mosn.io/htnn/controller/pkg/component"
 	"mosn.io/htnn/controller/pkg/constant"
 )
 
+const (
+	writeRetryBaseDelay = 500 * time.Millisecond
+	writeRetryMaxDelay  = 2 * time.Minute
+)
+
+type writeFunc func(ctx context.Context) error
+
+// WriteFailureNotifier is implemented by the Output which runs the writes asynchronously. As the
+// reconciliation succeeds before the write is run, the creator should reconcile again when the
+// channel returned for it receives an event.
+type WriteFailureNotifier interface {
+	WriteFailures(creator string) <-chan event.GenericEvent
+}
+
+type pendingWrite struct {
+	write writeFunc
+	// ctx is the context of the reconciliation, which is canceled once the leadership is lost
+	ctx   context.Context
+	since time.Time
+	timer *time.Timer
+	// stopDropping stops dropping the write when the ctx is canceled
+	stopDropping func() bool
+}
+
 type k8sOutput struct {
 	client.Client
 	logger logr.Logger
 
 	serviceEntrySyncer *serviceEntrySyncer
+
+	writeDelay    time.Duration
+	writeMaxDelay time.Duration
+	// creator -> the latest write which is not run yet
+	pending     map[string]*pendingWrite
+	pendingLock sync.Mutex
+	// serialize the delayed writes
+	writeLock sync.Mutex
+	// backoff of the failed delayed writes, by creator
+	retryLimiter workqueue.RateLimiter
+	// creator -> the time before which the next write should not be run
+	retryAfter map[string]time.Time
+	// creator -> the channel to notify the failed write
+	failures map[string]chan event.GenericEvent
 }
 
+var _ WriteFailureNotifier = &k8sOutput{}
+
 func NewK8sOutput(c client.Client) component.Output {
 	o := &k8sOutput{
-		Client: c,
-		logger: log.Logger(),
+		Client:        c,
+		logger:        log.Logger(),
+		writeDelay:    config.EnvoyFilterWriteDelay(),
+		writeMaxDelay: config.EnvoyFilterWriteMaxDelay(),
+		pending:       make(map[string]*pendingWrite),
+		retryLimiter:  workqueue.NewItemExponentialFailureRateLimiter(writeRetryBaseDelay, writeRetryMaxDelay),
+		retryAfter:    make(map[string]time.Time),
+		failures:      make(map[string]chan event.GenericEvent),
 	}
 	o.serviceEntrySyncer = newServiceEntrySyncer(c, &o.logger)
 	return o
 }
 
 func (o *k8sOutput) FromFilterPolicy(ctx context.Context, generatedEnvoyFilters map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
-	return o.diffGeneratedEnvoyFilters(ctx, "FilterPolicy", generatedEnvoyFilters)
+	return o.write(ctx, "FilterPolicy", func(ctx context.Context) error {
+		return o.diffGeneratedEnvoyFilters(ctx, "FilterPolicy", generatedEnvoyFilters)
+	})
 }
 
 func (o *k8sOutput) FromConsumer(ctx context.Context, ef *istiov1a3.EnvoyFilter) error {
-	return o.diffGeneratedEnvoyFilter(ctx, "Consumer", ef)
+	return o.write(ctx, "Consumer", func(ctx context.Context) error {
+		return o.diffGeneratedEnvoyFilter(ctx, "Consumer", ef)
+	})
 }
 
 func (o *k8sOutput) FromDynamicConfig(ctx context.Context, efs map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
-	return o.diffGeneratedEnvoyFilters(ctx, "DynamicConfig", efs)
+	return o.write(ctx, "DynamicConfig", func(ctx context.Context) error {
+		return o.diffGeneratedEnvoyFilters(ctx, "DynamicConfig", efs)
+	})
+}
+
+// write runs the write immediately if the write delay is disabled. Otherwise, the writes from the same
+// creator are batched: as each write contains all the EnvoyFilters generated from the creator, only the
+// latest one is run, after the creator is quiet for the write delay, or the first write in the batch is
+// delayed for the max delay. The pending write is dropped once the ctx is canceled, like when the leadership
+// is lost. When the delayed write fails, the creator is notified via WriteFailures to reconcile again, and
+// the next write is backed off exponentially, until it succeeds.
+func (o *k8sOutput) write(ctx context.Context, creator string, write writeFunc) error {
+	if o.writeDelay <= 0 {
+		return write(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	o.pendingLock.Lock()
+	defer o.pendingLock.Unlock()
+
+	p, ok := o.pending[creator]
+	if !ok {
+		o.schedule(ctx, creator, write)
+		return nil
+	}
+
+	metrics.EnvoyFilterCoalescedTotal.Increment()
+	p.write = write
+	if p.ctx != ctx {
+		p.stopDropping()
+		p.ctx = ctx
+		p.stopDropping = context.AfterFunc(ctx, func() {
+			o.drop(creator, p)
+		})
+	}
+	delay := o.writeDelay
+	if remain := o.writeMaxDelay - time.Since(p.since); remain < delay {
+		delay = max(remain, 0)
+	}
+	if backoff := time.Until(o.retryAfter[creator]); backoff > delay {
+		delay = backoff
+	}
+	p.timer.Reset(delay)
+	return nil
+}
+
+// schedule should be called with the pendingLock held
+func (o *k8sOutput) schedule(ctx context.Context, creator string, write writeFunc) {
+	delay := o.writeDelay
+	if backoff := time.Until(o.retryAfter[creator]); backoff > delay {
+		delay = backoff
+	}
+	p := &pendingWrite{
+		write: write,
+		ctx:   ctx,
+		since: time.Now(),
+	}
+	p.timer = time.AfterFunc(delay, func() {
+		o.flush(creator, p)
+	})
+	p.stopDropping = context.AfterFunc(ctx, func() {
+		o.drop(creator, p)
+	})
+	o.pending[creator] = p
+}
+
+// drop removes the pending write whose context is canceled
+func (o *k8sOutput) drop(creator string, p *pendingWrite) {
+	o.pendingLock.Lock()
+	defer o.pendingLock.Unlock()
+
+	if o.pending[creator] != p {
+		return
+	}
+	p.timer.Stop()
+	delete(o.pending, creator)
+	o.logger.Info("drop the pending write as the context is canceled", "creator", creator)
+}
+
+func (o *k8sOutput) flush(creator string, p *pendingWrite) {
+	o.pendingLock.Lock()
+	if o.pending[creator] != p {
+		// already flushed or dropped, the timer is fired again because it is reset during flushing
+		o.pendingLock.Unlock()
+		return
+	}
+	delete(o.pending, creator)
+	p.stopDropping()
+	write, ctx := p.write, p.ctx
+	o.pendingLock.Unlock()
+
+	o.writeLock.Lock()
+	err := write(ctx)
+	o.writeLock.Unlock()
+	if err == nil {
+		o.retryLimiter.Forget(creator)
+		o.pendingLock.Lock()
+		delete(o.retryAfter, creator)
+		o.pendingLock.Unlock()
+		return
+	}
+	if ctx.Err() != nil {
+		// the leadership is lost, the new leader will write
+		o.logger.Error(err, "failed to write EnvoyFilter as the context is canceled", "creator", creator)
+		return
+	}
+
+	delay := o.retryLimiter.When(creator)
+	o.logger.Error(err, "failed to write EnvoyFilter, requeue", "creator", creator, "retryAfter", delay)
+	o.pendingLock.Lock()
+	o.retryAfter[creator] = time.Now().Add(delay)
+	ch := o.failureChannel(creator)
+	o.pendingLock.Unlock()
+	select {
+	case ch <- event.GenericEvent{}:
+	default:
+		// the creator is already notified
+	}
+}
+
+// failureChannel should be called with the pendingLock held
+func (o *k8sOutput) failureChannel(creator string) chan event.GenericEvent {
+	ch, ok := o.failures[creator]
+	if !ok {
+		ch = make(chan event.GenericEvent, 1)
+		o.failures[creator] = ch
+	}
+	return ch
+}
+
+func (o *k8sOutput) WriteFailures(creator string) <-chan event.GenericEvent {
+	o.pendingLock.Lock()
+	defer o.pendingLock.Unlock()
+	return o.failureChannel(creator)
 }
 
 func (o *k8sOutput) diffGeneratedEnvoyFilters(ctx context.Context, creator string, generatedEnvoyFilters map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
@@ -68,6 +259,11 @@ func (o *k8sOutput) diffGeneratedEnvoyFilters(ctx context.Context, creator strin
 		return fmt.Errorf("failed to list EnvoyFilter: %w", err)
 	}
 
+	changed := 0
+	defer func() {
+		metrics.EnvoyFilterDiffSizeDistribution.Record(float64(changed))
+	}()
+
 	preEnvoyFilterMap := make(map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter, len(envoyfilters.Items))
 	for _, e := range envoyfilters.Items {
 		key := component.EnvoyFilterKey{
@@ -80,6 +276,8 @@ func (o *k8sOutput) diffGeneratedEnvoyFilters(ctx context.Context, creator strin
 				return fmt.Errorf("failed to delete EnvoyFilter: %w, namespacedName: %v",
 					err, types.NamespacedName{Name: e.Name, Namespace: e.Namespace})
 			}
+			changed++
+			metrics.EnvoyFilterDeletedTotal.Increment()
 		} else {
 			preEnvoyFilterMap[key] = e
 		}
@@ -94,6 +292,8 @@ func (o *k8sOutput) diffGeneratedEnvoyFilters(ctx context.Context, creator strin
 				nsName := types.NamespacedName{Name: ef.Name, Namespace: ef.Namespace}
 				return fmt.Errorf("failed to create EnvoyFilter: %w, namespacedName: %v", err, nsName)
 			}
+			changed++
+			metrics.EnvoyFilterCreatedTotal.Increment()
 
 		} else {
 			if proto.Equal(&envoyfilter.Spec, &ef.Spec) {
@@ -107,6 +307,8 @@ func (o *k8sOutput) diffGeneratedEnvoyFilters(ctx context.Context, creator strin
 				nsName := types.NamespacedName{Name: ef.Name, Namespace: ef.Namespace}
 				return fmt.Errorf("failed to update EnvoyFilter: %w, namespacedName: %v", err, nsName)
 			}
+			changed++
+			metrics.EnvoyFilterUpdatedTotal.Increment()
 		}
 	}
 
@@ -131,6 +333,7 @@ func (o *k8sOutput) diffGeneratedEnvoyFilter(ctx context.Context, creator string
 				return fmt.Errorf("failed to delete EnvoyFilter: %w, namespacedName: %v",
 					err, types.NamespacedName{Name: e.Name, Namespace: e.Namespace})
 			}
+			metrics.EnvoyFilterDeletedTotal.Increment()
 		} else {
 			envoyfilter = e
 		}
@@ -142,6 +345,7 @@ func (o *k8sOutput) diffGeneratedEnvoyFilter(ctx context.Context, creator string
 		if err := o.Create(ctx, ef.DeepCopy()); err != nil {
 			return fmt.Errorf("failed to create EnvoyFilter: %w, namespacedName: %v", err, nsName)
 		}
+		metrics.EnvoyFilterCreatedTotal.Increment()
 	} else {
 		logger.Info("update EnvoyFilter", "name", ef.Name, "namespace", ef.Namespace)
 
@@ -150,6 +354,7 @@ func (o *k8sOutput) diffGeneratedEnvoyFilter(ctx context.Context, creator string
 		if err := o.Update(ctx, ef); err != nil {
 			return fmt.Errorf("failed to update EnvoyFilter: %w, namespacedName: %v", err, nsName)
 		}
+		metrics.EnvoyFilterUpdatedTotal.Increment()
 	}
 
 	return nil
@@ -0,0 +1,200 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package component
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/metrics"
+)
+
+type testSum struct {
+	count atomic.Int32
+}
+
+func (s *testSum) Increment() {
+	s.count.Add(1)
+}
+
+func newDelayedOutput(delay, maxDelay time.Duration) *k8sOutput {
+	return &k8sOutput{
+		logger:        log.Logger(),
+		writeDelay:    delay,
+		writeMaxDelay: maxDelay,
+		pending:       make(map[string]*pendingWrite),
+		retryLimiter:  workqueue.NewItemExponentialFailureRateLimiter(10*time.Millisecond, time.Second),
+		retryAfter:    make(map[string]time.Time),
+		failures:      make(map[string]chan event.GenericEvent),
+	}
+}
+
+func TestWriteWithoutDelay(t *testing.T) {
+	o := newDelayedOutput(0, 0)
+	called := false
+	err := o.write(context.Background(), "FilterPolicy", func(ctx context.Context) error {
+		called = true
+		return errors.New("ouch")
+	})
+	assert.True(t, called)
+	assert.ErrorContains(t, err, "ouch")
+}
+
+func TestWriteBatched(t *testing.T) {
+	coalesced := &testSum{}
+	orig := metrics.EnvoyFilterCoalescedTotal
+	metrics.EnvoyFilterCoalescedTotal = coalesced
+	defer func() {
+		metrics.EnvoyFilterCoalescedTotal = orig
+	}()
+
+	o := newDelayedOutput(50*time.Millisecond, time.Second)
+	var lock sync.Mutex
+	var written []string
+	writeOf := func(s string) writeFunc {
+		return func(ctx context.Context) error {
+			lock.Lock()
+			written = append(written, s)
+			lock.Unlock()
+			return nil
+		}
+	}
+
+	ctx := context.Background()
+	require.NoError(t, o.write(ctx, "FilterPolicy", writeOf("fp1")))
+	require.NoError(t, o.write(ctx, "Consumer", writeOf("consumer")))
+	require.NoError(t, o.write(ctx, "FilterPolicy", writeOf("fp2")))
+	require.NoError(t, o.write(ctx, "FilterPolicy", writeOf("fp3")))
+
+	lock.Lock()
+	assert.Empty(t, written)
+	lock.Unlock()
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(written) == 2
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	lock.Lock()
+	assert.ElementsMatch(t, []string{"consumer", "fp3"}, written)
+	lock.Unlock()
+	assert.Equal(t, int32(2), coalesced.count.Load())
+}
+
+func TestWriteMaxDelay(t *testing.T) {
+	o := newDelayedOutput(50*time.Millisecond, 100*time.Millisecond)
+	var count atomic.Int32
+	write := func(ctx context.Context) error {
+		count.Add(1)
+		return nil
+	}
+
+	// keep writing, so the delay is extended again and again
+	start := time.Now()
+	for time.Since(start) < 300*time.Millisecond {
+		require.NoError(t, o.write(context.Background(), "FilterPolicy", write))
+		time.Sleep(10 * time.Millisecond)
+	}
+	// the write is flushed because of the max delay
+	assert.GreaterOrEqual(t, count.Load(), int32(2))
+}
+
+func TestWriteRetry(t *testing.T) {
+	o := newDelayedOutput(10*time.Millisecond, 100*time.Millisecond)
+	var lock sync.Mutex
+	var calledAt []time.Time
+	write := func(ctx context.Context) error {
+		lock.Lock()
+		defer lock.Unlock()
+		calledAt = append(calledAt, time.Now())
+		if len(calledAt) <= 3 {
+			return errors.New("ouch")
+		}
+		return nil
+	}
+	called := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(calledAt)
+	}
+
+	// reconcile again once the write fails, like the controller
+	failures := o.WriteFailures("FilterPolicy")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for {
+			select {
+			case <-failures:
+				_ = o.write(ctx, "FilterPolicy", write)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	require.NoError(t, o.write(ctx, "FilterPolicy", write))
+	require.Eventually(t, func() bool {
+		return called() == 4
+	}, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 4, called())
+
+	// the retry is backed off exponentially
+	lock.Lock()
+	defer lock.Unlock()
+	assert.GreaterOrEqual(t, calledAt[3].Sub(calledAt[2]), 40*time.Millisecond)
+	assert.GreaterOrEqual(t, calledAt[2].Sub(calledAt[1]), 20*time.Millisecond)
+	// the backoff is reset after the write succeeds
+	assert.Equal(t, 0, o.retryLimiter.NumRequeues("FilterPolicy"))
+	o.pendingLock.Lock()
+	assert.Empty(t, o.retryAfter)
+	o.pendingLock.Unlock()
+}
+
+func TestWriteDroppedWhenCanceled(t *testing.T) {
+	o := newDelayedOutput(50*time.Millisecond, time.Second)
+	var count atomic.Int32
+	write := func(ctx context.Context) error {
+		count.Add(1)
+		return nil
+	}
+
+	// the leadership is lost before the write is flushed
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, o.write(ctx, "FilterPolicy", write))
+	cancel()
+	require.Eventually(t, func() bool {
+		o.pendingLock.Lock()
+		defer o.pendingLock.Unlock()
+		return len(o.pending) == 0
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), count.Load())
+
+	assert.ErrorIs(t, o.write(ctx, "FilterPolicy", write), context.Canceled)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -40,6 +41,9 @@ import (
 type ConsumerReconciler struct {
 	component.ResourceManager
 	Output component.Output
+
+	// the namespaces which have Consumers in the last reconciliation
+	prevNamespaces map[string]struct{}
 }
 
 //+kubebuilder:rbac:groups=htnn.mosn.io,resources=consumers,verbs=get;list;watch;create;update;patch;delete
@@ -51,11 +55,14 @@ type ConsumerReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
-func (r *ConsumerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ConsumerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	reconcilationStart := time.Now()
 	defer func() {
 		reconcilationDuration := time.Since(reconcilationStart).Seconds()
 		metrics.ConsumerReconcileDurationDistribution.Record(reconcilationDuration)
+		if err != nil {
+			metrics.ReconcileErrorsTotal.With("consumer").Increment()
+		}
 	}()
 
 	log.Info("Reconcile Consumer")
@@ -66,6 +73,8 @@ func (r *ConsumerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	r.recordConsumerCount(state)
+
 	err = r.generateCustomResource(ctx, state)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -130,6 +139,20 @@ func (r *ConsumerReconciler) consumersToState(ctx context.Context,
 	return state, nil
 }
 
+func (r *ConsumerReconciler) recordConsumerCount(state *consumerReconcileState) {
+	namespaces := make(map[string]struct{}, len(state.namespaceToConsumers))
+	for ns, consumers := range state.namespaceToConsumers {
+		namespaces[ns] = struct{}{}
+		metrics.ConsumerCount.With(ns).Record(float64(len(consumers)))
+	}
+	for ns := range r.prevNamespaces {
+		if _, ok := namespaces[ns]; !ok {
+			metrics.ConsumerCount.With(ns).Record(0)
+		}
+	}
+	r.prevNamespaces = namespaces
+}
+
 func (r *ConsumerReconciler) generateCustomResource(ctx context.Context, state *consumerReconcileState) error {
 	consumerData := map[string]interface{}{}
 	for ns, consumers := range state.namespaceToConsumers {
@@ -146,6 +169,9 @@ func (r *ConsumerReconciler) generateCustomResource(ctx context.Context, state *
 	}
 
 	ef := istio.GenerateConsumers(consumerData)
+	recordGeneratedEnvoyFilters("Consumer", map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter{
+		{Namespace: ef.Namespace, Name: ef.Name}: ef,
+	})
 
 	return r.Output.FromConsumer(ctx, ef)
 }
@@ -179,5 +205,6 @@ func (r *ConsumerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				predicate.GenerationChangedPredicate{},
 			),
 		)
+	watchWriteFailures(controller, r.Output, "Consumer")
 	return controller.Complete(r)
 }
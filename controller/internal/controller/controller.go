@@ -15,9 +15,20 @@
 package controller
 
 import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	ctrlcomponent "mosn.io/htnn/controller/internal/controller/component"
+	"mosn.io/htnn/controller/internal/metrics"
+	"mosn.io/htnn/controller/pkg/component"
 	_ "mosn.io/htnn/controller/plugins"    // register plugins
 	_ "mosn.io/htnn/controller/registries" // register registries
 )
@@ -31,3 +42,26 @@ var (
 func triggerReconciliation() []reconcile.Request {
 	return reconcileReqPlaceholder
 }
+
+func recordGeneratedEnvoyFilters(creator string, efs map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) {
+	size := 0
+	for _, ef := range efs {
+		size += proto.Size(&ef.Spec)
+	}
+	metrics.EnvoyFilterGenerated.With(creator).Record(float64(len(efs)))
+	metrics.EnvoyFilterGeneratedBytes.With(creator).Record(float64(size))
+}
+
+// watchWriteFailures reconciles again when the delayed write of the generated EnvoyFilters fails
+func watchWriteFailures(b *builder.Builder, output component.Output, creator string) {
+	notifier, ok := output.(ctrlcomponent.WriteFailureNotifier)
+	if !ok {
+		return
+	}
+	b.WatchesRawSource(
+		&source.Channel{Source: notifier.WriteFailures(creator)},
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return triggerReconciliation()
+		}),
+	)
+}
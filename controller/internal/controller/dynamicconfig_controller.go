@@ -18,9 +18,11 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -40,17 +42,23 @@ import (
 type DynamicConfigReconciler struct {
 	component.ResourceManager
 	Output component.Output
+	// RevisionStore stores the config of each revision. Rolling back is not supported without it.
+	RevisionStore DynamicConfigRevisionStore
 }
 
 //+kubebuilder:rbac:groups=htnn.mosn.io,resources=dynamicconfigs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=htnn.mosn.io,resources=dynamicconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=htnn.mosn.io,resources=dynamicconfigs/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update;delete
 
-func (r *DynamicConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *DynamicConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	reconcilationStart := time.Now()
 	defer func() {
 		reconcilationDuration := time.Since(reconcilationStart).Seconds()
 		metrics.DynamicConfigReconcileDurationDistribution.Record(reconcilationDuration)
+		if err != nil {
+			metrics.ReconcileErrorsTotal.With("dynamicconfig").Increment()
+		}
 	}()
 
 	log.Info("Reconcile DynamicConfig")
@@ -71,7 +79,7 @@ func (r *DynamicConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 }
 
 type dynamicConfigReconcileState struct {
-	namespaceToDynamicConfigs map[string]map[string]*mosniov1.DynamicConfig
+	namespaceToDynamicConfigs map[string]map[string]*istio.DispatchedDynamicConfig
 }
 
 func (r *DynamicConfigReconciler) dynamicconfigsToState(ctx context.Context,
@@ -81,7 +89,7 @@ func (r *DynamicConfigReconciler) dynamicconfigsToState(ctx context.Context,
 		return nil, fmt.Errorf("failed to list DynamicConfig: %w", err)
 	}
 
-	namespaceToDynamicConfigs := make(map[string]map[string]*mosniov1.DynamicConfig)
+	namespaceToDynamicConfigs := make(map[string]map[string]*istio.DispatchedDynamicConfig)
 	for i := range dynamicConfigs.Items {
 		dynamicConfig := &dynamicConfigs.Items[i]
 
@@ -100,7 +108,7 @@ func (r *DynamicConfigReconciler) dynamicconfigsToState(ctx context.Context,
 
 		namespace := dynamicConfig.Namespace
 		if namespaceToDynamicConfigs[namespace] == nil {
-			namespaceToDynamicConfigs[namespace] = make(map[string]*mosniov1.DynamicConfig)
+			namespaceToDynamicConfigs[namespace] = make(map[string]*istio.DispatchedDynamicConfig)
 		}
 
 		name := dynamicConfig.Spec.Type
@@ -110,8 +118,14 @@ func (r *DynamicConfigReconciler) dynamicconfigsToState(ctx context.Context,
 			dynamicConfig.SetAccepted(mosniov1.ReasonInvalid,
 				fmt.Sprintf("duplicate with another DynamicConfig %s/%s, k8s name %s", namespace, name, dynamicConfig.Name))
 		} else {
-			namespaceToDynamicConfigs[namespace][name] = dynamicConfig
-			dynamicConfig.SetAccepted(mosniov1.ReasonAccepted)
+			dispatched, err := r.dispatchRevision(ctx, dynamicConfig)
+			if err != nil {
+				return nil, err
+			}
+			if dispatched != nil {
+				namespaceToDynamicConfigs[namespace][name] = dispatched
+				dynamicConfig.SetAccepted(mosniov1.ReasonAccepted)
+			}
 		}
 	}
 
@@ -121,8 +135,68 @@ func (r *DynamicConfigReconciler) dynamicconfigsToState(ctx context.Context,
 	return state, nil
 }
 
+// dispatchRevision records the config in the spec and returns the revision which should be dispatched.
+// It returns nil if the revision can't be dispatched, and returns an error if the reconciliation should be retried.
+func (r *DynamicConfigReconciler) dispatchRevision(ctx context.Context, dynamicConfig *mosniov1.DynamicConfig) (*istio.DispatchedDynamicConfig, error) {
+	if dynamicConfig.Spec.RollbackTo == "" {
+		if !dynamicConfig.IsRevisionRecorded() {
+			if r.RevisionStore != nil {
+				// save the config before referring it in the history
+				if err := r.RevisionStore.Save(ctx, dynamicConfig, dynamicConfig.CurrentRevision()); err != nil {
+					return nil, fmt.Errorf("failed to save DynamicConfig revision: %w, namespacedName: %v", err,
+						types.NamespacedName{Name: dynamicConfig.Name, Namespace: dynamicConfig.Namespace})
+				}
+			}
+			dropped := dynamicConfig.RecordRevision()
+			if r.RevisionStore != nil {
+				for i := range dropped {
+					if err := r.RevisionStore.Delete(ctx, dynamicConfig, &dropped[i]); err != nil {
+						log.Errorf("failed to delete DynamicConfig revision, err: %v", err)
+					}
+				}
+			}
+		}
+		return &istio.DispatchedDynamicConfig{
+			DynamicConfig: dynamicConfig,
+			Version:       dynamicConfig.CurrentVersion(),
+			Config:        dynamicConfig.Spec.Config.Raw,
+		}, nil
+	}
+
+	rev := dynamicConfig.DispatchedRevision()
+	if rev == nil {
+		log.Errorf("version %s to roll back is not found, name: %s, namespace: %s",
+			dynamicConfig.Spec.RollbackTo, dynamicConfig.Name, dynamicConfig.Namespace)
+		dynamicConfig.SetAccepted(mosniov1.ReasonInvalid,
+			fmt.Sprintf("version %s to roll back is not found in the history", dynamicConfig.Spec.RollbackTo))
+		return nil, nil
+	}
+	if r.RevisionStore == nil {
+		dynamicConfig.SetAccepted(mosniov1.ReasonInvalid, "rolling back is not supported as the revision store is not configured")
+		return nil, nil
+	}
+
+	cfg, err := r.RevisionStore.Load(ctx, dynamicConfig, rev)
+	if err != nil {
+		if apierrors.IsNotFound(err) || errors.Is(err, errRevisionMismatched) {
+			log.Errorf("failed to load version %s to roll back, err: %v, name: %s, namespace: %s",
+				rev.Version, err, dynamicConfig.Name, dynamicConfig.Namespace)
+			dynamicConfig.SetAccepted(mosniov1.ReasonInvalid,
+				fmt.Sprintf("version %s to roll back is not available: %s", rev.Version, err))
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &istio.DispatchedDynamicConfig{
+		DynamicConfig: dynamicConfig,
+		Version:       rev.Version,
+		Config:        cfg,
+	}, nil
+}
+
 func (r *DynamicConfigReconciler) generateCustomResource(ctx context.Context, state *dynamicConfigReconcileState) error {
 	efs := istio.GenerateDynamicConfigs(state.namespaceToDynamicConfigs)
+	recordGeneratedEnvoyFilters("DynamicConfig", efs)
 	return r.Output.FromDynamicConfig(ctx, efs)
 }
 
@@ -155,5 +229,6 @@ func (r *DynamicConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				predicate.GenerationChangedPredicate{},
 			),
 		)
+	watchWriteFailures(controller, r.Output, "DynamicConfig")
 	return controller.Complete(r)
 }
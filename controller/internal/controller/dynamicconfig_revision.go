@@ -0,0 +1,124 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+const (
+	dynamicConfigRevisionLabel = "htnn.mosn.io/dynamicconfig"
+	dynamicConfigRevisionKey   = "config"
+)
+
+// errRevisionMismatched means the stored config doesn't match the hash recorded in the history
+var errRevisionMismatched = errors.New("the stored config doesn't match the revision")
+
+// DynamicConfigRevisionStore stores the config of each DynamicConfig revision,
+// so that the status only records the hash and the location of the config.
+type DynamicConfigRevisionStore interface {
+	Save(ctx context.Context, dc *mosniov1.DynamicConfig, rev *mosniov1.DynamicConfigRevision) error
+	Load(ctx context.Context, dc *mosniov1.DynamicConfig, rev *mosniov1.DynamicConfigRevision) ([]byte, error)
+	Delete(ctx context.Context, dc *mosniov1.DynamicConfig, rev *mosniov1.DynamicConfigRevision) error
+}
+
+type configMapRevisionStore struct {
+	client kubernetes.Interface
+}
+
+// NewConfigMapRevisionStore returns a DynamicConfigRevisionStore which stores each revision in a ConfigMap
+// next to the DynamicConfig. The ConfigMap is owned by the DynamicConfig, so it's garbage collected with it.
+func NewConfigMapRevisionStore(client kubernetes.Interface) DynamicConfigRevisionStore {
+	return &configMapRevisionStore{
+		client: client,
+	}
+}
+
+func (s *configMapRevisionStore) Save(ctx context.Context, dc *mosniov1.DynamicConfig, rev *mosniov1.DynamicConfigRevision) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rev.ConfigMap,
+			Namespace: dc.Namespace,
+			Labels: map[string]string{
+				dynamicConfigRevisionLabel: dc.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: mosniov1.GroupVersion.String(),
+					Kind:       "DynamicConfig",
+					Name:       dc.Name,
+					UID:        dc.UID,
+				},
+			},
+		},
+		Data: map[string]string{
+			dynamicConfigRevisionKey: string(dc.Spec.Config.Raw),
+		},
+	}
+	_, err := s.client.CoreV1().ConfigMaps(dc.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ConfigMap %s/%s: %w", dc.Namespace, rev.ConfigMap, err)
+	}
+
+	// The ConfigMap is named after the hash, so the existing one usually has the same config.
+	// Overwrite it in case of a hash prefix collision or a modification by others.
+	_, err = s.Load(ctx, dc, rev)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, errRevisionMismatched) {
+		return err
+	}
+	_, err = s.client.CoreV1().ConfigMaps(dc.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %w", dc.Namespace, rev.ConfigMap, err)
+	}
+	return nil
+}
+
+func (s *configMapRevisionStore) Load(ctx context.Context, dc *mosniov1.DynamicConfig, rev *mosniov1.DynamicConfigRevision) ([]byte, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(dc.Namespace).Get(ctx, rev.ConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", dc.Namespace, rev.ConfigMap, err)
+	}
+	cfg := []byte(cm.Data[dynamicConfigRevisionKey])
+	sum := sha256.Sum256(cfg)
+	if hex.EncodeToString(sum[:]) != rev.Hash {
+		return nil, fmt.Errorf("%w, ConfigMap: %s/%s", errRevisionMismatched, dc.Namespace, rev.ConfigMap)
+	}
+	return cfg, nil
+}
+
+func (s *configMapRevisionStore) Delete(ctx context.Context, dc *mosniov1.DynamicConfig, rev *mosniov1.DynamicConfigRevision) error {
+	err := s.client.CoreV1().ConfigMaps(dc.Namespace).Delete(ctx, rev.ConfigMap, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ConfigMap %s/%s: %w", dc.Namespace, rev.ConfigMap, err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+func newRevisionTestDynamicConfig(generation int64, cfg string) *mosniov1.DynamicConfig {
+	return &mosniov1.DynamicConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dc",
+			Namespace:  "ns",
+			UID:        "uid",
+			Generation: generation,
+		},
+		Spec: mosniov1.DynamicConfigSpec{
+			Type:   "cb",
+			Config: runtime.RawExtension{Raw: []byte(cfg)},
+		},
+	}
+}
+
+func TestConfigMapRevisionStore(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapRevisionStore(client)
+
+	dc := newRevisionTestDynamicConfig(1, `{"key":"value"}`)
+	rev := dc.CurrentRevision()
+	require.NoError(t, store.Save(ctx, dc, rev))
+	// saving the same revision again is fine
+	require.NoError(t, store.Save(ctx, dc, rev))
+
+	cm, err := client.CoreV1().ConfigMaps("ns").Get(ctx, rev.ConfigMap, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "dc", cm.Labels[dynamicConfigRevisionLabel])
+	assert.Equal(t, "DynamicConfig", cm.OwnerReferences[0].Kind)
+	assert.Equal(t, "uid", string(cm.OwnerReferences[0].UID))
+
+	cfg, err := store.Load(ctx, dc, rev)
+	require.NoError(t, err)
+	assert.Equal(t, `{"key":"value"}`, string(cfg))
+
+	// modified by others
+	cm.Data[dynamicConfigRevisionKey] = `{"key":"modified"}`
+	_, err = client.CoreV1().ConfigMaps("ns").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = store.Load(ctx, dc, rev)
+	assert.ErrorIs(t, err, errRevisionMismatched)
+	require.NoError(t, store.Save(ctx, dc, rev))
+	cfg, err = store.Load(ctx, dc, rev)
+	require.NoError(t, err)
+	assert.Equal(t, `{"key":"value"}`, string(cfg))
+
+	require.NoError(t, store.Delete(ctx, dc, rev))
+	require.NoError(t, store.Delete(ctx, dc, rev))
+	_, err = store.Load(ctx, dc, rev)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestDynamicConfigDispatchRevision(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	r := &DynamicConfigReconciler{
+		RevisionStore: NewConfigMapRevisionStore(client),
+	}
+
+	dc := newRevisionTestDynamicConfig(1, `{"key":"v1"}`)
+	dispatched, err := r.dispatchRevision(ctx, dc)
+	require.NoError(t, err)
+	assert.Equal(t, "1", dispatched.Version)
+	assert.Equal(t, `{"key":"v1"}`, string(dispatched.Config))
+	assert.Equal(t, 1, len(dc.Status.History))
+	v1 := dc.Status.History[0]
+
+	dc.Generation = 2
+	dc.Spec.Config.Raw = []byte(`{"key":"v2"}`)
+	dispatched, err = r.dispatchRevision(ctx, dc)
+	require.NoError(t, err)
+	assert.Equal(t, "2", dispatched.Version)
+	assert.Equal(t, 2, len(dc.Status.History))
+
+	// roll back
+	dc.Generation = 3
+	dc.Spec.RollbackTo = "1"
+	dispatched, err = r.dispatchRevision(ctx, dc)
+	require.NoError(t, err)
+	assert.Equal(t, "1", dispatched.Version)
+	assert.Equal(t, `{"key":"v1"}`, string(dispatched.Config))
+
+	// the stale revisions are removed
+	limit := int32(0)
+	dc.Generation = 4
+	dc.Spec.RollbackTo = ""
+	dc.Spec.RevisionHistoryLimit = &limit
+	dc.Spec.Config.Raw = []byte(`{"key":"v4"}`)
+	_, err = r.dispatchRevision(ctx, dc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(dc.Status.History))
+	_, err = client.CoreV1().ConfigMaps("ns").Get(ctx, v1.ConfigMap, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// the stored config is lost
+	dc.Status.History = append([]mosniov1.DynamicConfigRevision{v1}, dc.Status.History...)
+	dc.Generation = 5
+	dc.Spec.RollbackTo = "1"
+	dispatched, err = r.dispatchRevision(ctx, dc)
+	require.NoError(t, err)
+	assert.Nil(t, dispatched)
+	assert.False(t, dc.IsValid())
+
+	// rolling back is not supported without the store
+	r.RevisionStore = nil
+	dc.Generation = 6
+	dispatched, err = r.dispatchRevision(ctx, dc)
+	require.NoError(t, err)
+	assert.Nil(t, dispatched)
+	assert.False(t, dc.IsValid())
+	assert.Contains(t, dc.Status.Conditions[0].Message, "revision store is not configured")
+}
@@ -0,0 +1,557 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/pkg/component"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+const (
+	// The proxy which doesn't report for a while is considered gone
+	dynamicConfigStatusExpiration = 3 * dynamicconfig.StatusReportInterval
+	// The collected statuses are shared and written at most once per interval
+	dynamicConfigStatusSyncInterval = 10 * time.Second
+	// The authenticated token is cached for a while to avoid a TokenReview per report
+	dynamicConfigStatusTokenTTL = 5 * time.Minute
+	// The rejected token is also cached, so that a bad reporter can't trigger a TokenReview per report
+	dynamicConfigStatusRejectedTokenTTL = time.Minute
+	// The TokenReviews are rate limited to protect the API server
+	dynamicConfigStatusReviewRate  = 10
+	dynamicConfigStatusReviewBurst = 20
+	// The max size of a report
+	dynamicConfigStatusMaxBodySize = 1 << 20
+
+	dynamicConfigStatusLabel     = "htnn.mosn.io/dynamicconfig-status"
+	dynamicConfigStatusKey       = "status"
+	dynamicConfigStatusPrefix    = "htnn-dynamicconfig-status-"
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+	podNameExtraKey              = "authentication.kubernetes.io/pod-name"
+)
+
+type proxyApplyStatus struct {
+	dynamicconfig.ApplyStatus
+
+	proxy      string
+	receivedAt time.Time
+}
+
+// versionSummary counts the proxies which report the same version of a DynamicConfig
+type versionSummary struct {
+	Applied int `json:"applied,omitempty"`
+	Failed  int `json:"failed,omitempty"`
+	// the first failure, sorted by the proxy
+	FailedProxy string `json:"failedProxy,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// replicaSummary is the statuses collected by a replica. It's shared with the leader via a ConfigMap.
+// Only the summary is shared so that the size doesn't grow with the number of proxies.
+type replicaSummary struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	// namespace/type -> version -> summary
+	Configs map[string]map[string]*versionSummary `json:"configs,omitempty"`
+}
+
+type authenticatedToken struct {
+	proxy     string
+	err       error
+	expiredAt time.Time
+}
+
+// DynamicConfigStatusOptions configures the DynamicConfigStatusCollector
+type DynamicConfigStatusOptions struct {
+	// Client is used to authenticate the reporters and share the collected statuses between replicas
+	Client kubernetes.Interface
+	// Namespace is where the collected statuses are shared
+	Namespace string
+	// Replica identifies the current replica, usually the pod name
+	Replica string
+	// IsLeader reports whether the current replica writes the status
+	IsLeader func() bool
+	// Reporters are the service accounts allowed to report, in the format of "namespace/name".
+	// Use "namespace/*" to allow all the service accounts in the namespace.
+	// Default to the ones configured via HTNN_DYNAMIC_CONFIG_STATUS_REPORTERS.
+	Reporters []string
+}
+
+// DynamicConfigStatusCollector collects the apply status reported by the data plane,
+// and reflects them to the Applied condition of the DynamicConfig.
+// The reporters are authenticated by their service account tokens bound to the StatusReportAudience,
+// and only the configured service accounts are allowed. As the data plane may
+// report to any replica, each replica shares what it collects, and only the leader aggregates
+// them and writes the status periodically.
+type DynamicConfigStatusCollector struct {
+	component.ResourceManager
+
+	opts DynamicConfigStatusOptions
+
+	lock sync.Mutex
+	// namespace/type -> proxy -> status
+	statuses map[string]map[string]*proxyApplyStatus
+	// proxy -> namespace/type
+	proxyConfigs map[string][]string
+
+	published   map[string]map[string]*versionSummary
+	publishedAt time.Time
+
+	tokenLock sync.Mutex
+	// sha256 of the token -> result
+	tokens        map[string]*authenticatedToken
+	reviewLimiter *rate.Limiter
+
+	// namespace/type -> summary, the aggregated result of all replicas
+	aggregated map[string]map[string]*versionSummary
+}
+
+func NewDynamicConfigStatusCollector(manager component.ResourceManager, opts DynamicConfigStatusOptions) *DynamicConfigStatusCollector {
+	if opts.Reporters == nil {
+		opts.Reporters = config.DynamicConfigStatusReporters()
+	}
+	return &DynamicConfigStatusCollector{
+		ResourceManager: manager,
+		opts:            opts,
+		statuses:        make(map[string]map[string]*proxyApplyStatus),
+		proxyConfigs:    make(map[string][]string),
+		tokens:          make(map[string]*authenticatedToken),
+		reviewLimiter:   rate.NewLimiter(dynamicConfigStatusReviewRate, dynamicConfigStatusReviewBurst),
+	}
+}
+
+var errTooManyReviews = errors.New("too many token reviews")
+
+func (c *DynamicConfigStatusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	proxy, err := c.authenticate(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, errTooManyReviews) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		log.Infof("failed to authenticate DynamicConfig status reporter: %v", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var report dynamicconfig.StatusReport
+	body := http.MaxBytesReader(w, r.Body, dynamicConfigStatusMaxBodySize)
+	if err := json.NewDecoder(body).Decode(&report); err != nil || report.Proxy == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if report.Proxy != proxy {
+		log.Infof("proxy %s reports DynamicConfig status as %s", proxy, report.Proxy)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	c.Record(&report)
+	w.WriteHeader(http.StatusOK)
+}
+
+// authenticate returns the proxy, in the format of "namespace/pod", which the token belongs to
+func (c *DynamicConfigStatusCollector) authenticate(ctx context.Context, token string) (string, error) {
+	now := time.Now()
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	c.tokenLock.Lock()
+	for t, at := range c.tokens {
+		if now.After(at.expiredAt) {
+			delete(c.tokens, t)
+		}
+	}
+	at := c.tokens[key]
+	c.tokenLock.Unlock()
+	if at != nil {
+		return at.proxy, at.err
+	}
+
+	if !c.reviewLimiter.Allow() {
+		return "", errTooManyReviews
+	}
+	tr, err := c.opts.Client.AuthenticationV1().TokenReviews().Create(ctx, &authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{dynamicconfig.StatusReportAudience},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		// don't cache the result if the review itself fails
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	proxy, err := c.checkTokenReview(tr)
+
+	ttl := dynamicConfigStatusTokenTTL
+	if err != nil {
+		ttl = dynamicConfigStatusRejectedTokenTTL
+	}
+	c.tokenLock.Lock()
+	c.tokens[key] = &authenticatedToken{
+		proxy:     proxy,
+		err:       err,
+		expiredAt: now.Add(ttl),
+	}
+	c.tokenLock.Unlock()
+	return proxy, err
+}
+
+// checkTokenReview checks the result of the TokenReview and if the service account is allowed to report
+func (c *DynamicConfigStatusCollector) checkTokenReview(tr *authnv1.TokenReview) (string, error) {
+	if !tr.Status.Authenticated {
+		return "", fmt.Errorf("token is not authenticated: %s", tr.Status.Error)
+	}
+	if !slices.Contains(tr.Status.Audiences, dynamicconfig.StatusReportAudience) {
+		return "", fmt.Errorf("token is not bound to audience %s", dynamicconfig.StatusReportAudience)
+	}
+
+	user := tr.Status.User
+	sa, ok := strings.CutPrefix(user.Username, serviceAccountUsernamePrefix)
+	if !ok {
+		return "", fmt.Errorf("user %s is not a service account", user.Username)
+	}
+	ns, name, _ := strings.Cut(sa, ":")
+	if !c.isReporterAllowed(ns, name) {
+		return "", fmt.Errorf("service account %s/%s is not allowed to report", ns, name)
+	}
+	pods := user.Extra[podNameExtraKey]
+	if len(pods) != 1 {
+		return "", fmt.Errorf("token of service account %s is not bound to a pod", sa)
+	}
+	return ns + "/" + pods[0], nil
+}
+
+func (c *DynamicConfigStatusCollector) isReporterAllowed(ns, name string) bool {
+	for _, reporter := range c.opts.Reporters {
+		if reporter == ns+"/"+name || reporter == ns+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Record replaces the statuses reported by the proxy previously with the given report
+func (c *DynamicConfigStatusCollector) Record(report *dynamicconfig.StatusReport) {
+	now := time.Now()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range c.proxyConfigs[report.Proxy] {
+		delete(c.statuses[key], report.Proxy)
+	}
+
+	keys := make([]string, 0, len(report.Statuses))
+	for _, st := range report.Statuses {
+		key := st.Namespace + "/" + st.Name
+		if c.statuses[key] == nil {
+			c.statuses[key] = make(map[string]*proxyApplyStatus)
+		}
+		c.statuses[key][report.Proxy] = &proxyApplyStatus{
+			ApplyStatus: st,
+			proxy:       report.Proxy,
+			receivedAt:  now,
+		}
+		keys = append(keys, key)
+	}
+	c.proxyConfigs[report.Proxy] = keys
+}
+
+// summarize summarizes the statuses collected by this replica, and removes the expired ones
+func (c *DynamicConfigStatusCollector) summarize(now time.Time) *replicaSummary {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	summary := &replicaSummary{
+		UpdatedAt: now,
+		Configs:   make(map[string]map[string]*versionSummary, len(c.statuses)),
+	}
+	for key, proxies := range c.statuses {
+		res := make([]*proxyApplyStatus, 0, len(proxies))
+		for proxy, st := range proxies {
+			if now.Sub(st.receivedAt) > dynamicConfigStatusExpiration {
+				delete(proxies, proxy)
+				// all the statuses in a report are received at the same time
+				delete(c.proxyConfigs, proxy)
+				continue
+			}
+			res = append(res, st)
+		}
+		if len(res) == 0 {
+			delete(c.statuses, key)
+			continue
+		}
+		sort.Slice(res, func(i, j int) bool {
+			return res[i].proxy < res[j].proxy
+		})
+
+		versions := make(map[string]*versionSummary)
+		for _, st := range res {
+			vs := versions[st.Version]
+			if vs == nil {
+				vs = &versionSummary{}
+				versions[st.Version] = vs
+			}
+			if st.Error == "" {
+				vs.Applied++
+				continue
+			}
+			vs.Failed++
+			if vs.FailedProxy == "" {
+				vs.FailedProxy = st.proxy
+				vs.Error = st.Error
+			}
+		}
+		summary.Configs[key] = versions
+	}
+	return summary
+}
+
+func (c *DynamicConfigStatusCollector) configMapName() string {
+	return dynamicConfigStatusPrefix + c.opts.Replica
+}
+
+// publish shares the statuses collected by this replica if they are changed. They are also
+// republished periodically to tell the leader that this replica is alive.
+func (c *DynamicConfigStatusCollector) publish(ctx context.Context, now time.Time) error {
+	summary := c.summarize(now)
+	if reflect.DeepEqual(summary.Configs, c.published) &&
+		now.Sub(c.publishedAt) < dynamicconfig.StatusReportInterval {
+		return nil
+	}
+
+	if err := c.writeSummary(ctx, summary); err != nil {
+		// retry in the next round
+		return err
+	}
+	c.published = summary.Configs
+	c.publishedAt = now
+	return nil
+}
+
+func (c *DynamicConfigStatusCollector) writeSummary(ctx context.Context, summary *replicaSummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	cms := c.opts.Client.CoreV1().ConfigMaps(c.opts.Namespace)
+	cm, err := cms.Get(ctx, c.configMapName(), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ConfigMap: %w", err)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.configMapName(),
+				Namespace: c.opts.Namespace,
+				Labels: map[string]string{
+					dynamicConfigStatusLabel: "true",
+				},
+			},
+			Data: map[string]string{
+				dynamicConfigStatusKey: string(data),
+			},
+		}
+		if _, err := cms.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create ConfigMap: %w", err)
+		}
+		return nil
+	}
+
+	cm.Data = map[string]string{
+		dynamicConfigStatusKey: string(data),
+	}
+	if _, err := cms.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// aggregate merges the statuses shared by all the replicas, and removes the ones from the gone replicas
+func (c *DynamicConfigStatusCollector) aggregate(ctx context.Context, now time.Time) error {
+	cms := c.opts.Client.CoreV1().ConfigMaps(c.opts.Namespace)
+	list, err := cms.List(ctx, metav1.ListOptions{
+		LabelSelector: dynamicConfigStatusLabel + "=true",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list ConfigMap: %w", err)
+	}
+
+	aggregated := make(map[string]map[string]*versionSummary)
+	for _, cm := range list.Items {
+		var summary replicaSummary
+		if err := json.Unmarshal([]byte(cm.Data[dynamicConfigStatusKey]), &summary); err != nil {
+			log.Errorf("failed to unmarshal DynamicConfig status in ConfigMap %s: %v", cm.Name, err)
+			continue
+		}
+		if now.Sub(summary.UpdatedAt) > dynamicConfigStatusExpiration {
+			err := cms.Delete(ctx, cm.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Errorf("failed to delete expired ConfigMap %s: %v", cm.Name, err)
+			}
+			continue
+		}
+
+		for key, versions := range summary.Configs {
+			if aggregated[key] == nil {
+				aggregated[key] = make(map[string]*versionSummary)
+			}
+			for version, vs := range versions {
+				agg := aggregated[key][version]
+				if agg == nil {
+					agg = &versionSummary{}
+					aggregated[key][version] = agg
+				}
+				agg.Applied += vs.Applied
+				agg.Failed += vs.Failed
+				if vs.FailedProxy != "" && (agg.FailedProxy == "" || vs.FailedProxy < agg.FailedProxy) {
+					agg.FailedProxy = vs.FailedProxy
+					agg.Error = vs.Error
+				}
+			}
+		}
+	}
+
+	c.aggregated = aggregated
+	return nil
+}
+
+func (c *DynamicConfigStatusCollector) setApplied(dynamicConfig *mosniov1.DynamicConfig) {
+	versions := c.aggregated[dynamicConfig.Namespace+"/"+dynamicConfig.Spec.Type]
+	if len(versions) == 0 {
+		return
+	}
+
+	rev := dynamicConfig.DispatchedRevision()
+	if rev == nil {
+		return
+	}
+	version := rev.Version
+	total := 0
+	for _, vs := range versions {
+		total += vs.Applied + vs.Failed
+	}
+	cur := versions[version]
+	if cur == nil {
+		cur = &versionSummary{}
+	}
+
+	if cur.Failed > 0 {
+		dynamicConfig.SetApplied(mosniov1.ReasonApplyFailed,
+			fmt.Sprintf("%d/%d proxies failed to apply version %s, proxy %s: %s",
+				cur.Failed, total, version, cur.FailedProxy, cur.Error))
+	} else if cur.Applied == total {
+		dynamicConfig.SetApplied(mosniov1.ReasonApplied,
+			fmt.Sprintf("%d/%d proxies applied version %s", cur.Applied, total, version))
+	} else {
+		dynamicConfig.SetApplied(mosniov1.ReasonPending,
+			fmt.Sprintf("%d/%d proxies applied version %s", cur.Applied, total, version))
+	}
+}
+
+// UpdateDynamicConfigs updates the Applied condition of the DynamicConfigs according to the aggregated statuses
+func (c *DynamicConfigStatusCollector) UpdateDynamicConfigs(ctx context.Context) error {
+	var dynamicConfigs mosniov1.DynamicConfigList
+	if err := c.List(ctx, &dynamicConfigs); err != nil {
+		return fmt.Errorf("failed to list DynamicConfig: %w", err)
+	}
+
+	var errs []error
+	for i := range dynamicConfigs.Items {
+		dynamicConfig := &dynamicConfigs.Items[i]
+		if !dynamicConfig.IsValid() {
+			continue
+		}
+
+		c.setApplied(dynamicConfig)
+		if !dynamicConfig.Status.IsChanged() {
+			continue
+		}
+		dynamicConfig.Status.Reset()
+		if err := c.UpdateStatus(ctx, dynamicConfig, &dynamicConfig.Status); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update DynamicConfig status: %w, namespacedName: %v",
+				err,
+				types.NamespacedName{Name: dynamicConfig.Name, Namespace: dynamicConfig.Namespace}))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync shares the statuses collected by this replica, and writes the aggregated statuses if this replica is the leader
+func (c *DynamicConfigStatusCollector) Sync(ctx context.Context) error {
+	now := time.Now()
+	if err := c.publish(ctx, now); err != nil {
+		return fmt.Errorf("failed to publish DynamicConfig status: %w", err)
+	}
+	if c.opts.IsLeader != nil && !c.opts.IsLeader() {
+		return nil
+	}
+	if err := c.aggregate(ctx, now); err != nil {
+		return fmt.Errorf("failed to aggregate DynamicConfig status: %w", err)
+	}
+	return c.UpdateDynamicConfigs(ctx)
+}
+
+// Start syncs the statuses periodically until the ctx is done. It should run in every replica.
+func (c *DynamicConfigStatusCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(dynamicConfigStatusSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.Sync(ctx); err != nil {
+				log.Errorf("failed to sync DynamicConfig status: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	authnv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+type fakeDynamicConfigManager struct {
+	items   []mosniov1.DynamicConfig
+	updated int
+}
+
+func (m *fakeDynamicConfigManager) Get(_ context.Context, _ client.ObjectKey, _ client.Object) error {
+	return nil
+}
+
+func (m *fakeDynamicConfigManager) List(_ context.Context, list client.ObjectList) error {
+	l := list.(*mosniov1.DynamicConfigList)
+	for _, item := range m.items {
+		l.Items = append(l.Items, *item.DeepCopy())
+	}
+	return nil
+}
+
+func (m *fakeDynamicConfigManager) UpdateStatus(_ context.Context, obj client.Object, _ any) error {
+	c := obj.(*mosniov1.DynamicConfig)
+	for i := range m.items {
+		if m.items[i].Name == c.Name && m.items[i].Namespace == c.Namespace {
+			m.items[i].Status = c.Status
+		}
+	}
+	m.updated++
+	return nil
+}
+
+func (m *fakeDynamicConfigManager) appliedCondition() *metav1.Condition {
+	for _, cond := range m.items[0].Status.Conditions {
+		if cond.Type == string(mosniov1.ConditionApplied) {
+			return &cond
+		}
+	}
+	return nil
+}
+
+func newFakeTokenReviewClient(reviews *int) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		*reviews++
+		tr := action.(k8stesting.CreateAction).GetObject().(*authnv1.TokenReview)
+		// the token is in the format of "namespace/pod", or "audience:namespace/pod" if it's bound to
+		// another audience
+		token := tr.Spec.Token
+		audience := dynamicconfig.StatusReportAudience
+		if aud, rest, ok := strings.Cut(token, ":"); ok {
+			audience, token = aud, rest
+		}
+		ns, pod, ok := strings.Cut(token, "/")
+		if !ok || !slices.Contains(tr.Spec.Audiences, audience) {
+			tr.Status.Error = "bad token"
+			return true, tr, nil
+		}
+		tr.Status.Authenticated = true
+		tr.Status.Audiences = []string{audience}
+		tr.Status.User = authnv1.UserInfo{
+			Username: "system:serviceaccount:" + ns + ":default",
+			Extra: map[string]authnv1.ExtraValue{
+				podNameExtraKey: {pod},
+			},
+		}
+		return true, tr, nil
+	})
+	return client
+}
+
+func TestDynamicConfigStatusCollector(t *testing.T) {
+	dc := mosniov1.DynamicConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "dc",
+			Generation: 2,
+		},
+		Spec: mosniov1.DynamicConfigSpec{
+			Type: "cb",
+		},
+	}
+	dc.SetAccepted(mosniov1.ReasonAccepted)
+	dc.Status.Reset()
+	rm := &fakeDynamicConfigManager{items: []mosniov1.DynamicConfig{dc}}
+	reviews := 0
+	client := newFakeTokenReviewClient(&reviews)
+	leader := NewDynamicConfigStatusCollector(rm, DynamicConfigStatusOptions{
+		Client:    client,
+		Namespace: "istio-system",
+		Replica:   "leader",
+		IsLeader:  func() bool { return true },
+		Reporters: []string{"ns/default"},
+	})
+	follower := NewDynamicConfigStatusCollector(rm, DynamicConfigStatusOptions{
+		Client:    client,
+		Namespace: "istio-system",
+		Replica:   "follower",
+		IsLeader:  func() bool { return false },
+		Reporters: []string{"ns/*"},
+	})
+
+	report := func(c *DynamicConfigStatusCollector, proxy, version, errMsg string) int {
+		data, _ := json.Marshal(&dynamicconfig.StatusReport{
+			Proxy: proxy,
+			Statuses: []dynamicconfig.ApplyStatus{
+				{Namespace: "ns", Name: "cb", Version: version, Error: errMsg},
+				{Namespace: "ns", Name: "unknown", Version: "1"},
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+		req.Header.Set("Authorization", "Bearer "+proxy)
+		w := httptest.NewRecorder()
+		c.ServeHTTP(w, req)
+		return w.Code
+	}
+	sync := func() {
+		ctx := context.Background()
+		assert.Nil(t, follower.Sync(ctx))
+		assert.Nil(t, leader.Sync(ctx))
+	}
+
+	assert.Equal(t, http.StatusOK, report(leader, "ns/a", "1", ""))
+	// the status is not written until synced
+	assert.Nil(t, rm.appliedCondition())
+	sync()
+	cond := rm.appliedCondition()
+	assert.Equal(t, string(mosniov1.ReasonPending), cond.Reason)
+	assert.Equal(t, "0/1 proxies applied version 2", cond.Message)
+
+	// the proxies report to different replicas
+	assert.Equal(t, http.StatusOK, report(leader, "ns/a", "2", ""))
+	assert.Equal(t, http.StatusOK, report(follower, "ns/b", "2", ""))
+	// only the leader writes the status
+	updated := rm.updated
+	assert.Nil(t, follower.Sync(context.Background()))
+	assert.Equal(t, updated, rm.updated)
+	sync()
+	cond = rm.appliedCondition()
+	assert.Equal(t, string(mosniov1.ReasonApplied), cond.Reason)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "2/2 proxies applied version 2", cond.Message)
+
+	// no change, no update
+	updated = rm.updated
+	assert.Equal(t, http.StatusOK, report(follower, "ns/b", "2", ""))
+	sync()
+	assert.Equal(t, updated, rm.updated)
+
+	assert.Equal(t, http.StatusOK, report(follower, "ns/b", "2", "bad config"))
+	sync()
+	cond = rm.appliedCondition()
+	assert.Equal(t, string(mosniov1.ReasonApplyFailed), cond.Reason)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "1/2 proxies failed to apply version 2, proxy ns/b: bad config", cond.Message)
+
+	// the proxy is gone
+	follower.lock.Lock()
+	follower.statuses["ns/cb"]["ns/b"].receivedAt = time.Now().Add(-dynamicConfigStatusExpiration - time.Second)
+	follower.lock.Unlock()
+	sync()
+	cond = rm.appliedCondition()
+	assert.Equal(t, "1/1 proxies applied version 2", cond.Message)
+
+	// the replica is gone
+	assert.Equal(t, http.StatusOK, report(follower, "ns/b", "2", ""))
+	sync()
+	assert.Equal(t, "2/2 proxies applied version 2", rm.appliedCondition().Message)
+	cms := client.CoreV1().ConfigMaps("istio-system")
+	cm, err := cms.Get(context.Background(), "htnn-dynamicconfig-status-follower", metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Data[dynamicConfigStatusKey] = `{"updatedAt":"2024-01-01T00:00:00Z"}`
+	_, err = cms.Update(context.Background(), cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, leader.Sync(context.Background()))
+	assert.Equal(t, "1/1 proxies applied version 2", rm.appliedCondition().Message)
+	_, err = cms.Get(context.Background(), "htnn-dynamicconfig-status-follower", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// bad requests
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	leader.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{")))
+	req.Header.Set("Authorization", "Bearer ns/a")
+	w = httptest.NewRecorder()
+	leader.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// unauthenticated
+	data, _ := json.Marshal(&dynamicconfig.StatusReport{Proxy: "ns/a"})
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	w = httptest.NewRecorder()
+	leader.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer bad")
+	w = httptest.NewRecorder()
+	leader.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// spoof another proxy
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer ns/c")
+	w = httptest.NewRecorder()
+	leader.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// the body is too large
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bytes.Repeat([]byte(" "), dynamicConfigStatusMaxBodySize+1)))
+	req.Header.Set("Authorization", "Bearer ns/a")
+	w = httptest.NewRecorder()
+	leader.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDynamicConfigStatusAuthentication(t *testing.T) {
+	reviews := 0
+	c := NewDynamicConfigStatusCollector(&fakeDynamicConfigManager{}, DynamicConfigStatusOptions{
+		Client:    newFakeTokenReviewClient(&reviews),
+		Namespace: "istio-system",
+		Replica:   "leader",
+		IsLeader:  func() bool { return true },
+		Reporters: []string{"ns/default"},
+	})
+	ctx := context.Background()
+
+	proxy, err := c.authenticate(ctx, "ns/a")
+	require.NoError(t, err)
+	assert.Equal(t, "ns/a", proxy)
+	_, err = c.authenticate(ctx, "ns/a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, reviews)
+
+	// the token bound to other audiences, like the API server, is rejected
+	_, err = c.authenticate(ctx, "kube-apiserver:ns/a")
+	assert.ErrorContains(t, err, "not authenticated")
+	// the service account is not allowed
+	_, err = c.authenticate(ctx, "other/a")
+	assert.ErrorContains(t, err, "not allowed to report")
+
+	// the rejected token is cached
+	reviews = 0
+	_, err = c.authenticate(ctx, "bad")
+	assert.Error(t, err)
+	_, err = c.authenticate(ctx, "bad")
+	assert.Error(t, err)
+	assert.Equal(t, 1, reviews)
+
+	// the token reviews are rate limited
+	c.reviewLimiter = rate.NewLimiter(0, 0)
+	_, err = c.authenticate(ctx, "ns/b")
+	assert.ErrorIs(t, err, errTooManyReviews)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer ns/b")
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	// the cached tokens are not affected
+	_, err = c.authenticate(ctx, "ns/a")
+	assert.NoError(t, err)
+}
@@ -21,12 +21,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -60,6 +64,9 @@ type FilterPolicyReconciler struct {
 
 	virtualServiceIndexer *customResourceIndexer
 	httpRouteIndexer      *customResourceIndexer
+	grpcRouteIndexer      *customResourceIndexer
+	tcpRouteIndexer       *customResourceIndexer
+	tlsRouteIndexer       *customResourceIndexer
 	istioGatewayIndexer   *customResourceIndexer
 	k8sGatewayIndexer     *customResourceIndexer
 }
@@ -104,6 +111,30 @@ func NewFilterPolicyReconciler(output component.Output, manager component.Resour
 		r.addIndexer(k8sGatewayIndexer)
 	}
 
+	if config.EnableAlphaGatewayAPI() {
+		grpcRouteIndexer := &customResourceIndexer{
+			Group:          "gateway.networking.k8s.io",
+			Kind:           "GRPCRoute",
+			CustomResource: &gwapiv1a2.GRPCRoute{},
+		}
+		r.grpcRouteIndexer = grpcRouteIndexer
+		tcpRouteIndexer := &customResourceIndexer{
+			Group:          "gateway.networking.k8s.io",
+			Kind:           "TCPRoute",
+			CustomResource: &gwapiv1a2.TCPRoute{},
+		}
+		r.tcpRouteIndexer = tcpRouteIndexer
+		tlsRouteIndexer := &customResourceIndexer{
+			Group:          "gateway.networking.k8s.io",
+			Kind:           "TLSRoute",
+			CustomResource: &gwapiv1a2.TLSRoute{},
+		}
+		r.tlsRouteIndexer = tlsRouteIndexer
+		r.addIndexer(grpcRouteIndexer)
+		r.addIndexer(tcpRouteIndexer)
+		r.addIndexer(tlsRouteIndexer)
+	}
+
 	return r
 }
 
@@ -135,24 +166,56 @@ func (r *FilterPolicyReconciler) NeedReconcile(ctx context.Context, meta compone
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
-func (r *FilterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *FilterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	reconcilationStart := time.Now()
 	defer func() {
 		reconcilationDuration := time.Since(reconcilationStart).Seconds()
 		metrics.FPReconcileDurationDistribution.Record(reconcilationDuration)
+		if err != nil {
+			metrics.ReconcileErrorsTotal.With("filterpolicy").Increment()
+		}
 	}()
 
 	log.Info("Reconcile FilterPolicy")
 
 	var policies mosniov1.FilterPolicyList
-	initState, err := r.policyToTranslationState(ctx, &policies)
+	if err := r.listPolicies(ctx, &policies); err != nil {
+		return ctrl.Result{}, err
+	}
+	finalState, err := r.translate(ctx, &policies)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if initState == nil {
+	if finalState == nil {
 		return ctrl.Result{}, nil
 	}
 
+	generatedEnvoyFilters := finalState.EnvoyFilters
+	recordGeneratedEnvoyFilters("FilterPolicy", generatedEnvoyFilters)
+	err = r.output.FromFilterPolicy(ctx, generatedEnvoyFilters)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	rejectPolicies(&policies, finalState.RejectedPolicies)
+	setPolicyAttachments(&policies, finalState.PolicyAttachments)
+	setPoliciesNearSizeLimit(&policies, finalState.PoliciesNearSizeLimit)
+	recordPolicyStatus(&policies)
+	err = r.updatePolicies(ctx, &policies)
+	return ctrl.Result{}, err
+}
+
+// translate translates the policies to the final state. It returns nil if there is nothing to translate
+// or the translation fails with a non-retryable error.
+func (r *FilterPolicyReconciler) translate(ctx context.Context, policies *mosniov1.FilterPolicyList) (*translation.FinalState, error) {
+	initState, err := r.policyToTranslationState(ctx, policies)
+	if err != nil {
+		return nil, err
+	}
+	if initState == nil {
+		return nil, nil
+	}
+
 	start := time.Now()
 	finalState, err := initState.Process(ctx)
 	processDurationInSecs := time.Since(start).Seconds()
@@ -160,17 +223,80 @@ func (r *FilterPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err != nil {
 		log.Errorf("failed to process state: %v", err)
 		// there is no retryable err during processing
-		return ctrl.Result{}, nil
+		return nil, nil
 	}
+	return finalState, nil
+}
 
-	generatedEnvoyFilters := finalState.EnvoyFilters
-	err = r.output.FromFilterPolicy(ctx, generatedEnvoyFilters)
-	if err != nil {
-		return ctrl.Result{}, err
+// checkPolicyPlugins returns an error if the policy uses a plugin which can't be served by the output
+func checkPolicyPlugins(checker component.PluginChecker, policy *mosniov1.FilterPolicy) error {
+	names := make([]string, 0, len(policy.Spec.Filters))
+	for name := range policy.Spec.Filters {
+		names = append(names, name)
 	}
+	for _, sub := range policy.Spec.SubPolicies {
+		for name := range sub.Filters {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := checker.CheckPlugin(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	err = r.updatePolicies(ctx, &policies)
-	return ctrl.Result{}, err
+// rejectPolicies marks the policies excluded from some routes as rejected. As the policies are
+// accepted again in the next translation, the rejection is re-evaluated in each reconciliation.
+func rejectPolicies(policies *mosniov1.FilterPolicyList, rejected map[string]string) {
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		msg, ok := rejected[policy.Namespace+"/"+policy.Name]
+		if !ok || !policy.IsValid() {
+			continue
+		}
+		policy.SetAccepted(mosniov1.PolicyReasonRejected, msg)
+	}
+}
+
+func setPoliciesNearSizeLimit(policies *mosniov1.FilterPolicyList, warnings map[string]string) {
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		policy.SetSizeLimitApproached(warnings[policy.Namespace+"/"+policy.Name])
+	}
+}
+
+func setPolicyAttachments(policies *mosniov1.FilterPolicyList, attachments map[string][]mosniov1.FilterPolicyAttachment) {
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.FromHTTPFilterPolicy() {
+			// HTTPFilterPolicy is deprecated, so we don't report attachments for it
+			continue
+		}
+		policy.SetAttachments(attachments[policy.Namespace+"/"+policy.Name])
+	}
+}
+
+func recordPolicyStatus(policies *mosniov1.FilterPolicyList) {
+	counts := map[string]int{
+		string(gwapiv1a2.PolicyReasonAccepted):       0,
+		string(gwapiv1a2.PolicyReasonInvalid):        0,
+		string(gwapiv1a2.PolicyReasonTargetNotFound): 0,
+		string(mosniov1.PolicyReasonRejected):        0,
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		cond := apimeta.FindStatusCondition(policy.Status.Conditions, string(gwapiv1a2.PolicyConditionAccepted))
+		if cond == nil {
+			continue
+		}
+		counts[cond.Reason]++
+	}
+	for status, n := range counts {
+		metrics.FPCount.With(status).Record(float64(n))
+	}
 }
 
 func (r *FilterPolicyReconciler) resolveVirtualService(ctx context.Context,
@@ -303,88 +429,283 @@ func (r *FilterPolicyReconciler) resolveHTTPRoute(ctx context.Context,
 		return nil
 	}
 
-	accepted := false
 	gws := initState.GetGatewaysWithHTTPRoute(&route)
 	if len(gws) > 0 {
-		for _, gateway := range gws {
-			key := getK8sKey(gateway.Namespace, gateway.Name)
-			gwIdx[key] = append(gwIdx[key], policy)
+		indexGateways(gws, policy, gwIdx)
+	} else {
+		gws, err = r.resolveParentGateways(ctx, policy, &route, route.Spec.ParentRefs, "", gwIdx)
+		if err != nil {
+			return err
 		}
+	}
 
-		accepted = true
+	if len(gws) > 0 {
+		initState.AddPolicyForHTTPRoute(policy, &route, gws)
+		policy.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	} else {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "all gateways are not found or unsupported")
+	}
 
+	return nil
+}
+
+func (r *FilterPolicyReconciler) resolveGRPCRoute(ctx context.Context,
+	policy *mosniov1.FilterPolicy, initState *translation.InitState, gwIdx map[string][]*mosniov1.FilterPolicy) error {
+
+	ref := policy.Spec.TargetRef
+	nsName := types.NamespacedName{Name: string(ref.Name), Namespace: policy.Namespace}
+	var route gwapiv1a2.GRPCRoute
+	err := r.Get(ctx, nsName, &route)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get GRPCRoute: %w, NamespacedName: %v", err, nsName)
+		}
+
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound)
+		return nil
+	}
+
+	gws := initState.GetGatewaysWithGRPCRoute(&route)
+	if len(gws) > 0 {
+		indexGateways(gws, policy, gwIdx)
 	} else {
-		gws = make([]*gwapiv1b1.Gateway, 0, len(route.Spec.ParentRefs))
-		ns := route.Namespace
+		gws, err = r.resolveParentGateways(ctx, policy, &route, route.Spec.ParentRefs, "", gwIdx)
+		if err != nil {
+			return err
+		}
+	}
 
-		for _, ref := range route.Spec.ParentRefs {
-			if ref.Group != nil && *ref.Group != gwapiv1.GroupName {
-				continue
-			}
-			if ref.Kind != nil && *ref.Kind != gwapiv1.Kind("Gateway") {
-				continue
-			}
-			if ref.Namespace != nil && *ref.Namespace != gwapiv1.Namespace(ns) {
-				log.Infof("skip gateway from other namespace, name: %s, namespace: %s, gateway: %v", route.Name, route.Namespace, ref)
-				continue
-			}
+	if len(gws) > 0 {
+		initState.AddPolicyForGRPCRoute(policy, &route, gws)
+		policy.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	} else {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "all gateways are not found or unsupported")
+	}
 
-			key := getK8sKey(ns, string(ref.Name))
-			// We index the gateway regardless of whether it is valid or not.
-			// Otherwise, we don't know whether the gateway is changed from invalid to valid.
-			gwIdx[key] = append(gwIdx[key], policy)
+	return nil
+}
 
-			var gw gwapiv1b1.Gateway
-			gwNsName := types.NamespacedName{Name: string(ref.Name), Namespace: ns}
-			err = r.Get(ctx, gwNsName, &gw)
-			if err != nil {
-				if !apierrors.IsNotFound(err) {
-					return err
-				}
-				log.Infof("gateway not found, name: %s, namespace: %s, gateway: %v", route.Name, route.Namespace, ref)
+func (r *FilterPolicyReconciler) resolveTCPRoute(ctx context.Context,
+	policy *mosniov1.FilterPolicy, initState *translation.InitState, gwIdx map[string][]*mosniov1.FilterPolicy) error {
+
+	ref := policy.Spec.TargetRef
+	nsName := types.NamespacedName{Name: string(ref.Name), Namespace: policy.Namespace}
+	var route gwapiv1a2.TCPRoute
+	err := r.Get(ctx, nsName, &route)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get TCPRoute: %w, NamespacedName: %v", err, nsName)
+		}
+
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound)
+		return nil
+	}
+
+	gws, err := r.resolveParentGateways(ctx, policy, &route, route.Spec.ParentRefs, gwapiv1.TCPProtocolType, gwIdx)
+	if err != nil {
+		return err
+	}
+
+	var routes gwapiv1a2.TCPRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return fmt.Errorf("failed to list TCPRoute: %w", err)
+	}
+	others := make([]l4Route, 0, len(routes.Items))
+	for i := range routes.Items {
+		others = append(others, l4Route{Object: &routes.Items[i], parentRefs: routes.Items[i].Spec.ParentRefs})
+	}
+	if msg := sharedL4Listener(l4Route{Object: &route, parentRefs: route.Spec.ParentRefs}, others, gws, gwapiv1.TCPProtocolType); msg != "" {
+		policy.SetAccepted(mosniov1.PolicyReasonRejected, msg)
+		return nil
+	}
+
+	if len(gws) > 0 {
+		initState.AddPolicyForTCPRoute(policy, &route, gws)
+		policy.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	} else {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "all gateways are not found or unsupported")
+	}
+
+	return nil
+}
+
+func (r *FilterPolicyReconciler) resolveTLSRoute(ctx context.Context,
+	policy *mosniov1.FilterPolicy, initState *translation.InitState, gwIdx map[string][]*mosniov1.FilterPolicy) error {
+
+	ref := policy.Spec.TargetRef
+	nsName := types.NamespacedName{Name: string(ref.Name), Namespace: policy.Namespace}
+	var route gwapiv1a2.TLSRoute
+	err := r.Get(ctx, nsName, &route)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get TLSRoute: %w, NamespacedName: %v", err, nsName)
+		}
+
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound)
+		return nil
+	}
+
+	gws, err := r.resolveParentGateways(ctx, policy, &route, route.Spec.ParentRefs, gwapiv1.TLSProtocolType, gwIdx)
+	if err != nil {
+		return err
+	}
+
+	var routes gwapiv1a2.TLSRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return fmt.Errorf("failed to list TLSRoute: %w", err)
+	}
+	others := make([]l4Route, 0, len(routes.Items))
+	for i := range routes.Items {
+		others = append(others, l4Route{Object: &routes.Items[i], parentRefs: routes.Items[i].Spec.ParentRefs})
+	}
+	if msg := sharedL4Listener(l4Route{Object: &route, parentRefs: route.Spec.ParentRefs}, others, gws, gwapiv1.TLSProtocolType); msg != "" {
+		policy.SetAccepted(mosniov1.PolicyReasonRejected, msg)
+		return nil
+	}
+
+	if len(gws) > 0 {
+		initState.AddPolicyForTLSRoute(policy, &route, gws)
+		policy.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	} else {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "all gateways are not found or unsupported")
+	}
+
+	return nil
+}
+
+type l4Route struct {
+	client.Object
+	parentRefs []gwapiv1.ParentReference
+}
+
+// parentRefsOfGateway returns the parentRefs which point to the given gateway
+func parentRefsOfGateway(route l4Route, gw *gwapiv1b1.Gateway) []gwapiv1.ParentReference {
+	var refs []gwapiv1.ParentReference
+	for _, ref := range route.parentRefs {
+		if ref.Group != nil && *ref.Group != gwapiv1.GroupName {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != gwapiv1.Kind("Gateway") {
+			continue
+		}
+		ns := route.GetNamespace()
+		if ref.Namespace != nil {
+			ns = string(*ref.Namespace)
+		}
+		if ns != gw.Namespace || string(ref.Name) != gw.Name {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// sharedL4Listener returns the reason if a listener attached by the route is also attached by other routes.
+// As the plugins of TCPRoute and TLSRoute are applied to the whole listener, the policy targeting the route
+// would affect the traffic of the other routes, which may belong to other namespaces.
+func sharedL4Listener(route l4Route, routes []l4Route, gws []*gwapiv1b1.Gateway, protocol gwapiv1.ProtocolType) string {
+	for _, gw := range gws {
+		nn := types.NamespacedName{Namespace: gw.Namespace, Name: gw.Name}
+		refs := parentRefsOfGateway(route, gw)
+		for _, ls := range gw.Spec.Listeners {
+			if ls.Protocol != protocol || !translation.ListenerAttachedByRoute(&ls, refs, route, &nn) {
 				continue
 			}
 
-			// This part of code is similar to the code in the translation.
-			// The code in the translation filters out which listeners are matched.
-			// The code here filters out which gateways have at least one matched listeners.
-			atLeastOneListenerMatched := false
-			for _, ls := range gw.Spec.Listeners {
-				if ref.Port != nil && *ref.Port != ls.Port {
+			shared := ""
+			for _, other := range routes {
+				if other.GetNamespace() == route.GetNamespace() && other.GetName() == route.GetName() {
 					continue
 				}
-				if ref.SectionName != nil && *ref.SectionName != ls.Name {
+				if !translation.ListenerAttachedByRoute(&ls, parentRefsOfGateway(other, gw), other, &nn) {
 					continue
 				}
-
-				if !translation.AllowRoute(ls.AllowedRoutes, &route, &gwNsName) {
-					continue
+				name := other.GetNamespace() + "/" + other.GetName()
+				if shared == "" || name < shared {
+					shared = name
 				}
+			}
+			if shared != "" {
+				return fmt.Sprintf("listener %s of gateway %s is shared with route %s, the policy targeting the route can only be applied to the listener which is not shared",
+					ls.Name, nn.String(), shared)
+			}
+		}
+	}
+	return ""
+}
 
-				atLeastOneListenerMatched = true
-				break
+func indexGateways(gws []*gwapiv1b1.Gateway, policy *mosniov1.FilterPolicy, gwIdx map[string][]*mosniov1.FilterPolicy) {
+	for _, gateway := range gws {
+		key := getK8sKey(gateway.Namespace, gateway.Name)
+		gwIdx[key] = append(gwIdx[key], policy)
+	}
+}
+
+// resolveParentGateways returns the gateways which have at least one listener attached by the given xRoute.
+// If the protocol is not empty, only the listeners with the same protocol are considered.
+func (r *FilterPolicyReconciler) resolveParentGateways(ctx context.Context, policy *mosniov1.FilterPolicy,
+	route client.Object, parentRefs []gwapiv1.ParentReference, protocol gwapiv1.ProtocolType,
+	gwIdx map[string][]*mosniov1.FilterPolicy) ([]*gwapiv1b1.Gateway, error) {
+
+	gws := make([]*gwapiv1b1.Gateway, 0, len(parentRefs))
+	ns := route.GetNamespace()
+
+	for _, ref := range parentRefs {
+		if ref.Group != nil && *ref.Group != gwapiv1.GroupName {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != gwapiv1.Kind("Gateway") {
+			continue
+		}
+		if ref.Namespace != nil && *ref.Namespace != gwapiv1.Namespace(ns) {
+			log.Infof("skip gateway from other namespace, name: %s, namespace: %s, gateway: %v", route.GetName(), ns, ref)
+			continue
+		}
+
+		key := getK8sKey(ns, string(ref.Name))
+		// We index the gateway regardless of whether it is valid or not.
+		// Otherwise, we don't know whether the gateway is changed from invalid to valid.
+		gwIdx[key] = append(gwIdx[key], policy)
+
+		var gw gwapiv1b1.Gateway
+		gwNsName := types.NamespacedName{Name: string(ref.Name), Namespace: ns}
+		err := r.Get(ctx, gwNsName, &gw)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			log.Infof("gateway not found, name: %s, namespace: %s, gateway: %v", route.GetName(), ns, ref)
+			continue
+		}
+
+		// This part of code is similar to the code in the translation.
+		// The code in the translation filters out which listeners are matched.
+		// The code here filters out which gateways have at least one matched listeners.
+		atLeastOneListenerMatched := false
+		refs := []gwapiv1.ParentReference{ref}
+		for _, ls := range gw.Spec.Listeners {
+			if protocol != "" && ls.Protocol != protocol {
+				continue
 			}
 
-			if !atLeastOneListenerMatched {
-				log.Infof("no matched listeners in gateway %v, name: %s, namespace: %s, listeners: %v", ref,
-					route.Name, route.Namespace, gw.Spec.Listeners)
+			if !translation.ListenerAttachedByRoute(&ls, refs, route, &gwNsName) {
 				continue
 			}
 
-			gws = append(gws, &gw)
+			atLeastOneListenerMatched = true
+			break
+		}
 
-			accepted = true
+		if !atLeastOneListenerMatched {
+			log.Infof("no matched listeners in gateway %v, name: %s, namespace: %s, listeners: %v", ref,
+				route.GetName(), ns, gw.Spec.Listeners)
+			continue
 		}
-	}
 
-	if accepted {
-		initState.AddPolicyForHTTPRoute(policy, &route, gws)
-		policy.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
-	} else {
-		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "all gateways are not found or unsupported")
+		gws = append(gws, &gw)
 	}
 
-	return nil
+	return gws, nil
 }
 
 func (r *FilterPolicyReconciler) resolveIstioGateway(ctx context.Context,
@@ -474,6 +795,155 @@ func (r *FilterPolicyReconciler) resolveK8sGateway(ctx context.Context,
 	return nil
 }
 
+// selectorCandidates caches the resources which may be selected by targetSelector during a reconciliation
+type selectorCandidates struct {
+	virtualServices []*istiov1a3.VirtualService
+	httpRoutes      []*gwapiv1b1.HTTPRoute
+
+	virtualServicesListed bool
+	httpRoutesListed      bool
+}
+
+func (r *FilterPolicyReconciler) listVirtualServiceCandidates(ctx context.Context, c *selectorCandidates) ([]*istiov1a3.VirtualService, error) {
+	if !c.virtualServicesListed {
+		var virtualServices istiov1a3.VirtualServiceList
+		if err := r.List(ctx, &virtualServices); err != nil {
+			return nil, fmt.Errorf("failed to list VirtualService: %w", err)
+		}
+		c.virtualServices = virtualServices.Items
+		c.virtualServicesListed = true
+	}
+	return c.virtualServices, nil
+}
+
+func (r *FilterPolicyReconciler) listHTTPRouteCandidates(ctx context.Context, c *selectorCandidates) ([]*gwapiv1b1.HTTPRoute, error) {
+	if !c.httpRoutesListed {
+		var routes gwapiv1b1.HTTPRouteList
+		if err := r.List(ctx, &routes); err != nil {
+			return nil, fmt.Errorf("failed to list HTTPRoute: %w", err)
+		}
+		c.httpRoutes = make([]*gwapiv1b1.HTTPRoute, len(routes.Items))
+		for i := range routes.Items {
+			c.httpRoutes[i] = &routes.Items[i]
+		}
+		c.httpRoutesListed = true
+	}
+	return c.httpRoutes, nil
+}
+
+// resolveTargetSelector resolves the policy to each selected resource as if the policy targets to it via targetRef.
+func (r *FilterPolicyReconciler) resolveTargetSelector(ctx context.Context, policy *mosniov1.FilterPolicy,
+	initState *translation.InitState, candidates *selectorCandidates,
+	vsIdx, hrIdx, istioGwIdx, k8sGwIdx map[string][]*mosniov1.FilterPolicy) error {
+
+	// defensive code in case the webhook doesn't work
+	if policy.IsSpecChanged() {
+		err := mosniov1.ValidateFilterPolicy(policy)
+		if err != nil {
+			log.Errorf("invalid FilterPolicy, err: %v, name: %s, namespace: %s", err, policy.Name, policy.Namespace)
+			policy.SetAccepted(gwapiv1a2.PolicyReasonInvalid, err.Error())
+			policy.SetTargets(nil)
+			return nil
+		}
+	}
+	if !policy.IsValid() {
+		return nil
+	}
+
+	sel := policy.Spec.TargetSelector
+	selector, err := metav1.LabelSelectorAsSelector(&sel.Selector)
+	if err != nil {
+		// should be caught by the validation
+		policy.SetAccepted(gwapiv1a2.PolicyReasonInvalid, err.Error())
+		policy.SetTargets(nil)
+		return nil
+	}
+
+	var objs []client.Object
+	idx := vsIdx
+	if sel.Kind == "VirtualService" {
+		vss, err := r.listVirtualServiceCandidates(ctx, candidates)
+		if err != nil {
+			return err
+		}
+		for _, vs := range vss {
+			objs = append(objs, vs)
+		}
+	} else {
+		if !config.EnableGatewayAPI() {
+			policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "gateway API is disabled")
+			policy.SetTargets(nil)
+			return nil
+		}
+
+		idx = hrIdx
+		hrs, err := r.listHTTPRouteCandidates(ctx, candidates)
+		if err != nil {
+			return err
+		}
+		for _, hr := range hrs {
+			objs = append(objs, hr)
+		}
+	}
+
+	matched := 0
+	var targets []mosniov1.FilterPolicyTargetStatus
+	for _, obj := range objs {
+		if obj.GetNamespace() != policy.Namespace || !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+
+		matched++
+		// Index the selected resource so that we can re-resolve it once it is unlabelled
+		key := getK8sKey(obj.GetNamespace(), obj.GetName())
+		idx[key] = append(idx[key], policy)
+
+		p := &mosniov1.FilterPolicy{}
+		*p = *policy
+		p.Status = mosniov1.FilterPolicyStatus{}
+		p.Spec.TargetSelector = nil
+		p.Spec.TargetRef = &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+			PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+				Group: sel.Group,
+				Kind:  sel.Kind,
+				Name:  gwapiv1.ObjectName(obj.GetName()),
+			},
+		}
+
+		if sel.Kind == "VirtualService" {
+			err = r.resolveVirtualService(ctx, p, initState, istioGwIdx)
+		} else {
+			err = r.resolveHTTPRoute(ctx, p, initState, k8sGwIdx)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !apimeta.IsStatusConditionTrue(p.Status.Conditions, string(gwapiv1a2.PolicyConditionAccepted)) {
+			continue
+		}
+		targets = append(targets, mosniov1.FilterPolicyTargetStatus{
+			Group: sel.Group,
+			Kind:  sel.Kind,
+			Name:  p.Spec.TargetRef.Name,
+		})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	policy.SetTargets(targets)
+
+	if len(targets) > 0 {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	} else if matched > 0 {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "all selected resources are not found or unsupported")
+	} else {
+		policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound, "no resource matches the targetSelector")
+	}
+	return nil
+}
+
 // When multiple policies target to the same resource, the oldest one wins. Since
 // the embedded policy doesn't have a CreationTimestamp, it will has the highest priority.
 // One can change this behavior by given a fake CreationTimestamp.
@@ -506,31 +976,58 @@ func getFilterPolicyFromAnnotation(obj client.Object) *mosniov1.FilterPolicy {
 	return nil
 }
 
-func (r *FilterPolicyReconciler) policyToTranslationState(ctx context.Context,
-	policies *mosniov1.FilterPolicyList) (*translation.InitState, error) {
-
+func (r *FilterPolicyReconciler) listPolicies(ctx context.Context, policies *mosniov1.FilterPolicyList) error {
 	// For current implementation, let's rebuild the state each time to avoid complexity.
 	// The controller will use local cache when doing read operation.
 	if err := r.List(ctx, policies); err != nil {
-		return nil, fmt.Errorf("failed to list FilterPolicy: %w", err)
+		return fmt.Errorf("failed to list FilterPolicy: %w", err)
 	}
 
 	var httpfilterpolicies mosniov1.HTTPFilterPolicyList
 	if err := r.List(ctx, &httpfilterpolicies); err != nil {
-		return nil, fmt.Errorf("failed to list HTTPFilterPolicy: %w", err)
+		return fmt.Errorf("failed to list HTTPFilterPolicy: %w", err)
 	}
 	for _, p := range httpfilterpolicies.Items {
 		policies.Items = append(policies.Items, mosniov1.ConvertHTTPFilterPolicyToFilterPolicy(&p))
 	}
+	return nil
+}
+
+// policyToTranslationState builds the translation state from the listed policies. The status of the
+// policies is updated during the resolution.
+func (r *FilterPolicyReconciler) policyToTranslationState(ctx context.Context,
+	policies *mosniov1.FilterPolicyList) (*translation.InitState, error) {
 
 	initState := translation.NewInitState()
 	vsIdx := map[string][]*mosniov1.FilterPolicy{}
 	hrIdx := map[string][]*mosniov1.FilterPolicy{}
+	grIdx := map[string][]*mosniov1.FilterPolicy{}
+	tcpIdx := map[string][]*mosniov1.FilterPolicy{}
+	tlsIdx := map[string][]*mosniov1.FilterPolicy{}
 	istioGwIdx := map[string][]*mosniov1.FilterPolicy{}
 	k8sGwIdx := map[string][]*mosniov1.FilterPolicy{}
 
+	vsSelectorIdx := map[string][]labels.Selector{}
+	hrSelectorIdx := map[string][]labels.Selector{}
+
 	for i := range policies.Items {
 		policy := &policies.Items[i]
+		if sel := policy.Spec.TargetSelector; sel != nil {
+			selector, err := metav1.LabelSelectorAsSelector(&sel.Selector)
+			if err != nil {
+				// will be reported in the validation
+				continue
+			}
+
+			ns := policy.Namespace
+			if sel.Group == "networking.istio.io" && sel.Kind == "VirtualService" {
+				vsSelectorIdx[ns] = append(vsSelectorIdx[ns], selector)
+			} else if sel.Group == "gateway.networking.k8s.io" && sel.Kind == "HTTPRoute" {
+				hrSelectorIdx[ns] = append(hrSelectorIdx[ns], selector)
+			}
+			continue
+		}
+
 		ref := policy.Spec.TargetRef
 		if ref == nil {
 			continue
@@ -541,20 +1038,52 @@ func (r *FilterPolicyReconciler) policyToTranslationState(ctx context.Context,
 		key := getK8sKey(nsName.Namespace, nsName.Name)
 		if ref.Group == "networking.istio.io" && ref.Kind == "VirtualService" {
 			vsIdx[key] = append(vsIdx[key], policy)
-		} else if ref.Group == "gateway.networking.k8s.io" && ref.Kind == "HTTPRoute" {
-			hrIdx[key] = append(hrIdx[key], policy)
+		} else if ref.Group == "gateway.networking.k8s.io" {
+			switch ref.Kind {
+			case "HTTPRoute":
+				hrIdx[key] = append(hrIdx[key], policy)
+			case "GRPCRoute":
+				grIdx[key] = append(grIdx[key], policy)
+			case "TCPRoute":
+				tcpIdx[key] = append(tcpIdx[key], policy)
+			case "TLSRoute":
+				tlsIdx[key] = append(tlsIdx[key], policy)
+			}
 		}
 	}
 
-	r.virtualServiceIndexer.UpdateIndex(vsIdx)
-	if config.EnableGatewayAPI() {
-		r.httpRouteIndexer.UpdateIndex(hrIdx)
+	enableAlphaGatewayAPI := config.EnableAlphaGatewayAPI()
+	if enableAlphaGatewayAPI {
+		r.grpcRouteIndexer.UpdateIndex(grIdx)
+		r.tcpRouteIndexer.UpdateIndex(tcpIdx)
+		r.tlsRouteIndexer.UpdateIndex(tlsIdx)
 	}
 
 	supportGatewayPolicy := config.EnableLDSPluginViaECDS()
+	checker, _ := r.output.(component.PluginChecker)
 
+	candidates := &selectorCandidates{}
 	for i := range policies.Items {
 		policy := &policies.Items[i]
+		if checker != nil && policy.IsValid() {
+			// Unlike the invalid spec, the rejection is not kept, so it's checked in each reconciliation
+			if err := checkPolicyPlugins(checker, policy); err != nil {
+				policy.SetAccepted(mosniov1.PolicyReasonRejected, err.Error())
+				continue
+			}
+		}
+
+		if policy.Spec.TargetSelector != nil {
+			err := r.resolveTargetSelector(ctx, policy, initState, candidates, vsIdx, hrIdx, istioGwIdx, k8sGwIdx)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// clear the targets reported when the policy used targetSelector
+		policy.SetTargets(nil)
+
 		ref := policy.Spec.TargetRef
 		if ref == nil {
 			policy.SetAccepted(gwapiv1a2.PolicyReasonInvalid, "targetRef is required when using FilterPolicy outside embedded mode")
@@ -596,12 +1125,33 @@ func (r *FilterPolicyReconciler) policyToTranslationState(ctx context.Context,
 				err = r.resolveIstioGateway(ctx, policy, initState)
 			}
 		} else if ref.Group == "gateway.networking.k8s.io" {
-			if ref.Kind == "HTTPRoute" {
+			switch ref.Kind {
+			case "HTTPRoute":
 				err = r.resolveHTTPRoute(ctx, policy, initState, k8sGwIdx)
-			} else if ref.Kind == "Gateway" && supportGatewayPolicy {
-				key := getK8sKey(nsName.Namespace, nsName.Name)
-				k8sGwIdx[key] = append(k8sGwIdx[key], policy)
-				err = r.resolveK8sGateway(ctx, policy, initState)
+			case "Gateway":
+				if supportGatewayPolicy {
+					key := getK8sKey(nsName.Namespace, nsName.Name)
+					k8sGwIdx[key] = append(k8sGwIdx[key], policy)
+					err = r.resolveK8sGateway(ctx, policy, initState)
+				}
+			case "GRPCRoute", "TCPRoute", "TLSRoute":
+				if !enableAlphaGatewayAPI {
+					policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound,
+						fmt.Sprintf("%s is not supported until the alpha gateway API is enabled", ref.Kind))
+					break
+				}
+
+				if ref.Kind == "GRPCRoute" {
+					err = r.resolveGRPCRoute(ctx, policy, initState, k8sGwIdx)
+				} else if !supportGatewayPolicy {
+					// The policy of TCPRoute and TLSRoute is applied to the listener
+					policy.SetAccepted(gwapiv1a2.PolicyReasonTargetNotFound,
+						fmt.Sprintf("%s is not supported until the LDS plugin via ECDS is enabled", ref.Kind))
+				} else if ref.Kind == "TCPRoute" {
+					err = r.resolveTCPRoute(ctx, policy, initState, k8sGwIdx)
+				} else {
+					err = r.resolveTLSRoute(ctx, policy, initState, k8sGwIdx)
+				}
 			}
 		}
 		if err != nil {
@@ -609,6 +1159,14 @@ func (r *FilterPolicyReconciler) policyToTranslationState(ctx context.Context,
 		}
 	}
 
+	// The targets selected by targetSelector are indexed during the resolution
+	r.virtualServiceIndexer.UpdateIndex(vsIdx)
+	r.virtualServiceIndexer.UpdateSelectorIndex(vsSelectorIdx)
+	if config.EnableGatewayAPI() {
+		r.httpRouteIndexer.UpdateIndex(hrIdx)
+		r.httpRouteIndexer.UpdateSelectorIndex(hrSelectorIdx)
+	}
+
 	if config.EnableEmbeddedMode() {
 		// Some of our users use embedded policy mostly, so it's fine to list all
 		var virtualServices istiov1a3.VirtualServiceList
@@ -728,6 +1286,8 @@ func (r *FilterPolicyReconciler) updatePolicies(ctx context.Context,
 type customResourceIndexer struct {
 	lock  sync.RWMutex
 	index map[string][]*mosniov1.FilterPolicy
+	// selectorIndex stores the label selectors from targetSelector, keyed by namespace
+	selectorIndex map[string][]labels.Selector
 
 	Group          string
 	Kind           string
@@ -740,6 +1300,29 @@ func (v *customResourceIndexer) UpdateIndex(idx map[string][]*mosniov1.FilterPol
 	v.lock.Unlock()
 }
 
+func (v *customResourceIndexer) UpdateSelectorIndex(idx map[string][]labels.Selector) {
+	v.lock.Lock()
+	v.selectorIndex = idx
+	v.lock.Unlock()
+}
+
+func (v *customResourceIndexer) matchSelector(obj component.ResourceMeta) bool {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	selectors := v.selectorIndex[obj.GetNamespace()]
+	if len(selectors) == 0 {
+		return false
+	}
+	set := labels.Set(obj.GetLabels())
+	for _, sel := range selectors {
+		if sel.Matches(set) {
+			return true
+		}
+	}
+	return false
+}
+
 func (v *customResourceIndexer) FindAffectedObjects(ctx context.Context, obj component.ResourceMeta) []reconcile.Request {
 	if config.EnableEmbeddedMode() {
 		ann := obj.GetAnnotations()
@@ -769,6 +1352,11 @@ func (v *customResourceIndexer) FindAffectedObjects(ctx context.Context, obj com
 	policies, ok := v.index[getK8sKey(obj.GetNamespace(), obj.GetName())]
 	v.lock.RUnlock()
 	if !ok {
+		if v.matchSelector(obj) {
+			log.Infof("Target selected by targetSelector changed, trigger reconciliation, group: %s, kind: %s, namespace: %s, name: %s",
+				obj.GetGroup(), obj.GetKind(), obj.GetNamespace(), obj.GetName())
+			return triggerReconciliation()
+		}
 		return nil
 	}
 
@@ -831,6 +1419,8 @@ func (r *FilterPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	pred := predicate.Or(
 		predicate.GenerationChangedPredicate{},
 		predicate.AnnotationChangedPredicate{},
+		// for targetSelector
+		predicate.LabelChangedPredicate{},
 	)
 	for name, idxer := range r.indexers {
 		ss := strings.Split(name, "/")
@@ -844,6 +1434,7 @@ func (r *FilterPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			builder.WithPredicates(pred),
 		)
 	}
+	watchWriteFailures(controller, r.output, "FilterPolicy")
 
 	return controller.Complete(r)
 }
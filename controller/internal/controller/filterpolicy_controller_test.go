@@ -18,11 +18,16 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"mosn.io/htnn/controller/internal/controller/component"
 	"mosn.io/htnn/controller/tests/pkg"
@@ -58,4 +63,125 @@ func TestNeedReconcile(t *testing.T) {
 		"ns/name": {&policy},
 	}
 	assert.True(t, r.NeedReconcile(ctx, res))
+
+	// selected by targetSelector
+	r.httpRouteIndexer.index = nil
+	sel, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"tier": "public"},
+	})
+	require.NoError(t, err)
+	r.httpRouteIndexer.UpdateSelectorIndex(map[string][]labels.Selector{
+		"ns": {sel},
+	})
+	assert.False(t, r.NeedReconcile(ctx, res))
+	route.Labels = map[string]string{"tier": "public"}
+	assert.True(t, r.NeedReconcile(ctx, res))
+	route.Namespace = "other"
+	assert.False(t, r.NeedReconcile(ctx, res))
+}
+
+func TestRejectPolicies(t *testing.T) {
+	policies := &mosniov1.FilterPolicyList{
+		Items: []mosniov1.FilterPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "large"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "small"}},
+		},
+	}
+	for i := range policies.Items {
+		policies.Items[i].SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	}
+
+	rejected := map[string]string{"default/large": "too large"}
+	rejectPolicies(policies, rejected)
+	assert.Equal(t, string(mosniov1.PolicyReasonRejected), policies.Items[0].Status.Conditions[0].Reason)
+	assert.Equal(t, "too large", policies.Items[0].Status.Conditions[0].Message)
+	// the rejection is not sticky
+	assert.True(t, policies.Items[0].IsValid())
+	assert.Equal(t, string(gwapiv1a2.PolicyReasonAccepted), policies.Items[1].Status.Conditions[0].Reason)
+
+	// the policy is accepted again in the next translation
+	policies.Items[0].SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	rejectPolicies(policies, nil)
+	assert.Equal(t, string(gwapiv1a2.PolicyReasonAccepted), policies.Items[0].Status.Conditions[0].Reason)
+
+	setPoliciesNearSizeLimit(policies, map[string]string{"default/small": "close to the limit"})
+	assert.Len(t, policies.Items[0].Status.Conditions, 1)
+	require.Len(t, policies.Items[1].Status.Conditions, 2)
+	assert.Equal(t, string(mosniov1.ConditionSizeLimitApproached), policies.Items[1].Status.Conditions[1].Type)
+	setPoliciesNearSizeLimit(policies, nil)
+	assert.Len(t, policies.Items[1].Status.Conditions, 1)
+}
+
+type pluginCheckerFunc func(name string) error
+
+func (f pluginCheckerFunc) CheckPlugin(name string) error {
+	return f(name)
+}
+
+func TestCheckPolicyPlugins(t *testing.T) {
+	checker := pluginCheckerFunc(func(name string) error {
+		if name == "listenerPatch" {
+			return errors.New("unsupported")
+		}
+		return nil
+	})
+
+	policy := &mosniov1.FilterPolicy{
+		Spec: mosniov1.FilterPolicySpec{
+			Filters: map[string]mosniov1.Plugin{
+				"demo": {},
+			},
+		},
+	}
+	assert.NoError(t, checkPolicyPlugins(checker, policy))
+
+	policy.Spec.SubPolicies = []mosniov1.FilterSubPolicy{
+		{Filters: map[string]mosniov1.Plugin{"listenerPatch": {}}},
+	}
+	assert.ErrorContains(t, checkPolicyPlugins(checker, policy), "unsupported")
+}
+
+func TestSharedL4Listener(t *testing.T) {
+	gw := &gwapiv1b1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec: gwapiv1.GatewaySpec{
+			Listeners: []gwapiv1.Listener{
+				{Name: "shared", Port: 9000, Protocol: gwapiv1.TCPProtocolType},
+				{Name: "dedicated", Port: 9001, Protocol: gwapiv1.TCPProtocolType},
+			},
+		},
+	}
+	route := func(ns, name, section string) l4Route {
+		sectionName := gwapiv1.SectionName(section)
+		gwNs := gwapiv1.Namespace("default")
+		return l4Route{
+			Object: &gwapiv1a2.TCPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}},
+			parentRefs: []gwapiv1.ParentReference{
+				{Name: "gw", Namespace: &gwNs, SectionName: &sectionName},
+			},
+		}
+	}
+	gws := []*gwapiv1b1.Gateway{gw}
+
+	routes := []l4Route{
+		route("default", "a", "shared"),
+		route("default", "b", "dedicated"),
+	}
+	// the route itself is skipped
+	assert.Equal(t, "", sharedL4Listener(routes[0], routes, gws, gwapiv1.TCPProtocolType))
+
+	// the route from other namespace is not allowed to attach
+	same := gwapiv1.NamespacesFromSame
+	gw.Spec.Listeners[0].AllowedRoutes = &gwapiv1.AllowedRoutes{
+		Namespaces: &gwapiv1.RouteNamespaces{From: &same},
+	}
+	routes = append(routes, route("other", "c", "shared"))
+	assert.Equal(t, "", sharedL4Listener(routes[0], routes, gws, gwapiv1.TCPProtocolType))
+
+	gw.Spec.Listeners[0].AllowedRoutes = nil
+	assert.Contains(t, sharedL4Listener(routes[0], routes, gws, gwapiv1.TCPProtocolType),
+		"listener shared of gateway default/gw is shared with route other/c")
+	assert.Equal(t, "", sharedL4Listener(routes[1], routes, gws, gwapiv1.TCPProtocolType))
+	// the listener with other protocol is not considered
+	assert.Equal(t, "", sharedL4Listener(routes[0], routes, gws, gwapiv1.TLSProtocolType))
 }
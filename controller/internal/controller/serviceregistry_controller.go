@@ -55,11 +55,14 @@ func NewServiceRegistryReconciler(manager component.ResourceManager) *ServiceReg
 //+kubebuilder:rbac:groups=htnn.mosn.io,resources=serviceregistries/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=htnn.mosn.io,resources=serviceregistries/finalizers,verbs=update
 
-func (r *ServiceRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ServiceRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	reconcilationStart := time.Now()
 	defer func() {
 		reconcilationDuration := time.Since(reconcilationStart).Seconds()
 		metrics.ServiceRegistryReconcileDurationDistribution.Record(reconcilationDuration)
+		if err != nil {
+			metrics.ReconcileErrorsTotal.With("serviceregistry").Increment()
+		}
 	}()
 
 	for nsName, prevServiceRegistry := range r.prevServiceRegistries {
@@ -72,7 +75,7 @@ func (r *ServiceRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	var serviceRegistries mosniov1.ServiceRegistryList
-	err := r.List(ctx, &serviceRegistries)
+	err = r.List(ctx, &serviceRegistries)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to list ServiceRegistry: %w", err)
 	}
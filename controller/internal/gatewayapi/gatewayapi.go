@@ -17,6 +17,7 @@ package gatewayapi
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -26,6 +27,7 @@ func AddToScheme(scheme *runtime.Scheme) error {
 	fs := []addToScheme{
 		gwapiv1b1.AddToScheme,
 		gwapiv1.AddToScheme,
+		gwapiv1a2.AddToScheme,
 	}
 	for _, f := range fs {
 		if err := f(scheme); err != nil {
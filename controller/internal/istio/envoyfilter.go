@@ -46,6 +46,8 @@ const (
 	DefaultHTTPFilter            = "htnn-http-filter"
 	ECDSConsumerName             = "htnn-consumer"
 	DynamicConfigEnvoyFilterName = "htnn-dynamic-config"
+	DynamicConfigIndexName       = "htnn-DynamicConfigIndex"
+	HTTPFilterConfigName         = "htnn-http-filter-config"
 )
 
 type configWrapper struct {
@@ -99,18 +101,24 @@ func DefaultEnvoyFilters() map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter {
 	// Native filters can only be used before/after Go plugins.
 
 	configs := []*configWrapper{}
+	wasmConfigs := map[string]map[string]interface{}{}
 	plugins.IteratePlugin(func(key string, value plugins.Plugin) bool {
-		nativePlugin, ok := value.(plugins.HTTPNativePlugin)
-		if !ok {
+		name := fmt.Sprintf("htnn.filters.http.%s", key)
+		var filter map[string]interface{}
+		if wasmPlugin, ok := value.(plugins.WasmPlugin); ok {
+			filter = wasmFilterPlaceholder(wasmPlugin.Module())
+			wasmConfigs[name] = WasmFilterConfig(name, wasmPlugin.Module())
+		} else if nativePlugin, ok := value.(plugins.HTTPNativePlugin); ok {
+			filter = nativePlugin.HTTPFilterConfigPlaceholder()
+		} else {
 			return true
 		}
 
-		filter := nativePlugin.HTTPFilterConfigPlaceholder()
-		filter["name"] = fmt.Sprintf("htnn.filters.http.%s", key)
+		filter["name"] = name
 		filter["disabled"] = true
 		configs = append(configs, &configWrapper{
 			name:   key,
-			pre:    nativePlugin.Order().Position == plugins.OrderPositionOuter,
+			pre:    value.Order().Position == plugins.OrderPositionOuter,
 			filter: filter,
 		})
 		return true
@@ -155,6 +163,11 @@ func DefaultEnvoyFilters() map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter {
 		}
 	}
 
+	if len(wasmConfigs) > 0 {
+		// The module of wasm plugin is delivered via ECDS
+		patches = append(patches, GenerateHTTPFilterConfigs(ctrlcfg.RootNamespace(), wasmConfigs).Spec.ConfigPatches...)
+	}
+
 	key := component.EnvoyFilterKey{
 		Namespace: ctrlcfg.RootNamespace(),
 		Name:      DefaultHTTPFilter,
@@ -175,6 +188,26 @@ func DefaultEnvoyFilters() map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter {
 	return efs
 }
 
+// mergeRouteConfig merges the fields into the route configuration. The nested maps are merged, so that
+// multiple plugins can set the different keys of the same field, like the metadata.
+func mergeRouteConfig(dst map[string]interface{}, src map[string]interface{}) {
+	for k, v := range src {
+		srcField, ok := v.(map[string]interface{})
+		dstField, ok2 := dst[k].(map[string]interface{})
+		if !ok || !ok2 {
+			dst[k] = v
+			continue
+		}
+
+		merged := make(map[string]interface{}, len(dstField)+len(srcField))
+		for fk, fv := range dstField {
+			merged[fk] = fv
+		}
+		mergeRouteConfig(merged, srcField)
+		dst[k] = merged
+	}
+}
+
 func GenerateRouteFilter(host *model.VirtualHost, route string, config map[string]interface{}) *istiov1a3.EnvoyFilter {
 	applyTo := istioapi.EnvoyFilter_HTTP_ROUTE
 	vhost := &istioapi.EnvoyFilter_RouteConfigurationMatch_VirtualHostMatch{
@@ -201,9 +234,7 @@ func GenerateRouteFilter(host *model.VirtualHost, route string, config map[strin
 	}
 	for _, filter := range extraRouteConfig {
 		fields, _ := filter.Config.(map[string]interface{})
-		for k, v := range fields {
-			routeConfig[k] = v
-		}
+		mergeRouteConfig(routeConfig, fields)
 	}
 
 	return &istiov1a3.EnvoyFilter{
@@ -489,9 +520,74 @@ func GenerateConsumers(consumers map[string]interface{}) *istiov1a3.EnvoyFilter
 	}
 }
 
-func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*mosniov1.DynamicConfig) map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter {
-	efs := map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter{}
+// GenerateHTTPFilterConfigs generates the HTTP filter configurations delivered via ECDS to the proxies in the
+// given namespace, keyed by the filter name. The HTTP filters which subscribe to them are inserted in the
+// DefaultEnvoyFilters.
+func GenerateHTTPFilterConfigs(ns string, configs map[string]map[string]interface{}) *istiov1a3.EnvoyFilter {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	patches := make([]*istioapi.EnvoyFilter_EnvoyConfigObjectPatch, 0, len(configs))
+	for _, name := range names {
+		patches = append(patches, &istioapi.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: istioapi.EnvoyFilter_EXTENSION_CONFIG,
+			Patch: &istioapi.EnvoyFilter_Patch{
+				Operation: istioapi.EnvoyFilter_Patch_ADD,
+				Value: MustNewStruct(map[string]interface{}{
+					"name":         name,
+					"typed_config": configs[name],
+				}),
+			},
+		})
+	}
+
+	return &istiov1a3.EnvoyFilter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      HTTPFilterConfigName,
+		},
+		Spec: istioapi.EnvoyFilter{
+			ConfigPatches: patches,
+		},
+	}
+}
+
+// DispatchedDynamicConfig is a DynamicConfig with the revision which should be dispatched to the data plane
+type DispatchedDynamicConfig struct {
+	*mosniov1.DynamicConfig
+
+	Version string
+	Config  []byte
+}
+
+func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*DispatchedDynamicConfig) map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter {
+	// The index lists all the dispatched DynamicConfigs, so that the data plane can purge the removed ones.
+	// It's dispatched from the root namespace, so the EnvoyFilter there is always generated.
+	index := map[string]interface{}{}
+	for ns, dynamicConfigs := range namespacedDynamicConfigs {
+		names := make([]string, 0, len(dynamicConfigs))
+		for _, dynamicConfig := range dynamicConfigs {
+			names = append(names, dynamicConfig.Spec.Type)
+		}
+		sort.Strings(names)
+		values := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			values = append(values, name)
+		}
+		index[ns] = values
+	}
+	rootNs := ctrlcfg.RootNamespace()
+	namespaces := make(map[string]map[string]*DispatchedDynamicConfig, len(namespacedDynamicConfigs)+1)
+	namespaces[rootNs] = nil
 	for ns, dynamicConfigs := range namespacedDynamicConfigs {
+		namespaces[ns] = dynamicConfigs
+	}
+
+	efs := map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter{}
+	for ns, dynamicConfigs := range namespaces {
 		ef := &istiov1a3.EnvoyFilter{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: ns,
@@ -507,7 +603,7 @@ func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*mosn
 		// Each DynamicConfig is smaller than 1.5MB, which is the limit applied by the k8s API server (the value may be different by configured).
 		// In prod, we generate the EnvoyFilter inside the istio, so the size of EnvoyFilter doesn't matter.
 
-		configs := make([]*mosniov1.DynamicConfig, 0, len(dynamicConfigs))
+		configs := make([]*DispatchedDynamicConfig, 0, len(dynamicConfigs))
 		for _, dynamicConfig := range dynamicConfigs {
 			configs = append(configs, dynamicConfig)
 		}
@@ -518,7 +614,21 @@ func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*mosn
 		httpFilters := []interface{}{}
 		for _, cfg := range configs {
 			var dispatchedConfig interface{}
-			_ = json.Unmarshal(cfg.Spec.Config.Raw, &dispatchedConfig)
+			_ = json.Unmarshal(cfg.Config, &dispatchedConfig)
+			value := map[string]interface{}{
+				"name":   cfg.Spec.Type,
+				"config": dispatchedConfig,
+				// namespace and version are used to report the apply status
+				"namespace": ns,
+				"version":   cfg.Version,
+			}
+			if cfg.Spec.RollbackTo != "" {
+				value["rollback"] = true
+			}
+			if ns == rootNs {
+				// the DynamicConfig from the root namespace can be referred by the plugins in all namespaces
+				value["root"] = true
+			}
 
 			ef.Spec.ConfigPatches = append(ef.Spec.ConfigPatches, &istioapi.EnvoyFilter_EnvoyConfigObjectPatch{
 				ApplyTo: istioapi.EnvoyFilter_EXTENSION_CONFIG,
@@ -526,6 +636,39 @@ func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*mosn
 					Operation: istioapi.EnvoyFilter_Patch_ADD,
 					Value: MustNewStruct(map[string]interface{}{
 						"name": fmt.Sprintf("htnn-DynamicConfig-%s", cfg.Spec.Type),
+						"typed_config": map[string]interface{}{
+							"@type":        "type.googleapis.com/envoy.extensions.filters.http.golang.v3alpha.Config",
+							"library_id":   "dc",
+							"library_path": ctrlcfg.GoSoPath(),
+							"plugin_name":  "dc",
+							"plugin_config": map[string]interface{}{
+								"@type": "type.googleapis.com/xds.type.v3.TypedStruct",
+								"value": value,
+							},
+						},
+					}),
+				},
+			})
+			httpFilters = append(httpFilters, map[string]interface{}{
+				"name": fmt.Sprintf("htnn-DynamicConfig-%s", cfg.Spec.Type),
+				"config_discovery": map[string]interface{}{
+					"config_source": map[string]interface{}{
+						"ads": map[string]interface{}{},
+					},
+					"type_urls": []interface{}{
+						"type.googleapis.com/envoy.extensions.filters.http.golang.v3alpha.Config",
+					},
+				},
+			})
+		}
+
+		if ns == rootNs {
+			ef.Spec.ConfigPatches = append(ef.Spec.ConfigPatches, &istioapi.EnvoyFilter_EnvoyConfigObjectPatch{
+				ApplyTo: istioapi.EnvoyFilter_EXTENSION_CONFIG,
+				Patch: &istioapi.EnvoyFilter_Patch{
+					Operation: istioapi.EnvoyFilter_Patch_ADD,
+					Value: MustNewStruct(map[string]interface{}{
+						"name": DynamicConfigIndexName,
 						"typed_config": map[string]interface{}{
 							"@type":        "type.googleapis.com/envoy.extensions.filters.http.golang.v3alpha.Config",
 							"library_id":   "dc",
@@ -534,8 +677,7 @@ func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*mosn
 							"plugin_config": map[string]interface{}{
 								"@type": "type.googleapis.com/xds.type.v3.TypedStruct",
 								"value": map[string]interface{}{
-									"name":   cfg.Spec.Type,
-									"config": dispatchedConfig,
+									"index": index,
 								},
 							},
 						},
@@ -543,7 +685,7 @@ func GenerateDynamicConfigs(namespacedDynamicConfigs map[string]map[string]*mosn
 				},
 			})
 			httpFilters = append(httpFilters, map[string]interface{}{
-				"name": fmt.Sprintf("htnn-DynamicConfig-%s", cfg.Spec.Type),
+				"name": DynamicConfigIndexName,
 				"config_discovery": map[string]interface{}{
 					"config_source": map[string]interface{}{
 						"ads": map[string]interface{}{},
@@ -23,7 +23,6 @@ import (
 	local_ratelimit "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
 	"github.com/stretchr/testify/require"
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
-	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/yaml"
 
 	"mosn.io/htnn/api/pkg/filtermanager/api"
@@ -169,23 +168,25 @@ func TestGenerateDynamicConfigs(t *testing.T) {
 	patch := gomonkey.ApplyFuncReturn(ctrlcfg.GoSoPath, "/etc/libgolang.so")
 	defer patch.Reset()
 
-	out := GenerateDynamicConfigs(map[string]map[string]*mosniov1.DynamicConfig{
+	out := GenerateDynamicConfigs(map[string]map[string]*DispatchedDynamicConfig{
 		"ns": {
 			"cb_name": {
-				Spec: mosniov1.DynamicConfigSpec{
-					Type: "cb_name",
-					Config: runtime.RawExtension{
-						Raw: []byte(`{"key": "value"}`),
+				DynamicConfig: &mosniov1.DynamicConfig{
+					Spec: mosniov1.DynamicConfigSpec{
+						Type: "cb_name",
 					},
 				},
+				Version: "2",
+				Config:  []byte(`{"key": "value"}`),
 			},
 			"cb_name2": {
-				Spec: mosniov1.DynamicConfigSpec{
-					Type: "cb_name2",
-					Config: runtime.RawExtension{
-						Raw: []byte(`{"key2": "value"}`),
+				DynamicConfig: &mosniov1.DynamicConfig{
+					Spec: mosniov1.DynamicConfigSpec{
+						Type: "cb_name2",
 					},
 				},
+				Version: "0",
+				Config:  []byte(`{"key2": "value"}`),
 			},
 		},
 	})
@@ -198,4 +199,33 @@ func TestGenerateDynamicConfigs(t *testing.T) {
 	d, _ = os.ReadFile(expFile)
 	want := string(d)
 	require.Equal(t, want, actual)
+
+	// the index is dispatched from the root namespace
+	d, _ = yaml.Marshal(out[component.EnvoyFilterKey{
+		Namespace: ctrlcfg.RootNamespace(),
+		Name:      DynamicConfigEnvoyFilterName,
+	}])
+	actual = string(d)
+	expFile = filepath.Join("testdata", "dynamic_config_index.yml")
+	d, _ = os.ReadFile(expFile)
+	want = string(d)
+	require.Equal(t, want, actual)
+}
+
+func TestWasmFilterPlaceholder(t *testing.T) {
+	patch := gomonkey.ApplyFuncReturn(ctrlcfg.GoSoPath, "/path/to/goso")
+	defer patch.Reset()
+
+	defaultConfig := func(module plugins.WasmModule) map[string]interface{} {
+		discovery := wasmFilterPlaceholder(module)["config_discovery"].(map[string]interface{})
+		return discovery["default_config"].(map[string]interface{})
+	}
+
+	// reject the request until the module is loaded
+	config := defaultConfig(plugins.WasmModule{URL: "file:///etc/demo.wasm"})
+	require.Equal(t, faultFilterConfigTypeURL, config["@type"])
+	require.Equal(t, 503, config["abort"].(map[string]interface{})["http_status"])
+
+	config = defaultConfig(plugins.WasmModule{URL: "file:///etc/demo.wasm", FailOpen: true})
+	require.Equal(t, golangFilterConfigTypeURL, config["@type"])
 }
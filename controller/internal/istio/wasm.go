@@ -0,0 +1,124 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istio
+
+import (
+	"strings"
+
+	"mosn.io/htnn/api/pkg/plugins"
+	ctrlcfg "mosn.io/htnn/controller/internal/config"
+)
+
+const (
+	WasmFilterConfigTypeURL = "type.googleapis.com/envoy.extensions.filters.http.wasm.v3.Wasm"
+	// WasmRouteConfigTypeURL is used to enable the disabled wasm filter in the route, as the
+	// wasm filter doesn't support per-route configuration.
+	WasmRouteConfigTypeURL = "type.googleapis.com/envoy.config.route.v3.FilterConfig"
+
+	golangFilterConfigTypeURL = "type.googleapis.com/envoy.extensions.filters.http.golang.v3alpha.Config"
+	faultFilterConfigTypeURL  = "type.googleapis.com/envoy.extensions.filters.http.fault.v3.HTTPFault"
+)
+
+func wasmCode(module plugins.WasmModule) map[string]interface{} {
+	if path, ok := strings.CutPrefix(module.URL, "file://"); ok {
+		return map[string]interface{}{
+			"local": map[string]interface{}{
+				"filename": path,
+			},
+		}
+	}
+
+	// The remote module, including the OCI image, is fetched by istio-agent and converted
+	// to the local one before sending to Envoy.
+	remote := map[string]interface{}{
+		"http_uri": map[string]interface{}{
+			"uri":     module.URL,
+			"timeout": "30s",
+		},
+	}
+	if module.SHA256 != "" {
+		remote["sha256"] = module.SHA256
+	}
+	return map[string]interface{}{
+		"remote": remote,
+	}
+}
+
+// WasmFilterConfig returns the wasm HTTP filter configuration of the given module
+func WasmFilterConfig(name string, module plugins.WasmModule) map[string]interface{} {
+	config := map[string]interface{}{
+		"name": name,
+		"vm_config": map[string]interface{}{
+			"vm_id":   name,
+			"runtime": "envoy.wasm.runtime.v8",
+			"code":    wasmCode(module),
+		},
+	}
+	if module.RootID != "" {
+		config["root_id"] = module.RootID
+	}
+	if module.Configuration != "" {
+		config["configuration"] = map[string]interface{}{
+			"@type": "type.googleapis.com/google.protobuf.StringValue",
+			"value": module.Configuration,
+		}
+	}
+	return map[string]interface{}{
+		"@type":  WasmFilterConfigTypeURL,
+		"config": config,
+	}
+}
+
+// wasmDefaultConfig returns the configuration used before the module is loaded. An empty Wasm
+// configuration can't be used here as it's rejected by Envoy.
+func wasmDefaultConfig(module plugins.WasmModule) (string, map[string]interface{}) {
+	if module.FailOpen {
+		// Use the Go filter without plugins, which lets the request pass through
+		return golangFilterConfigTypeURL, map[string]interface{}{
+			"@type":        golangFilterConfigTypeURL,
+			"library_id":   "fm",
+			"library_path": ctrlcfg.GoSoPath(),
+			"plugin_name":  "fm",
+		}
+	}
+	// Reject the request, so that a plugin like authentication or WAF doesn't fail open
+	return faultFilterConfigTypeURL, map[string]interface{}{
+		"@type": faultFilterConfigTypeURL,
+		"abort": map[string]interface{}{
+			"http_status": 503,
+			"percentage": map[string]interface{}{
+				"numerator": 100,
+			},
+		},
+	}
+}
+
+// wasmFilterPlaceholder returns the wasm HTTP filter which fetches its configuration via ECDS, so
+// that the module can be fetched by istio-agent.
+func wasmFilterPlaceholder(module plugins.WasmModule) map[string]interface{} {
+	typeURL, defaultConfig := wasmDefaultConfig(module)
+	return map[string]interface{}{
+		"config_discovery": map[string]interface{}{
+			"config_source": map[string]interface{}{
+				"ads": map[string]interface{}{},
+			},
+			// Apply the default configuration when the module is not fetched yet or fails to load,
+			// so that the listener is not blocked. Only the routes which enable the plugin are affected.
+			"default_config":                       defaultConfig,
+			"apply_default_config_without_warming": true,
+			"type_urls":                            []interface{}{WasmFilterConfigTypeURL, typeURL},
+		},
+	}
+}
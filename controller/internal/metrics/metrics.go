@@ -25,6 +25,8 @@ const (
 	Consumer                = "htnn_consumer"
 	SR                      = "htnn_service_registry"
 	DC                      = "htnn_dynamic_config"
+	EF                      = "htnn_envoyfilter"
+	Reconcile               = "htnn_reconcile"
 	TranslateDurationSuffix = "translate_duration_seconds"
 	ReconcileDurationSuffix = "reconcile_duration_seconds"
 )
@@ -34,12 +36,47 @@ type voidMetric struct {
 
 func (m *voidMetric) Record(value float64) {}
 
+func (m *voidMetric) Increment() {}
+
+type voidLabeledSum struct {
+}
+
+func (m *voidLabeledSum) With(labelValues ...string) component.Sum {
+	return &voidMetric{}
+}
+
+type voidLabeledGauge struct {
+}
+
+func (m *voidLabeledGauge) With(labelValues ...string) component.Gauge {
+	return &voidMetric{}
+}
+
 var (
 	FPTranslateDurationDistribution              component.Distribution = &voidMetric{}
 	FPReconcileDurationDistribution              component.Distribution = &voidMetric{}
 	ConsumerReconcileDurationDistribution        component.Distribution = &voidMetric{}
 	ServiceRegistryReconcileDurationDistribution component.Distribution = &voidMetric{}
 	DynamicConfigReconcileDurationDistribution   component.Distribution = &voidMetric{}
+	EnvoyFilterDiffSizeDistribution              component.Distribution = &voidMetric{}
+
+	EnvoyFilterCreatedTotal   component.Sum = &voidMetric{}
+	EnvoyFilterUpdatedTotal   component.Sum = &voidMetric{}
+	EnvoyFilterDeletedTotal   component.Sum = &voidMetric{}
+	EnvoyFilterCoalescedTotal component.Sum = &voidMetric{}
+
+	// labels: creator
+	EnvoyFilterGenerated      component.LabeledGauge = &voidLabeledGauge{}
+	EnvoyFilterGeneratedBytes component.LabeledGauge = &voidLabeledGauge{}
+	// labels: status
+	FPCount component.LabeledGauge = &voidLabeledGauge{}
+	// labels: namespace
+	ConsumerCount component.LabeledGauge = &voidLabeledGauge{}
+	// labels: namespace, name
+	ServiceRegistryServices        component.LabeledGauge = &voidLabeledGauge{}
+	ServiceRegistrySyncErrorsTotal component.LabeledSum   = &voidLabeledSum{}
+	// labels: controller
+	ReconcileErrorsTotal component.LabeledSum = &voidLabeledSum{}
 )
 
 func InitMetrics(provider component.MetricProvider) {
@@ -69,4 +106,43 @@ func InitMetrics(provider component.MetricProvider) {
 		// minimal: 100 microseconds
 		[]float64{1e-4, 1e-3, 0.01, 0.1, 1, 10},
 	)
+	EnvoyFilterDiffSizeDistribution = provider.NewDistribution(fmt.Sprintf("%s_diff_size", EF),
+		"How many EnvoyFilters are created, updated or deleted in a write.",
+		[]float64{0, 1, 10, 100, 1000},
+	)
+
+	if sp, ok := provider.(component.SumProvider); ok {
+		EnvoyFilterCreatedTotal = sp.NewSum(fmt.Sprintf("%s_created_total", EF),
+			"Total number of EnvoyFilters created by HTNN.")
+		EnvoyFilterUpdatedTotal = sp.NewSum(fmt.Sprintf("%s_updated_total", EF),
+			"Total number of EnvoyFilters updated by HTNN.")
+		EnvoyFilterDeletedTotal = sp.NewSum(fmt.Sprintf("%s_deleted_total", EF),
+			"Total number of EnvoyFilters deleted by HTNN.")
+		EnvoyFilterCoalescedTotal = sp.NewSum(fmt.Sprintf("%s_coalesced_writes_total", EF),
+			"Total number of EnvoyFilter writes skipped because they are superseded by a later one in the same batch.")
+	}
+
+	if lp, ok := provider.(component.LabeledMetricProvider); ok {
+		EnvoyFilterGenerated = lp.NewLabeledGauge(fmt.Sprintf("%s_generated", EF),
+			"Number of EnvoyFilters generated by HTNN in the last reconciliation, by the creator.",
+			[]string{"creator"})
+		EnvoyFilterGeneratedBytes = lp.NewLabeledGauge(fmt.Sprintf("%s_generated_bytes", EF),
+			"Total size in bytes of the EnvoyFilters generated by HTNN in the last reconciliation, by the creator.",
+			[]string{"creator"})
+		FPCount = lp.NewLabeledGauge(fmt.Sprintf("%s_count", FP),
+			"Number of FilterPolicies, by the reason of the Accepted condition.",
+			[]string{"status"})
+		ConsumerCount = lp.NewLabeledGauge(fmt.Sprintf("%s_count", Consumer),
+			"Number of accepted Consumers, by namespace.",
+			[]string{"namespace"})
+		ServiceRegistryServices = lp.NewLabeledGauge(fmt.Sprintf("%s_services", SR),
+			"Number of services synced from the ServiceRegistry.",
+			[]string{"namespace", "name"})
+		ServiceRegistrySyncErrorsTotal = lp.NewLabeledSum(fmt.Sprintf("%s_sync_errors_total", SR),
+			"Total number of errors happened when syncing services from the ServiceRegistry.",
+			[]string{"namespace", "name"})
+		ReconcileErrorsTotal = lp.NewLabeledSum(fmt.Sprintf("%s_errors_total", Reconcile),
+			"Total number of failed reconciliations, by the controller.",
+			[]string{"controller"})
+	}
 }
@@ -24,6 +24,9 @@ import (
 
 type metricProvider struct {
 	distributions int
+	sums          int
+	labeledSums   int
+	labeledGauges int
 }
 
 func (m *metricProvider) NewDistribution(name string, description string, buckets []float64) component.Distribution {
@@ -31,8 +34,41 @@ func (m *metricProvider) NewDistribution(name string, description string, bucket
 	return nil
 }
 
+func (m *metricProvider) NewSum(name string, description string) component.Sum {
+	m.sums++
+	return nil
+}
+
+func (m *metricProvider) NewLabeledSum(name string, description string, labels []string) component.LabeledSum {
+	m.labeledSums++
+	return nil
+}
+
+func (m *metricProvider) NewLabeledGauge(name string, description string, labels []string) component.LabeledGauge {
+	m.labeledGauges++
+	return nil
+}
+
 func TestInitMetrics(t *testing.T) {
 	p := &metricProvider{}
 	InitMetrics(p)
-	assert.Equal(t, 5, p.distributions)
+	assert.Equal(t, 6, p.distributions)
+	assert.Equal(t, 4, p.sums)
+	assert.Equal(t, 2, p.labeledSums)
+	assert.Equal(t, 5, p.labeledGauges)
+}
+
+type distributionOnlyProvider struct {
+	distributions int
+}
+
+func (m *distributionOnlyProvider) NewDistribution(name string, description string, buckets []float64) component.Distribution {
+	m.distributions++
+	return &voidMetric{}
+}
+
+func TestInitMetricsWithoutOptionalProviders(t *testing.T) {
+	p := &distributionOnlyProvider{}
+	InitMetrics(p)
+	assert.Equal(t, 6, p.distributions)
 }
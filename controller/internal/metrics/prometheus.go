@@ -0,0 +1,121 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"mosn.io/htnn/controller/pkg/component"
+)
+
+type prometheusProvider struct {
+	registerer prometheus.Registerer
+}
+
+// NewPrometheusProvider returns a MetricProvider which registers the metrics to the given registerer.
+// It's used when the controller runs outside istiod.
+func NewPrometheusProvider(registerer prometheus.Registerer) component.MetricProvider {
+	return &prometheusProvider{
+		registerer: registerer,
+	}
+}
+
+// register returns the registered collector if the collector with the same name is already registered,
+// so that the metrics can be initialized more than once.
+func register[T prometheus.Collector](p *prometheusProvider, c T) T {
+	err := p.registerer.Register(c)
+	if err == nil {
+		return c
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	panic(err)
+}
+
+type histogram struct {
+	prometheus.Histogram
+}
+
+func (h *histogram) Record(value float64) {
+	h.Observe(value)
+}
+
+type counter struct {
+	prometheus.Counter
+}
+
+func (c *counter) Increment() {
+	c.Inc()
+}
+
+type gauge struct {
+	prometheus.Gauge
+}
+
+func (g *gauge) Record(value float64) {
+	g.Set(value)
+}
+
+type counterVec struct {
+	*prometheus.CounterVec
+}
+
+func (c *counterVec) With(labelValues ...string) component.Sum {
+	return &counter{c.WithLabelValues(labelValues...)}
+}
+
+type gaugeVec struct {
+	*prometheus.GaugeVec
+}
+
+func (g *gaugeVec) With(labelValues ...string) component.Gauge {
+	return &gauge{g.WithLabelValues(labelValues...)}
+}
+
+func (p *prometheusProvider) NewDistribution(name, description string, bounds []float64) component.Distribution {
+	return &histogram{register[prometheus.Histogram](p, prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    name,
+		Help:    description,
+		Buckets: bounds,
+	}))}
+}
+
+func (p *prometheusProvider) NewSum(name, description string) component.Sum {
+	return &counter{register[prometheus.Counter](p, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: name,
+		Help: description,
+	}))}
+}
+
+func (p *prometheusProvider) NewLabeledSum(name, description string, labels []string) component.LabeledSum {
+	return &counterVec{register(p, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: name,
+		Help: description,
+	}, labels))}
+}
+
+func (p *prometheusProvider) NewLabeledGauge(name, description string, labels []string) component.LabeledGauge {
+	return &gaugeVec{register(p, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: name,
+		Help: description,
+	}, labels))}
+}
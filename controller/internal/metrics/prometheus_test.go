@@ -0,0 +1,68 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusProvider(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p := NewPrometheusProvider(reg).(*prometheusProvider)
+
+	sum := p.NewSum("htnn_test_total", "test")
+	sum.Increment()
+	// registering the same metric again reuses the existing one
+	p.NewSum("htnn_test_total", "test").Increment()
+
+	p.NewDistribution("htnn_test_seconds", "test", []float64{0.1, 1}).Record(0.5)
+
+	gauges := p.NewLabeledGauge("htnn_test_count", "test", []string{"namespace"})
+	gauges.With("default").Record(2)
+	gauges.With("other").Record(3)
+	gauges.With("other").Record(1)
+
+	sums := p.NewLabeledSum("htnn_test_errors_total", "test", []string{"namespace", "name"})
+	sums.With("default", "a").Increment()
+	sums.With("default", "a").Increment()
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+	assert.Len(t, mfs, 4)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(reg, "htnn_test_count"))
+	assert.Equal(t, float64(2), testutil.ToFloat64(sum.(*counter).Counter))
+	assert.Equal(t, float64(1), testutil.ToFloat64(gauges.(*gaugeVec).WithLabelValues("other")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(sums.(*counterVec).WithLabelValues("default", "a")))
+}
+
+func TestInitMetricsWithPrometheus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p := NewPrometheusProvider(reg).(*prometheusProvider)
+
+	InitMetrics(p)
+	// can be initialized again
+	InitMetrics(p)
+
+	FPCount.With("Accepted").Record(1)
+	ReconcileErrorsTotal.With("consumer").Increment()
+	assert.Equal(t, 1, testutil.CollectAndCount(reg, "htnn_filterpolicy_count"))
+	assert.Equal(t, 1, testutil.CollectAndCount(reg, "htnn_reconcile_errors_total"))
+}
@@ -54,6 +54,8 @@ const (
 
 	CategoryRoute       = "route"
 	CategoryRouteFilter = "route_filter"
+	// CategoryHTTPFilter is the part of HTTP filter configuration which is delivered via ECDS
+	CategoryHTTPFilter = "http_filter"
 
 	// This constant is used in the resource name which doesn't support '_' in the name
 	ECDSGolangPlugins = "golang-filter"
@@ -0,0 +1,703 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	istioapi "istio.io/api/networking/v1alpha3"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/controller/internal/translation"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+// ExplainRequest describes the request to explain
+type ExplainRequest struct {
+	// Gateway is the Istio Gateway or the Kubernetes Gateway which receives the request
+	Gateway types.NamespacedName
+	// Port is the port of the gateway which receives the request. If it's zero, all the ports are matched.
+	Port   uint32
+	Host   string
+	Path   string
+	Method string
+}
+
+// ExplainedRoute is the route which the request hits
+type ExplainedRoute struct {
+	// Kind is the kind of the resource which generates the route, like VirtualService or HTTPRoute
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// RouteName is the name of the route in the RDS
+	RouteName string `json:"routeName"`
+}
+
+// ExplainedPolicy is the FilterPolicy which configures the request
+type ExplainedPolicy struct {
+	// Policy is the namespace/name of the FilterPolicy
+	Policy string `json:"policy"`
+	// Level is the attachment kind of the policy, either "Route" or "Listener"
+	Level   mosniov1.FilterPolicyAttachmentKind `json:"level"`
+	Plugins []string                            `json:"plugins,omitempty"`
+}
+
+// ExplainedPlugin is the plugin which will be executed
+type ExplainedPlugin struct {
+	Name   string                              `json:"name"`
+	Policy string                              `json:"policy"`
+	Level  mosniov1.FilterPolicyAttachmentKind `json:"level"`
+}
+
+// OverriddenPlugin is the plugin configured by a policy but doesn't take effect
+type OverriddenPlugin struct {
+	Name   string `json:"name"`
+	Policy string `json:"policy"`
+	// By is the namespace/name of the FilterPolicy whose configuration takes effect
+	By string `json:"by"`
+}
+
+// Explanation describes how the request is configured
+type Explanation struct {
+	// Gateway is the kind and namespace/name of the gateway, like "Gateway istio-system/default"
+	Gateway     string          `json:"gateway"`
+	Listener    string          `json:"listener"`
+	VirtualHost string          `json:"virtualHost"`
+	Route       *ExplainedRoute `json:"route"`
+	// Policies are the FilterPolicies which configure the request
+	Policies []*ExplainedPolicy `json:"policies,omitempty"`
+	// Plugins are sorted in the execution order
+	Plugins    []*ExplainedPlugin  `json:"plugins,omitempty"`
+	Overridden []*OverriddenPlugin `json:"overridden,omitempty"`
+	// Notes describe the limitation of the explanation, for example, the truncated attachments
+	Notes []string `json:"notes,omitempty"`
+	// Warnings describe why the request may actually hit another route, for example, the match conditions
+	// not evaluated. The explanation can't be trusted when there are warnings.
+	Warnings []string `json:"warnings,omitempty"`
+	Rejected []string `json:"rejected,omitempty"`
+}
+
+// routeMatch is the result of matching the request with the gateway and routes
+type routeMatch struct {
+	listener    string
+	virtualHost string
+	route       *ExplainedRoute
+	warnings    []string
+}
+
+var errNoRouteMatched = errors.New("no route matched")
+
+// Explain shows how the request is configured by the FilterPolicies. The gateway host is matched in the same
+// way as the translation, then the route is matched by the host, path and method. The other match conditions
+// are assumed to match and are reported in the warnings.
+func Explain(ctx context.Context, objects []client.Object, req *ExplainRequest) (*Explanation, error) {
+	if req.Path == "" {
+		req.Path = "/"
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+
+	var m *routeMatch
+	var gwDesc string
+	var err error
+	found := false
+	for _, obj := range objects {
+		if obj.GetNamespace() != req.Gateway.Namespace || obj.GetName() != req.Gateway.Name {
+			continue
+		}
+
+		switch gw := obj.(type) {
+		case *istiov1a3.Gateway:
+			gwDesc = "Gateway " + req.Gateway.String() + " (networking.istio.io)"
+			m, err = matchIstioGateway(objects, gw, req)
+		case *gwapiv1b1.Gateway:
+			gwDesc = "Gateway " + req.Gateway.String() + " (gateway.networking.k8s.io)"
+			m, err = matchK8sGateway(objects, gw, req)
+		default:
+			continue
+		}
+		found = true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("gateway %s not found", req.Gateway)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, rm, err := render(ctx, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Explanation{
+		Gateway:     gwDesc,
+		Listener:    m.listener,
+		VirtualHost: m.virtualHost,
+		Route:       m.route,
+		Warnings:    m.warnings,
+		Rejected:    res.Rejected,
+	}
+	explainPlugins(exp, req.Gateway.Namespace, rm.policies)
+	return exp, nil
+}
+
+func explainPlugins(exp *Explanation, ns string, policies []*mosniov1.FilterPolicy) {
+	var routePolicies, listenerPolicies []*ExplainedPolicy
+	for _, policy := range policies {
+		nsName := policy.Namespace + "/" + policy.Name
+		if policy.Status.TruncatedAttachments > 0 {
+			exp.Notes = append(exp.Notes, fmt.Sprintf("%d attachments of policy %s are truncated, its plugins may be missing",
+				policy.Status.TruncatedAttachments, nsName))
+		}
+		for _, a := range policy.Status.Attachments {
+			if a.Namespace != ns {
+				continue
+			}
+
+			var ep *ExplainedPolicy
+			switch {
+			case a.Kind == mosniov1.FilterPolicyAttachmentKindRoute && a.VirtualHost == exp.VirtualHost &&
+				a.Name == exp.Route.RouteName:
+				ep = &ExplainedPolicy{Policy: nsName, Level: a.Kind, Plugins: a.Plugins}
+				routePolicies = append(routePolicies, ep)
+			case a.Kind == mosniov1.FilterPolicyAttachmentKindListener && a.Name == exp.Listener:
+				ep = &ExplainedPolicy{Policy: nsName, Level: a.Kind, Plugins: a.Plugins}
+				listenerPolicies = append(listenerPolicies, ep)
+			default:
+				continue
+			}
+
+			for _, sp := range a.ShadowedPlugins {
+				exp.Overridden = append(exp.Overridden, &OverriddenPlugin{
+					Name:   sp.Name,
+					Policy: nsName,
+					By:     sp.By,
+				})
+			}
+		}
+	}
+
+	plugins := make(map[string]*ExplainedPlugin)
+	for _, ep := range routePolicies {
+		for _, name := range ep.Plugins {
+			plugins[name] = &ExplainedPlugin{Name: name, Policy: ep.Policy, Level: ep.Level}
+		}
+	}
+	for _, ep := range listenerPolicies {
+		for _, name := range ep.Plugins {
+			if p, ok := plugins[name]; ok {
+				// the data plane prefers the route's configuration to the listener's
+				exp.Overridden = append(exp.Overridden, &OverriddenPlugin{
+					Name:   name,
+					Policy: ep.Policy,
+					By:     p.Policy,
+				})
+				continue
+			}
+			plugins[name] = &ExplainedPlugin{Name: name, Policy: ep.Policy, Level: ep.Level}
+		}
+	}
+
+	exp.Policies = append(routePolicies, listenerPolicies...)
+	for _, p := range plugins {
+		exp.Plugins = append(exp.Plugins, p)
+	}
+	sortExplainedPlugins(exp.Plugins)
+	sort.Slice(exp.Overridden, func(i, j int) bool {
+		if exp.Overridden[i].Name != exp.Overridden[j].Name {
+			return exp.Overridden[i].Name < exp.Overridden[j].Name
+		}
+		return exp.Overridden[i].Policy < exp.Overridden[j].Policy
+	})
+}
+
+func sortExplainedPlugins(ps []*ExplainedPlugin) {
+	sort.Slice(ps, func(i, j int) bool {
+		if c := plugins.ComparePluginOrderInt(ps[i].Name, ps[j].Name); c != 0 {
+			return c < 0
+		}
+		return ps[i].Name < ps[j].Name
+	})
+}
+
+// hostSpecificity is used to choose the most specific virtual host like Envoy: the exact host is
+// preferred, then the longest wildcard host.
+func hostSpecificity(host string) int {
+	if host == "" {
+		return -1
+	}
+	if strings.HasPrefix(host, "*") {
+		return len(host)
+	}
+	// exact host always wins
+	return 1 << 20
+}
+
+// hostMatch checks if the pattern, which may be wildcarded, matches the host of the request
+func hostMatch(pattern string, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return pattern == host
+}
+
+func matchRegex(pattern string, s string) bool {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+func matchIstioString(m *istioapi.StringMatch, s string) bool {
+	if m == nil {
+		return true
+	}
+	switch {
+	case m.GetExact() != "":
+		return m.GetExact() == s
+	case m.GetPrefix() != "":
+		return strings.HasPrefix(s, m.GetPrefix())
+	case m.GetRegex() != "":
+		return matchRegex(m.GetRegex(), s)
+	}
+	return true
+}
+
+// unevaluatedIstioConditions returns the match conditions other than the uri and the method
+func unevaluatedIstioConditions(m *istioapi.HTTPMatchRequest) []string {
+	var conds []string
+	for _, name := range sortedKeys(m.Headers) {
+		conds = append(conds, "header "+name)
+	}
+	for _, name := range sortedKeys(m.WithoutHeaders) {
+		conds = append(conds, "withoutHeader "+name)
+	}
+	for _, name := range sortedKeys(m.QueryParams) {
+		conds = append(conds, "queryParam "+name)
+	}
+	if m.Authority != nil {
+		conds = append(conds, "authority")
+	}
+	if m.Scheme != nil {
+		conds = append(conds, "scheme")
+	}
+	if m.Port != 0 {
+		conds = append(conds, "port")
+	}
+	if m.IgnoreUriCase {
+		conds = append(conds, "ignoreUriCase")
+	}
+	if len(m.SourceLabels) > 0 {
+		conds = append(conds, "sourceLabels")
+	}
+	if m.SourceNamespace != "" {
+		conds = append(conds, "sourceNamespace")
+	}
+	if len(m.Gateways) > 0 {
+		conds = append(conds, "gateways")
+	}
+	return conds
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matchIstioHTTPRoute returns true if one of the match conditions is matched. The conditions other than
+// the uri and the method are not evaluated and are assumed to match, so they are returned as warnings.
+func matchIstioHTTPRoute(route *istioapi.HTTPRoute, path, method string) (bool, []string) {
+	if len(route.Match) == 0 {
+		return true, nil
+	}
+	var warnings []string
+	for i, m := range route.Match {
+		if !matchIstioString(m.Uri, path) || !matchIstioString(m.Method, method) {
+			continue
+		}
+		conds := unevaluatedIstioConditions(m)
+		if len(conds) == 0 {
+			return true, nil
+		}
+		warnings = append(warnings, fmt.Sprintf("match %d of route %q has conditions not evaluated: %s. "+
+			"The request may hit a later route if they are not matched", i, route.Name, strings.Join(conds, ", ")))
+	}
+	if len(warnings) > 0 {
+		return true, warnings
+	}
+	return false, nil
+}
+
+// vsReferGateway checks if the VirtualService refers to the gateway. Like the FilterPolicy controller,
+// the gateway from other namespace is not supported.
+func vsReferGateway(vs *istiov1a3.VirtualService, gw *istiov1a3.Gateway) bool {
+	if vs.Namespace != gw.Namespace {
+		return false
+	}
+	for _, ref := range vs.Spec.Gateways {
+		if ref == gw.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPProtocol(protocol string) bool {
+	switch strings.ToUpper(protocol) {
+	case "HTTP", "HTTPS", "GRPC", "GRPC-WEB", "HTTP2":
+		return true
+	}
+	return false
+}
+
+func matchIstioGateway(objects []client.Object, gw *istiov1a3.Gateway, req *ExplainRequest) (*routeMatch, error) {
+	var vss []*istiov1a3.VirtualService
+	for _, obj := range objects {
+		if vs, ok := obj.(*istiov1a3.VirtualService); ok && vsReferGateway(vs, gw) {
+			vss = append(vss, vs)
+		}
+	}
+	// Istio merges the VirtualServices of the same host in the order of creation
+	sort.SliceStable(vss, func(i, j int) bool {
+		ti, tj := vss[i].CreationTimestamp, vss[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		if vss[i].Namespace != vss[j].Namespace {
+			return vss[i].Namespace < vss[j].Namespace
+		}
+		return vss[i].Name < vss[j].Name
+	})
+
+	for _, svr := range gw.Spec.Servers {
+		if svr.Port == nil || !isHTTPProtocol(svr.Port.Protocol) {
+			continue
+		}
+		port := svr.Port.Number
+		if req.Port != 0 && port != req.Port {
+			continue
+		}
+
+		// choose the most specific host of the VirtualServices, like what Envoy does
+		bestHost := ""
+		for _, vs := range vss {
+			for _, h := range vs.Spec.Hosts {
+				if !hostMatch(h, req.Host) {
+					continue
+				}
+				for _, sh := range svr.Hosts {
+					if translation.HostMatch(sh, h) && hostSpecificity(h) > hostSpecificity(bestHost) {
+						bestHost = h
+					}
+				}
+			}
+		}
+		if bestHost == "" {
+			continue
+		}
+
+		var merged []string
+		for _, vs := range vss {
+			if containsString(vs.Spec.Hosts, bestHost) {
+				merged = append(merged, vs.Namespace+"/"+vs.Name)
+			}
+		}
+		var mergeWarning string
+		if len(merged) > 1 {
+			mergeWarning = fmt.Sprintf("the routes of VirtualServices %s are merged for host %s. "+
+				"They are assumed to be merged in the order of creation, which may differ from the actual order "+
+				"when the VirtualServices are recreated or come from multiple sources",
+				strings.Join(merged, ", "), bestHost)
+		}
+
+		for _, vs := range vss {
+			if !containsString(vs.Spec.Hosts, bestHost) {
+				continue
+			}
+			for _, route := range vs.Spec.Http {
+				matched, warnings := matchIstioHTTPRoute(route, req.Path, req.Method)
+				if !matched {
+					continue
+				}
+				m := &routeMatch{
+					listener:    translation.LDSName(svr.Bind, port),
+					virtualHost: net.JoinHostPort(bestHost, strconv.Itoa(int(port))),
+					route: &ExplainedRoute{
+						Kind:      "VirtualService",
+						Namespace: vs.Namespace,
+						Name:      vs.Name,
+						RouteName: route.Name,
+					},
+				}
+				if mergeWarning != "" {
+					m.warnings = append(m.warnings, mergeWarning)
+				}
+				m.warnings = append(m.warnings, warnings...)
+				return m, nil
+			}
+		}
+	}
+	return nil, errNoRouteMatched
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func routeReferGateway(route *gwapiv1b1.HTTPRoute, gw *gwapiv1b1.Gateway) []gwapiv1.ParentReference {
+	var refs []gwapiv1.ParentReference
+	for _, ref := range route.Spec.ParentRefs {
+		if ref.Group != nil && *ref.Group != gwapiv1.GroupName {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != "Gateway" {
+			continue
+		}
+		// Like the FilterPolicy controller, the gateway from other namespace is not supported
+		if ref.Namespace != nil && string(*ref.Namespace) != route.Namespace {
+			continue
+		}
+		if route.Namespace == gw.Namespace && string(ref.Name) == gw.Name {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// k8sRuleMatch is a matched rule of HTTPRoute. It's used to choose the rule according to the precedence
+// defined in the Gateway API.
+type k8sRuleMatch struct {
+	route     *gwapiv1b1.HTTPRoute
+	ruleIdx   int
+	exact     bool
+	prefixLen int
+	method    bool
+	// headers and queryParams are not evaluated, but they affect the precedence
+	headers     int
+	queryParams int
+	warning     string
+}
+
+func matchK8sPath(m *gwapiv1.HTTPPathMatch, path string) (bool, bool, int) {
+	typ := gwapiv1.PathMatchPathPrefix
+	value := "/"
+	if m != nil {
+		if m.Type != nil {
+			typ = *m.Type
+		}
+		if m.Value != nil {
+			value = *m.Value
+		}
+	}
+
+	switch typ {
+	case gwapiv1.PathMatchExact:
+		return value == path, true, len(value)
+	case gwapiv1.PathMatchRegularExpression:
+		return matchRegex(value, path), false, 0
+	default:
+		// The prefix is matched element-wise
+		prefix := strings.TrimSuffix(value, "/")
+		if prefix == "" {
+			return true, false, 1
+		}
+		ok := path == prefix || strings.HasPrefix(path, prefix+"/")
+		return ok, false, len(value)
+	}
+}
+
+func matchK8sRule(route *gwapiv1b1.HTTPRoute, idx int, path, method string) *k8sRuleMatch {
+	rule := &route.Spec.Rules[idx]
+	matches := rule.Matches
+	if len(matches) == 0 {
+		matches = []gwapiv1.HTTPRouteMatch{{}}
+	}
+
+	var best *k8sRuleMatch
+	for _, m := range matches {
+		ok, exact, prefixLen := matchK8sPath(m.Path, path)
+		if !ok {
+			continue
+		}
+		if m.Method != nil && string(*m.Method) != method {
+			continue
+		}
+
+		cur := &k8sRuleMatch{
+			route:       route,
+			ruleIdx:     idx,
+			exact:       exact,
+			prefixLen:   prefixLen,
+			method:      m.Method != nil,
+			headers:     len(m.Headers),
+			queryParams: len(m.QueryParams),
+		}
+		if len(m.Headers) > 0 || len(m.QueryParams) > 0 {
+			var conds []string
+			for _, h := range m.Headers {
+				conds = append(conds, "header "+string(h.Name))
+			}
+			for _, q := range m.QueryParams {
+				conds = append(conds, "queryParam "+string(q.Name))
+			}
+			cur.warning = fmt.Sprintf("rule %d of HTTPRoute %s/%s has conditions not evaluated: %s. "+
+				"The request may hit another rule if they are not matched",
+				idx, route.Namespace, route.Name, strings.Join(conds, ", "))
+		}
+		if best == nil || higherPrecedence(cur, best) {
+			best = cur
+		}
+	}
+	return best
+}
+
+func higherPrecedence(a, b *k8sRuleMatch) bool {
+	if a.exact != b.exact {
+		return a.exact
+	}
+	if a.prefixLen != b.prefixLen {
+		return a.prefixLen > b.prefixLen
+	}
+	if a.method != b.method {
+		return a.method
+	}
+	if a.headers != b.headers {
+		return a.headers > b.headers
+	}
+	if a.queryParams != b.queryParams {
+		return a.queryParams > b.queryParams
+	}
+	ta, tb := a.route.CreationTimestamp, b.route.CreationTimestamp
+	if !ta.Equal(&tb) {
+		return ta.Before(&tb)
+	}
+	if a.route.Namespace != b.route.Namespace {
+		return a.route.Namespace < b.route.Namespace
+	}
+	if a.route.Name != b.route.Name {
+		return a.route.Name < b.route.Name
+	}
+	return a.ruleIdx < b.ruleIdx
+}
+
+func matchK8sGateway(objects []client.Object, gw *gwapiv1b1.Gateway, req *ExplainRequest) (*routeMatch, error) {
+	gwNsName := &types.NamespacedName{Namespace: gw.Namespace, Name: gw.Name}
+	for i := range gw.Spec.Listeners {
+		ls := &gw.Spec.Listeners[i]
+		if ls.Protocol != gwapiv1.HTTPProtocolType && ls.Protocol != gwapiv1.HTTPSProtocolType {
+			continue
+		}
+		if req.Port != 0 && uint32(ls.Port) != req.Port {
+			continue
+		}
+		if ls.Hostname != nil && !hostMatch(string(*ls.Hostname), req.Host) {
+			continue
+		}
+
+		bestHost := ""
+		var best *k8sRuleMatch
+		for _, obj := range objects {
+			route, ok := obj.(*gwapiv1b1.HTTPRoute)
+			if !ok {
+				continue
+			}
+			refs := routeReferGateway(route, gw)
+			if len(refs) == 0 {
+				continue
+			}
+			route = route.DeepCopy()
+			route.SetGroupVersionKind(gwapiv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+			if !translation.ListenerAttachedByRoute(ls, refs, route, gwNsName) {
+				continue
+			}
+
+			hostnames := route.Spec.Hostnames
+			if len(hostnames) == 0 {
+				hostnames = []gwapiv1.Hostname{"*"}
+			}
+			for _, hostname := range hostnames {
+				h := string(hostname)
+				if !hostMatch(h, req.Host) {
+					continue
+				}
+				if h == "*" && ls.Hostname != nil {
+					h = string(*ls.Hostname)
+				}
+				if hostSpecificity(h) < hostSpecificity(bestHost) {
+					continue
+				}
+				if hostSpecificity(h) > hostSpecificity(bestHost) {
+					bestHost = h
+					best = nil
+				}
+
+				for idx := range route.Spec.Rules {
+					cur := matchK8sRule(route, idx, req.Path, req.Method)
+					if cur != nil && (best == nil || higherPrecedence(cur, best)) {
+						best = cur
+					}
+				}
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		m := &routeMatch{
+			// When Istio converts k8s gateway to istio gateway, the bind field is empty
+			listener:    translation.LDSName("", uint32(ls.Port)),
+			virtualHost: net.JoinHostPort(bestHost, strconv.Itoa(int(ls.Port))),
+			route: &ExplainedRoute{
+				Kind:      "HTTPRoute",
+				Namespace: best.route.Namespace,
+				Name:      best.route.Name,
+				RouteName: fmt.Sprintf("%s.%s.%d", best.route.Namespace, best.route.Name, best.ruleIdx),
+			},
+		}
+		if best.warning != "" {
+			m.warnings = append(m.warnings, best.warning)
+		}
+		return m, nil
+	}
+	return nil, errNoRouteMatched
+}
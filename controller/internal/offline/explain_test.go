@@ -0,0 +1,160 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"mosn.io/htnn/controller/internal/config"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+func pluginSources(exp *Explanation) []string {
+	var res []string
+	for _, p := range exp.Plugins {
+		res = append(res, p.Name+"@"+p.Policy)
+	}
+	return res
+}
+
+func TestExplainIstio(t *testing.T) {
+	os.Setenv("HTNN_ENABLE_LDS_PLUGIN_VIA_ECDS", "true")
+	config.Init()
+	defer func() {
+		os.Setenv("HTNN_ENABLE_LDS_PLUGIN_VIA_ECDS", "false")
+		config.Init()
+	}()
+
+	loaded, err := Load([]string{filepath.Join("testdata", "explain")})
+	require.NoError(t, err)
+	gw := types.NamespacedName{Namespace: "default", Name: "gw"}
+
+	exp, err := Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: gw,
+		Host:    "default.local",
+		Path:    "/echo",
+		Method:  "POST",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0_80", exp.Listener)
+	assert.Equal(t, "default.local:80", exp.VirtualHost)
+	assert.Equal(t, &ExplainedRoute{
+		Kind:      "VirtualService",
+		Namespace: "default",
+		Name:      "vs",
+		RouteName: "echo",
+	}, exp.Route)
+	assert.Len(t, exp.Policies, 3)
+	assert.Equal(t, []string{
+		"keyAuth@default/b-policy",
+		"limitReq@default/gw-policy",
+		"demo@default/a-policy",
+	}, pluginSources(exp))
+	assert.Equal(t, mosniov1.FilterPolicyAttachmentKindListener, exp.Plugins[1].Level)
+	assert.Equal(t, []*OverriddenPlugin{
+		{Name: "demo", Policy: "default/gw-policy", By: "default/a-policy"},
+		{Name: "keyAuth", Policy: "default/a-policy", By: "default/b-policy"},
+	}, exp.Overridden)
+
+	// the method doesn't match the first route
+	exp, err = Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: gw,
+		Host:    "default.local",
+		Path:    "/echo",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "route", exp.Route.RouteName)
+	assert.Equal(t, []string{
+		"keyAuth@default/a-policy",
+		"limitReq@default/gw-policy",
+		"demo@default/a-policy",
+	}, pluginSources(exp))
+
+	_, err = Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: gw,
+		Host:    "other.local",
+	})
+	assert.ErrorContains(t, err, "no route matched")
+
+	_, err = Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: types.NamespacedName{Namespace: "default", Name: "nonexistent"},
+		Host:    "default.local",
+	})
+	assert.ErrorContains(t, err, "gateway default/nonexistent not found")
+}
+
+func TestExplainGatewayAPI(t *testing.T) {
+	loaded, err := Load([]string{filepath.Join("testdata", "render")})
+	require.NoError(t, err)
+
+	exp, err := Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: types.NamespacedName{Namespace: "ns", Name: "gw"},
+		Host:    "www.example.com",
+		Path:    "/anything",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0_80", exp.Listener)
+	assert.Equal(t, "www.example.com:80", exp.VirtualHost)
+	assert.Equal(t, &ExplainedRoute{
+		Kind:      "HTTPRoute",
+		Namespace: "ns",
+		Name:      "route",
+		RouteName: "ns.route.0",
+	}, exp.Route)
+	assert.Equal(t, []string{"demo@ns/policy"}, pluginSources(exp))
+	assert.Empty(t, exp.Overridden)
+	// the unrelated rejected policy is still reported
+	assert.Len(t, exp.Rejected, 1)
+
+	_, err = Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: types.NamespacedName{Namespace: "ns", Name: "gw"},
+		Host:    "www.example.org",
+	})
+	assert.ErrorContains(t, err, "no route matched")
+}
+
+func TestExplainWarnings(t *testing.T) {
+	loaded, err := Load([]string{filepath.Join("testdata", "explain_warning")})
+	require.NoError(t, err)
+	gw := types.NamespacedName{Namespace: "default", Name: "gw"}
+
+	exp, err := Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: gw,
+		Host:    "default.local",
+		Path:    "/echo",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "canary", exp.Route.RouteName)
+	require.Len(t, exp.Warnings, 2)
+	assert.Contains(t, exp.Warnings[0], "VirtualServices default/a-vs, default/b-vs are merged")
+	assert.Contains(t, exp.Warnings[1], "header x-canary, queryParam debug")
+
+	exp, err = Explain(context.Background(), loaded.Objects, &ExplainRequest{
+		Gateway: gw,
+		Host:    "default.local",
+		Path:    "/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "route", exp.Route.RouteName)
+	require.Len(t, exp.Warnings, 1)
+	assert.Contains(t, exp.Warnings[0], "are merged")
+}
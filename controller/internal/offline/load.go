@@ -0,0 +1,152 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package offline drives the controllers with the resources read from local files, so that the
+// translation result can be reviewed without a cluster.
+package offline
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/yaml"
+
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+const defaultNamespace = "default"
+
+// The resources are converted to the version used by the controllers. As the resources of different
+// versions share the same schema, the conversion is done by unmarshaling the data into the target version.
+var supportedKinds = map[schema.GroupKind]func() client.Object{
+	{Group: "htnn.mosn.io", Kind: "FilterPolicy"}:           func() client.Object { return &mosniov1.FilterPolicy{} },
+	{Group: "htnn.mosn.io", Kind: "Consumer"}:               func() client.Object { return &mosniov1.Consumer{} },
+	{Group: "networking.istio.io", Kind: "VirtualService"}:  func() client.Object { return &istiov1a3.VirtualService{} },
+	{Group: "networking.istio.io", Kind: "Gateway"}:         func() client.Object { return &istiov1a3.Gateway{} },
+	{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}: func() client.Object { return &gwapiv1b1.HTTPRoute{} },
+	{Group: "gateway.networking.k8s.io", Kind: "Gateway"}:   func() client.Object { return &gwapiv1b1.Gateway{} },
+	{Group: "gateway.networking.k8s.io", Kind: "GRPCRoute"}: func() client.Object { return &gwapiv1a2.GRPCRoute{} },
+	{Group: "gateway.networking.k8s.io", Kind: "TCPRoute"}:  func() client.Object { return &gwapiv1a2.TCPRoute{} },
+	{Group: "gateway.networking.k8s.io", Kind: "TLSRoute"}:  func() client.Object { return &gwapiv1a2.TLSRoute{} },
+}
+
+// LoadResult contains the resources loaded from the files
+type LoadResult struct {
+	Objects []client.Object
+	// Skipped records the resources which are not supported, like "Service default/foo".
+	Skipped []string
+}
+
+// Load reads the resources from the given files. A directory is read recursively and only the files
+// with the .yaml, .yml or .json suffix are read. The resources without namespace are put into the
+// "default" namespace.
+func Load(paths []string) (*LoadResult, error) {
+	res := &LoadResult{}
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(fn string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if fn != path {
+				ext := filepath.Ext(fn)
+				if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+					return nil
+				}
+			}
+
+			f, err := os.Open(fn)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if err := res.decode(f); err != nil {
+				return fmt.Errorf("failed to load %s: %w", fn, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (res *LoadResult) decode(r io.Reader) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var meta metav1.PartialObjectMetadata
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return err
+		}
+		if meta.Kind == "" {
+			// skip the document which only contains comments
+			continue
+		}
+
+		gvk := meta.GroupVersionKind()
+		if strings.HasSuffix(meta.Kind, "List") {
+			return fmt.Errorf("list kind %s is not supported, please split it into multiple documents", meta.Kind)
+		}
+
+		newObj := supportedKinds[gvk.GroupKind()]
+		if newObj == nil {
+			name := meta.Name
+			if meta.Namespace != "" {
+				name = meta.Namespace + "/" + name
+			}
+			res.Skipped = append(res.Skipped, meta.Kind+" "+name)
+			continue
+		}
+
+		obj := newObj()
+		if err := yaml.Unmarshal(doc, obj); err != nil {
+			return fmt.Errorf("failed to unmarshal %s %s: %w", meta.Kind, meta.Name, err)
+		}
+		// the apiVersion is set according to the Go type
+		obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNamespace)
+		}
+		obj.SetResourceVersion("")
+		res.Objects = append(res.Objects, obj)
+	}
+}
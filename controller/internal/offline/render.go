@@ -0,0 +1,191 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	istioapi "istio.io/api/networking/v1alpha3"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istioscheme "istio.io/client-go/pkg/clientset/versioned/scheme"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"mosn.io/htnn/controller/internal/controller"
+	"mosn.io/htnn/controller/internal/gatewayapi"
+	"mosn.io/htnn/controller/pkg/component"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		mosniov1.AddToScheme,
+		istioscheme.AddToScheme,
+		gatewayapi.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			return nil, err
+		}
+	}
+	return scheme, nil
+}
+
+// resourceManager reads the resources from the memory. The status is not stored, but the rejected resources
+// and the FilterPolicies with status are recorded.
+type resourceManager struct {
+	client client.Client
+
+	rejected []string
+	policies []*mosniov1.FilterPolicy
+}
+
+func (r *resourceManager) Get(ctx context.Context, key client.ObjectKey, out client.Object) error {
+	return r.client.Get(ctx, key, out)
+}
+
+func (r *resourceManager) List(ctx context.Context, list client.ObjectList) error {
+	return r.client.List(ctx, list)
+}
+
+func (r *resourceManager) UpdateStatus(_ context.Context, obj client.Object, _ any) error {
+	var kind string
+	var conds []metav1.Condition
+	switch o := obj.(type) {
+	case *mosniov1.FilterPolicy:
+		kind = "FilterPolicy"
+		conds = o.Status.Conditions
+		r.policies = append(r.policies, o.DeepCopy())
+	case *mosniov1.Consumer:
+		kind = "Consumer"
+		conds = o.Status.Conditions
+	default:
+		return nil
+	}
+
+	cond := apimeta.FindStatusCondition(conds, string(mosniov1.ConditionAccepted))
+	if cond != nil && cond.Status == metav1.ConditionFalse {
+		r.rejected = append(r.rejected, fmt.Sprintf("%s %s/%s is not accepted: %s",
+			kind, obj.GetNamespace(), obj.GetName(), cond.Message))
+	}
+	return nil
+}
+
+func newResourceManager(objects []client.Object) (*resourceManager, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+	// the objects will be modified by the controllers, so we copy them
+	objs := make([]client.Object, len(objects))
+	for i, obj := range objects {
+		objs[i] = obj.DeepCopyObject().(client.Object)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &resourceManager{client: c}, nil
+}
+
+// memoryOutput collects the generated EnvoyFilters
+type memoryOutput struct {
+	envoyFilters map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter
+}
+
+func (o *memoryOutput) FromFilterPolicy(_ context.Context, efs map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
+	for key, ef := range efs {
+		o.envoyFilters[key] = ef
+	}
+	return nil
+}
+
+func (o *memoryOutput) FromConsumer(_ context.Context, ef *istiov1a3.EnvoyFilter) error {
+	o.envoyFilters[component.EnvoyFilterKey{Namespace: ef.Namespace, Name: ef.Name}] = ef
+	return nil
+}
+
+func (o *memoryOutput) FromServiceRegistry(_ context.Context, _ map[string]*istioapi.ServiceEntry) {
+}
+
+func (o *memoryOutput) FromDynamicConfig(_ context.Context, efs map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
+	for key, ef := range efs {
+		o.envoyFilters[key] = ef
+	}
+	return nil
+}
+
+// RenderResult is the result of Render
+type RenderResult struct {
+	// EnvoyFilters are sorted by namespace and name
+	EnvoyFilters []*istiov1a3.EnvoyFilter
+	// Rejected describes the resources which are not accepted, for example, because of invalid configuration
+	Rejected []string
+}
+
+// Render runs the FilterPolicy and Consumer controllers with the given resources, and returns the
+// generated EnvoyFilters.
+func Render(ctx context.Context, objects []client.Object) (*RenderResult, error) {
+	res, _, err := render(ctx, objects)
+	return res, err
+}
+
+func render(ctx context.Context, objects []client.Object) (*RenderResult, *resourceManager, error) {
+	rm, err := newResourceManager(objects)
+	if err != nil {
+		return nil, nil, err
+	}
+	output := &memoryOutput{
+		envoyFilters: make(map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter),
+	}
+
+	fpr := controller.NewFilterPolicyReconciler(output, rm)
+	if _, err := fpr.Reconcile(ctx, ctrl.Request{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to render FilterPolicy: %w", err)
+	}
+
+	for _, obj := range objects {
+		if _, ok := obj.(*mosniov1.Consumer); ok {
+			cr := &controller.ConsumerReconciler{
+				ResourceManager: rm,
+				Output:          output,
+			}
+			if _, err := cr.Reconcile(ctx, ctrl.Request{}); err != nil {
+				return nil, nil, fmt.Errorf("failed to render Consumer: %w", err)
+			}
+			break
+		}
+	}
+
+	efs := make([]*istiov1a3.EnvoyFilter, 0, len(output.envoyFilters))
+	for _, ef := range output.envoyFilters {
+		ef.SetGroupVersionKind(istiov1a3.SchemeGroupVersion.WithKind("EnvoyFilter"))
+		efs = append(efs, ef)
+	}
+	sort.Slice(efs, func(i, j int) bool {
+		if efs[i].Namespace != efs[j].Namespace {
+			return efs[i].Namespace < efs[j].Namespace
+		}
+		return efs[i].Name < efs[j].Name
+	})
+	sort.Strings(rm.rejected)
+	return &RenderResult{
+		EnvoyFilters: efs,
+		Rejected:     rm.rejected,
+	}, rm, nil
+}
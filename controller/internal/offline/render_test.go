@@ -0,0 +1,71 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestLoad(t *testing.T) {
+	res, err := Load([]string{filepath.Join("testdata", "render")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Service httpbin"}, res.Skipped)
+	assert.Len(t, res.Objects, 8)
+
+	for _, obj := range res.Objects {
+		assert.NotEmpty(t, obj.GetNamespace())
+		if route, ok := obj.(*gwapiv1b1.HTTPRoute); ok {
+			// converted from v1
+			assert.Equal(t, "www.example.com", string(route.Spec.Hostnames[0]))
+		}
+	}
+
+	_, err = Load([]string{filepath.Join("testdata", "nonexistent")})
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	loaded, err := Load([]string{filepath.Join("testdata", "render")})
+	require.NoError(t, err)
+
+	res, err := Render(context.Background(), loaded.Objects)
+	require.NoError(t, err)
+
+	var names []string
+	for _, ef := range res.EnvoyFilters {
+		assert.Equal(t, "EnvoyFilter", ef.Kind)
+		names = append(names, ef.Namespace+"/"+ef.Name)
+	}
+	assert.Equal(t, []string{
+		"default/htnn-h-default.local",
+		"istio-system/htnn-consumer",
+		"istio-system/htnn-http-filter",
+		"ns/htnn-h-www.example.com",
+	}, names)
+
+	require.Len(t, res.Rejected, 1)
+	assert.Contains(t, res.Rejected[0], "FilterPolicy default/bad is not accepted: invalid config for filter keyAuth")
+
+	// the input is not modified
+	for _, obj := range loaded.Objects {
+		assert.Empty(t, obj.GetResourceVersion())
+	}
+}
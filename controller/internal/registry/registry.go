@@ -26,7 +26,9 @@ import (
 
 var (
 	registries = map[types.NamespacedName]pkgRegistry.Registry{}
-	store      *serviceEntryStore
+	// the stores of the started registries
+	registryStores = map[types.NamespacedName]*registryStore{}
+	store          *serviceEntryStore
 )
 
 type RegistryManagerOption struct {
@@ -45,7 +47,8 @@ func UpdateRegistry(registry *mosniov1.ServiceRegistry, prevServiceRegistry *mos
 
 	key := types.NamespacedName{Namespace: registry.Namespace, Name: registry.Name}
 	if reg, ok := registries[key]; !ok {
-		reg, err := pkgRegistry.CreateRegistry(registry.Spec.Type, store, registry.ObjectMeta)
+		regStore := newRegistryStore(store, key)
+		reg, err := pkgRegistry.CreateRegistry(registry.Spec.Type, regStore, registry.ObjectMeta)
 		if err != nil {
 			return err
 		}
@@ -64,6 +67,7 @@ func UpdateRegistry(registry *mosniov1.ServiceRegistry, prevServiceRegistry *mos
 
 		// only started registry can be put into registries
 		registries[key] = reg
+		registryStores[key] = regStore
 
 	} else {
 		conf, err := registrytype.ParseConfig(reg, registry.Spec.Config.Raw)
@@ -90,6 +94,10 @@ func DeleteRegistry(key types.NamespacedName) error {
 	}
 
 	delete(registries, key)
+	if regStore, ok := registryStores[key]; ok {
+		delete(registryStores, key)
+		defer regStore.reset()
+	}
 	log.Infof("stop registry %s", key)
 	return prev.Stop()
 }
@@ -18,10 +18,13 @@ import (
 	"context"
 	"sync"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	"google.golang.org/protobuf/proto"
 	istioapi "istio.io/api/networking/v1alpha3"
 
 	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/metrics"
 	"mosn.io/htnn/controller/pkg/component"
 	pkgRegistry "mosn.io/htnn/controller/pkg/registry"
 )
@@ -75,3 +78,55 @@ func (store *serviceEntryStore) Delete(service string) {
 	delete(store.entries, service)
 	store.output.FromServiceRegistry(context.Background(), store.entries)
 }
+
+// registryStore is the view of the serviceEntryStore for a single registry. It tracks the services
+// synced from the registry for the metrics.
+type registryStore struct {
+	*serviceEntryStore
+	key types.NamespacedName
+
+	lock     sync.Mutex
+	services map[string]struct{}
+}
+
+func newRegistryStore(store *serviceEntryStore, key types.NamespacedName) *registryStore {
+	return &registryStore{
+		serviceEntryStore: store,
+		key:               key,
+		services:          make(map[string]struct{}),
+	}
+}
+
+func (store *registryStore) Update(service string, se *pkgRegistry.ServiceEntryWrapper) {
+	store.serviceEntryStore.Update(service, se)
+
+	store.lock.Lock()
+	store.services[service] = struct{}{}
+	store.recordServices()
+	store.lock.Unlock()
+}
+
+func (store *registryStore) Delete(service string) {
+	store.serviceEntryStore.Delete(service)
+
+	store.lock.Lock()
+	delete(store.services, service)
+	store.recordServices()
+	store.lock.Unlock()
+}
+
+func (store *registryStore) ReportSyncError(err error) {
+	metrics.ServiceRegistrySyncErrorsTotal.With(store.key.Namespace, store.key.Name).Increment()
+}
+
+// reset is called when the registry is stopped
+func (store *registryStore) reset() {
+	store.lock.Lock()
+	store.services = make(map[string]struct{})
+	store.recordServices()
+	store.lock.Unlock()
+}
+
+func (store *registryStore) recordServices() {
+	metrics.ServiceRegistryServices.With(store.key.Namespace, store.key.Name).Record(float64(len(store.services)))
+}
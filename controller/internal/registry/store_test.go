@@ -15,13 +15,19 @@
 package registry
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	istioapi "istio.io/api/networking/v1alpha3"
+	"k8s.io/apimachinery/pkg/types"
 
 	"mosn.io/htnn/controller/internal/controller/component"
+	"mosn.io/htnn/controller/internal/metrics"
 	pkgRegistry "mosn.io/htnn/controller/pkg/registry"
 	"mosn.io/htnn/controller/tests/pkg"
 )
@@ -52,3 +58,40 @@ func TestStoreRepeatedUpdate(t *testing.T) {
 
 	require.Equal(t, 1, counter)
 }
+
+func TestRegistryStoreMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics.InitMetrics(metrics.NewPrometheusProvider(reg))
+
+	client := pkg.FakeK8sClient(t)
+	out := component.NewK8sOutput(client)
+	patches := gomonkey.ApplyMethodFunc(out, "FromServiceRegistry", func(ctx interface{}, serviceEntries map[string]*istioapi.ServiceEntry) {
+	})
+	defer patches.Reset()
+
+	store := newRegistryStore(newServiceEntryStore(out), types.NamespacedName{Namespace: "default", Name: "nacos"})
+	for _, svc := range []string{"a", "b"} {
+		store.Update(svc, &pkgRegistry.ServiceEntryWrapper{
+			ServiceEntry: istioapi.ServiceEntry{
+				Hosts: []string{svc},
+			},
+		})
+	}
+	store.Delete("a")
+	store.ReportSyncError(errors.New("timeout"))
+
+	expected := `
+# HELP htnn_service_registry_services Number of services synced from the ServiceRegistry.
+# TYPE htnn_service_registry_services gauge
+htnn_service_registry_services{name="nacos",namespace="default"} 1
+# HELP htnn_service_registry_sync_errors_total Total number of errors happened when syncing services from the ServiceRegistry.
+# TYPE htnn_service_registry_sync_errors_total counter
+htnn_service_registry_sync_errors_total{name="nacos",namespace="default"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"htnn_service_registry_services", "htnn_service_registry_sync_errors_total"))
+
+	store.reset()
+	require.Equal(t, float64(0), testutil.ToFloat64(
+		metrics.ServiceRegistryServices.With("default", "nacos").(prometheus.Collector)))
+}
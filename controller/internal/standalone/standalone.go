@@ -0,0 +1,322 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package standalone runs the HTNN controller as an independent process outside istiod.
+// The reconcilers are driven by controller-runtime, and the generated EnvoyFilters and
+// ServiceEntries are written to the Kubernetes API server, where Istio picks them up.
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	istioscheme "istio.io/client-go/pkg/clientset/versioned/scheme"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/controller"
+	"mosn.io/htnn/controller/internal/controller/component"
+	"mosn.io/htnn/controller/internal/gatewayapi"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/internal/registry"
+	htnnwebhook "mosn.io/htnn/controller/internal/webhook"
+	"mosn.io/htnn/controller/internal/xds"
+	pkgcomponent "mosn.io/htnn/controller/pkg/component"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+const (
+	// DynamicConfigStatusPath is the path to receive the apply status of DynamicConfig reported by the data plane.
+	// It's served by the TLS server of the validating webhook, as the report carries a token.
+	DynamicConfigStatusPath = "/htnn/dynamicconfig/status"
+
+	// the timeout to wait for the cache in a readiness check
+	cacheSyncCheckTimeout = time.Second
+
+	// OutputK8s writes the generated EnvoyFilters and ServiceEntries to the Kubernetes API server
+	OutputK8s = "k8s"
+	// OutputXDS serves the generated configuration to Envoy via xDS
+	OutputXDS = "xds"
+)
+
+// Options configures the standalone controller
+type Options struct {
+	// MetricsBindAddress is the address the metrics server binds to. Set it to "0" to disable the server.
+	MetricsBindAddress string
+	// HealthProbeBindAddress is the address the /healthz and /readyz endpoints bind to.
+	// Set it to "0" to disable them.
+	HealthProbeBindAddress string
+
+	// LeaderElection makes only one replica reconcile and write the generated resources at a time.
+	// It should be enabled when running multiple replicas.
+	LeaderElection bool
+	// LeaderElectionID is the name of the Lease used in the leader election
+	LeaderElectionID string
+	// LeaderElectionNamespace is the namespace of the Lease. Default to the Istio root namespace.
+	LeaderElectionNamespace string
+
+	// WebhookCertDir is the directory which contains the tls.crt and tls.key used to serve the validating
+	// webhook. The webhook is not served if it's empty, unless HTNN_WEBHOOK_SELF_SIGNED_CERT is enabled.
+	WebhookCertDir string
+	// WebhookPort is the port of the validating webhook served with the certificate from the WebhookCertDir.
+	WebhookPort int
+
+	// Output is where the generated configuration goes, OutputK8s or OutputXDS. Default to OutputK8s.
+	Output string
+	// XDSAddress is the address the xDS server listens to when the Output is OutputXDS
+	XDSAddress string
+	// XDSRouteConfigFile is the file of the base route configurations served via RDS when the Output is OutputXDS
+	XDSRouteConfigFile string
+	// XDSNamespace is the namespace of the gateways served via xDS when the Output is OutputXDS.
+	// All the namespaces are served if it's empty.
+	XDSNamespace string
+}
+
+// NewScheme returns the scheme which contains all the resources watched by the controller
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, f := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		istioscheme.AddToScheme,
+		gatewayapi.AddToScheme,
+		mosniov1.AddToScheme,
+	} {
+		if err := f(scheme); err != nil {
+			return nil, err
+		}
+	}
+	return scheme, nil
+}
+
+// NewManager creates a controller-runtime manager with all the reconcilers of HTNN set up.
+// The config should be initialized before calling it.
+func NewManager(cfg *rest.Config, opts Options) (ctrl.Manager, error) {
+	scheme, err := NewScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	var xdsOutput *xds.Output
+	switch opts.Output {
+	case "", OutputK8s:
+	case OutputXDS:
+		// Each replica serves the Envoy connected to it, so all of them need to reconcile
+		if opts.LeaderElection {
+			return nil, errors.New("leader election can't be used with the xds output")
+		}
+		xdsOpts := xds.Options{Address: opts.XDSAddress, Namespace: opts.XDSNamespace}
+		if opts.XDSRouteConfigFile != "" {
+			xdsOpts.RouteConfigurations, err = xds.LoadRouteConfigurations(opts.XDSRouteConfigFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		xdsOutput = xds.NewOutput(xdsOpts)
+	default:
+		return nil, fmt.Errorf("unknown output %q", opts.Output)
+	}
+
+	leaderElectionNamespace := opts.LeaderElectionNamespace
+	if leaderElectionNamespace == "" {
+		leaderElectionNamespace = config.RootNamespace()
+	}
+
+	mgrOpts := ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: opts.MetricsBindAddress,
+		},
+		HealthProbeBindAddress:  opts.HealthProbeBindAddress,
+		LeaderElection:          opts.LeaderElection,
+		LeaderElectionID:        opts.LeaderElectionID,
+		LeaderElectionNamespace: leaderElectionNamespace,
+		// The process exits once the leadership is lost, so release the lease to speed up the failover
+		LeaderElectionReleaseOnCancel: true,
+	}
+	if opts.WebhookCertDir != "" {
+		mgrOpts.WebhookServer = webhook.NewServer(webhook.Options{
+			Port:    opts.WebhookPort,
+			CertDir: opts.WebhookCertDir,
+		})
+	}
+
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// The handlers are registered before the manager is created, so the resource manager
+	// is bound to the manager's client lazily.
+	rm := &lazyResourceManager{}
+	var mgr ctrl.Manager
+	statusCollector := controller.NewDynamicConfigStatusCollector(rm, controller.DynamicConfigStatusOptions{
+		Client:    client,
+		Namespace: leaderElectionNamespace,
+		Replica:   replicaName(),
+		IsLeader: func() bool {
+			if !opts.LeaderElection {
+				return true
+			}
+			select {
+			case <-mgr.Elected():
+				return true
+			default:
+				return false
+			}
+		},
+	})
+	mgr, err = ctrl.NewManager(cfg, mgrOpts)
+	if err != nil {
+		return nil, err
+	}
+	rm.ResourceManager = component.NewK8sResourceManager(mgr.GetClient())
+	// the statuses are collected by every replica, and only written by the leader
+	if err := mgr.Add(&nonLeaderRunnable{run: statusCollector.Start}); err != nil {
+		return nil, err
+	}
+
+	var output pkgcomponent.Output
+	if xdsOutput != nil {
+		if err := mgr.Add(xdsOutput); err != nil {
+			return nil, err
+		}
+		output = xdsOutput
+	} else {
+		output = component.NewK8sOutput(mgr.GetClient())
+	}
+
+	if err := setupReconcilers(mgr, client, rm, output); err != nil {
+		return nil, err
+	}
+	if err := setupWebhook(mgr, client, rm, statusCollector, opts); err != nil {
+		return nil, err
+	}
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return nil, err
+	}
+	if err := mgr.AddReadyzCheck("cache", cacheSyncedChecker(mgr.GetCache())); err != nil {
+		return nil, err
+	}
+	return mgr, nil
+}
+
+func setupReconcilers(mgr ctrl.Manager, client kubernetes.Interface, rm *lazyResourceManager, output pkgcomponent.Output) error {
+	if err := controller.NewFilterPolicyReconciler(output, rm).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	if err := (&controller.ConsumerReconciler{
+		ResourceManager: rm,
+		Output:          output,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	registry.InitRegistryManager(&registry.RegistryManagerOption{
+		Output: output,
+	})
+	if err := controller.NewServiceRegistryReconciler(rm).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	return (&controller.DynamicConfigReconciler{
+		ResourceManager: rm,
+		Output:          output,
+		RevisionStore:   controller.NewConfigMapRevisionStore(client),
+	}).SetupWithManager(mgr)
+}
+
+func setupWebhook(mgr ctrl.Manager, client kubernetes.Interface, rm *lazyResourceManager,
+	statusCollector http.Handler, opts Options) error {
+
+	handler := htnnwebhook.NewHandler(rm)
+	if config.WebhookSelfSignedCert() {
+		// the webhook is served by every replica, not only the leader
+		return mgr.Add(&nonLeaderRunnable{
+			run: func(ctx context.Context) error {
+				handlers := map[string]http.Handler{
+					htnnwebhook.ValidationPath: handler,
+					DynamicConfigStatusPath:    statusCollector,
+				}
+				if err := htnnwebhook.ServeWithSelfSignedCert(ctx, client, handlers); err != nil {
+					return err
+				}
+				<-ctx.Done()
+				return nil
+			},
+		})
+	}
+
+	if opts.WebhookCertDir != "" {
+		mgr.GetWebhookServer().Register(htnnwebhook.ValidationPath, handler)
+		mgr.GetWebhookServer().Register(DynamicConfigStatusPath, statusCollector)
+		return nil
+	}
+	log.Infof("the apply status of DynamicConfig is not collected as there is no TLS server")
+	return nil
+}
+
+// cacheSyncedChecker reports ready once the informers are synced. The standby replicas are also ready
+// as the cache is started regardless of the leader election, so they can serve the webhook.
+func cacheSyncedChecker(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncCheckTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("cache is not synced")
+		}
+		return nil
+	}
+}
+
+// lazyResourceManager delegates to the ResourceManager set after the manager is created
+type lazyResourceManager struct {
+	pkgcomponent.ResourceManager
+}
+
+// replicaName returns the name which identifies the current replica
+func replicaName() string {
+	if name := os.Getenv("POD_NAME"); name != "" {
+		return name
+	}
+	name, _ := os.Hostname()
+	return name
+}
+
+type nonLeaderRunnable struct {
+	run func(ctx context.Context) error
+}
+
+func (r *nonLeaderRunnable) Start(ctx context.Context) error {
+	return r.run(ctx)
+}
+
+func (r *nonLeaderRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+var _ manager.LeaderElectionRunnable = &nonLeaderRunnable{}
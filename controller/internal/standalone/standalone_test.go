@@ -0,0 +1,97 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standalone
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+func TestNewScheme(t *testing.T) {
+	scheme, err := NewScheme()
+	require.NoError(t, err)
+
+	for _, obj := range []runtime.Object{
+		&mosniov1.FilterPolicy{},
+		&mosniov1.Consumer{},
+		&mosniov1.ServiceRegistry{},
+		&mosniov1.DynamicConfig{},
+		&istiov1a3.EnvoyFilter{},
+		&istiov1a3.VirtualService{},
+		&gwapiv1.HTTPRoute{},
+		// for the leader election
+		&coordinationv1.Lease{},
+	} {
+		_, _, err := scheme.ObjectKinds(obj)
+		assert.NoError(t, err, "%T", obj)
+	}
+}
+
+func TestNewManagerInvalidOutput(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts Options
+		err  string
+	}{
+		{
+			name: "unknown output",
+			opts: Options{Output: "file"},
+			err:  `unknown output "file"`,
+		},
+		{
+			name: "xds with leader election",
+			opts: Options{Output: OutputXDS, LeaderElection: true},
+			err:  "leader election can't be used with the xds output",
+		},
+		{
+			name: "bad route config file",
+			opts: Options{Output: OutputXDS, XDSRouteConfigFile: "nonexistent.yaml"},
+			err:  "no such file",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewManager(&rest.Config{}, tc.opts)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestCacheSyncedChecker(t *testing.T) {
+	synced := false
+	c := &informertest.FakeInformers{Synced: &synced}
+	checker := cacheSyncedChecker(c)
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	assert.Error(t, checker(req))
+
+	synced = true
+	assert.NoError(t, checker(req))
+}
+
+func TestNonLeaderRunnable(t *testing.T) {
+	r := &nonLeaderRunnable{}
+	assert.False(t, r.NeedLeaderElection())
+}
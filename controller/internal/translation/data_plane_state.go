@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
@@ -60,7 +61,8 @@ func isWildCarded(s string) bool {
 	return len(s) > 0 && s[0] == '*'
 }
 
-func hostMatch(gwHost string, host string) bool {
+// HostMatch checks if the gateway host matches the route host. Both of them may be wildcarded.
+func HostMatch(gwHost string, host string) bool {
 	gwc := isWildCarded(gwHost)
 	hwc := isWildCarded(host)
 	if gwc {
@@ -86,7 +88,7 @@ func buildVirtualHostsWithIstioGw(host string, nsName *types.NamespacedName, gws
 		for _, svr := range gw.Spec.Servers {
 			port := svr.Port.Number
 			for _, h := range svr.Hosts {
-				if hostMatch(h, host) {
+				if HostMatch(h, host) {
 					name := net.JoinHostPort(host, fmt.Sprintf("%d", port))
 					vhs = append(vhs, &model.VirtualHost{
 						GatewaySection: &model.GatewaySection{
@@ -112,7 +114,7 @@ func buildVirtualHostsWithK8sGw(host string, ls *gwapiv1.Listener, nsName, gwNsN
 	if ls.Protocol != gwapiv1.HTTPProtocolType && ls.Protocol != gwapiv1.HTTPSProtocolType {
 		return vhs
 	}
-	if ls.Hostname == nil || hostMatch(string(*ls.Hostname), host) {
+	if ls.Hostname == nil || HostMatch(string(*ls.Hostname), host) {
 		if host == "*" && ls.Hostname != nil {
 			host = string(*ls.Hostname)
 		}
@@ -131,17 +133,42 @@ func buildVirtualHostsWithK8sGw(host string, ls *gwapiv1.Listener, nsName, gwNsN
 	return vhs
 }
 
-func AllowRoute(cond *gwapiv1.AllowedRoutes, route *gwapiv1b1.HTTPRoute, gwNsName *types.NamespacedName) bool {
+// ListenerAttachedByRoute checks if the listener is referred by one of the route's parentRefs
+// and allows the route to attach to it. The parentRefs are expected to point to the Gateway
+// which contains the listener.
+func ListenerAttachedByRoute(ls *gwapiv1.Listener, parentRefs []gwapiv1.ParentReference, route client.Object,
+	gwNsName *types.NamespacedName) bool {
+
+	matched := false
+	for _, ref := range parentRefs {
+		if ref.Port != nil && *ref.Port != ls.Port {
+			continue
+		}
+		if ref.SectionName != nil && *ref.SectionName != ls.Name {
+			continue
+		}
+		matched = true
+		break
+	}
+	if !matched {
+		return false
+	}
+
+	return AllowRoute(ls.AllowedRoutes, route, gwNsName)
+}
+
+func AllowRoute(cond *gwapiv1.AllowedRoutes, route client.Object, gwNsName *types.NamespacedName) bool {
 	if cond == nil {
 		return true
 	}
 
 	matched := len(cond.Kinds) == 0
 	for _, kind := range cond.Kinds {
-		if kind.Group != nil && string(*kind.Group) != route.GroupVersionKind().Group {
+		gvk := route.GetObjectKind().GroupVersionKind()
+		if kind.Group != nil && string(*kind.Group) != gvk.Group {
 			continue
 		}
-		if string(kind.Kind) != route.GroupVersionKind().Kind {
+		if string(kind.Kind) != gvk.Kind {
 			continue
 		}
 
@@ -157,7 +184,7 @@ func AllowRoute(cond *gwapiv1.AllowedRoutes, route *gwapiv1b1.HTTPRoute, gwNsNam
 		from := gwapiv1.NamespacesFromSelector
 		if nsCond.From != nil {
 			from = *nsCond.From
-			if from == gwapiv1.NamespacesFromSame && gwNsName.Namespace != route.Namespace {
+			if from == gwapiv1.NamespacesFromSame && gwNsName.Namespace != route.GetNamespace() {
 				return false
 			}
 		}
@@ -167,7 +194,7 @@ func AllowRoute(cond *gwapiv1.AllowedRoutes, route *gwapiv1b1.HTTPRoute, gwNsNam
 				log.Errorf("failed to convert selector, err: %v, selector: %v", err, nsCond.Selector)
 				return false
 			}
-			if !sel.Matches(labels.Set(route.Labels)) {
+			if !sel.Matches(labels.Set(route.GetLabels())) {
 				return false
 			}
 		}
@@ -208,6 +235,55 @@ func addVirtualHostToProxy(vh *model.VirtualHost, proxies map[Proxy]*proxyConfig
 	}
 }
 
+func addK8sRouteToProxy(proxies map[Proxy]*proxyConfig, id types.NamespacedName, route client.Object,
+	parentRefs []gwapiv1.ParentReference, hostnames []gwapiv1.Hostname,
+	routePolicies map[string][]*FilterPolicyWrapper, gws []*gwapiv1b1.Gateway) {
+
+	routeNsName := &types.NamespacedName{
+		Namespace: route.GetNamespace(),
+		Name:      route.GetName(),
+	}
+	routes := make(map[string]*routePolicy)
+	for name, policies := range routePolicies {
+		routes[name] = &routePolicy{
+			Policies: policies,
+			NsName:   &id,
+		}
+	}
+	if len(hostnames) == 0 {
+		// This is how Istio handles empty Hostnames
+		hostnames = wildcardHostnams
+	}
+	for _, gw := range gws {
+		gwNsName := &types.NamespacedName{
+			Namespace: gw.Namespace,
+			Name:      gw.Name,
+		}
+		for _, ls := range gw.Spec.Listeners {
+			if !ListenerAttachedByRoute(&ls, parentRefs, route, gwNsName) {
+				continue
+			}
+
+			for _, hostName := range hostnames {
+				vhs := buildVirtualHostsWithK8sGw(string(hostName), &ls, routeNsName, gwNsName)
+				if len(vhs) == 0 {
+					// It's acceptable to have an unmatched hostname, which is already
+					// reported in the route's status
+					continue
+				}
+				for _, vh := range vhs {
+					addVirtualHostToProxy(vh, proxies, routes)
+				}
+			}
+		}
+	}
+}
+
+// LDSName returns the name of the LDS which listens to the given bind address and port
+func LDSName(bind string, port uint32) string {
+	return getLDSName(bind, port)
+}
+
 func getLDSName(bind string, port uint32) string {
 	// We don't support unix socket. Is there someone using it on production?
 	if bind == "" {
@@ -293,61 +369,13 @@ func toDataPlaneState(ctx *Ctx, state *InitState) (*FinalState, error) {
 	}
 
 	for id, route := range state.HTTPRoutePolicies {
-		gws := route.Gateways
-		spec := &route.HTTPRoute.Spec
-		routeNsName := &types.NamespacedName{
-			Namespace: route.HTTPRoute.Namespace,
-			Name:      route.HTTPRoute.Name,
-		}
-		routes := make(map[string]*routePolicy)
-		for name, policies := range route.RoutePolicies {
-			routes[name] = &routePolicy{
-				Policies: policies,
-				NsName:   &id,
-			}
-		}
-		for _, gw := range gws {
-			gwNsName := &types.NamespacedName{
-				Namespace: gw.Namespace,
-				Name:      gw.Name,
-			}
-			for _, ls := range gw.Spec.Listeners {
-				matched := false
-				for _, ref := range route.HTTPRoute.Spec.ParentRefs {
-					if ref.Port != nil && *ref.Port != ls.Port {
-						continue
-					}
-					if ref.SectionName != nil && *ref.SectionName != ls.Name {
-						continue
-					}
-					matched = true
-				}
-				if !matched {
-					continue
-				}
-
-				if !AllowRoute(ls.AllowedRoutes, route.HTTPRoute, gwNsName) {
-					continue
-				}
+		addK8sRouteToProxy(s.Proxies, id, route.HTTPRoute, route.HTTPRoute.Spec.ParentRefs,
+			route.HTTPRoute.Spec.Hostnames, route.RoutePolicies, route.Gateways)
+	}
 
-				hostnames := spec.Hostnames
-				if len(hostnames) == 0 {
-					// This is how Istio handles empty Hostnames
-					hostnames = wildcardHostnams
-				}
-				for _, hostName := range hostnames {
-					vhs := buildVirtualHostsWithK8sGw(string(hostName), &ls, routeNsName, gwNsName)
-					if len(vhs) == 0 {
-						// It's acceptable to have an unmatched hostname, which is already
-						// reported in the HTTPRoute's status
-						continue
-					}
-					for _, vh := range vhs {
-						addVirtualHostToProxy(vh, s.Proxies, routes)
-					}
-				}
-			}
-		}
+	for id, route := range state.GRPCRoutePolicies {
+		addK8sRouteToProxy(s.Proxies, id, route.GRPCRoute, route.GRPCRoute.Spec.ParentRefs,
+			route.GRPCRoute.Spec.Hostnames, route.RoutePolicies, route.Gateways)
 	}
 
 	for gs, gwp := range state.GatewayPolicies {
@@ -28,13 +28,13 @@ func TestHostMatch(t *testing.T) {
 	mismatched := []string{"a.test.com", "*.t.com"}
 
 	for _, m := range matched {
-		if !hostMatch(m, "v.test.com") {
-			t.Errorf("hostMatch(%s, v.test.com) should be true", m)
+		if !HostMatch(m, "v.test.com") {
+			t.Errorf("HostMatch(%s, v.test.com) should be true", m)
 		}
 	}
 	for _, m := range mismatched {
-		if hostMatch(m, "v.test.com") {
-			t.Errorf("hostMatch(%s, v.test.com) should be false", m)
+		if HostMatch(m, "v.test.com") {
+			t.Errorf("HostMatch(%s, v.test.com) should be false", m)
 		}
 	}
 
@@ -43,21 +43,21 @@ func TestHostMatch(t *testing.T) {
 	mismatched = []string{"*.t.com", "test.com"}
 
 	for _, m := range matched {
-		if !hostMatch(m, "*.test.com") {
-			t.Errorf("hostMatch(%s, *.test.com) should be true", m)
+		if !HostMatch(m, "*.test.com") {
+			t.Errorf("HostMatch(%s, *.test.com) should be true", m)
 		}
 	}
 	for _, m := range mismatched {
-		if hostMatch(m, "*.test.com") {
-			t.Errorf("hostMatch(%s, *.test.com) should be false", m)
+		if HostMatch(m, "*.test.com") {
+			t.Errorf("HostMatch(%s, *.test.com) should be false", m)
 		}
 	}
 
 	matched = []string{"*", "*.com", "a.com"}
 
 	for _, m := range matched {
-		if !hostMatch(m, "*") {
-			t.Errorf("hostMatch(%s, *) should be true", m)
+		if !HostMatch(m, "*") {
+			t.Errorf("HostMatch(%s, *) should be true", m)
 		}
 	}
 }
@@ -15,29 +15,45 @@
 package translation
 
 import (
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"net"
+	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"golang.org/x/net/idna"
+	istioapi "istio.io/api/networking/v1alpha3"
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 
+	fmModel "mosn.io/htnn/api/pkg/filtermanager/model"
+	"mosn.io/htnn/controller/internal/config"
 	"mosn.io/htnn/controller/internal/istio"
+	"mosn.io/htnn/controller/internal/log"
 	"mosn.io/htnn/controller/internal/model"
 	"mosn.io/htnn/controller/pkg/component"
 	"mosn.io/htnn/controller/pkg/constant"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
 )
 
 const (
 	AnnotationInfo = "htnn.mosn.io/info"
 
 	DefaultEnvoyFilterPriority = -10
+
+	// the policies are warned once the generated configuration of a route is over this percent of the limit
+	sizeLimitWarningPercent = 80
+	// the max number of the shards of a route EnvoyFilter, in case the routes can't be separated by hash
+	maxRouteEnvoyFilterShards = 1 << 12
 )
 
 var (
 	validEnvoyFilterName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// the route EnvoyFilter and its shards, like htnn-h-example.com and htnn-h1-example.com
+	routeEnvoyFilterName = regexp.MustCompile(`^htnn-h[0-9]*-`)
 )
 
 // We use the domain as the EnvoyFilter's name, so that:
@@ -45,7 +61,7 @@ var (
 // 2. Match the EnvoyFilter model which uses domain + routeName as the key.
 // 3. Allow merging the same route configuration into virtual host level.
 // There are also some drawbacks. For example, a domain shared by hundreds of VirtualServices will
-// cause one big EnvoyFilter. Let's see if it's a problem.
+// cause one big EnvoyFilter. So the big EnvoyFilter is split into shards, see shardRouteEnvoyFilters.
 func envoyFilterNameFromVirtualHost(vhost *model.VirtualHost) string {
 	// Strip the port number. We don't need to create two EnvoyFilters for :80 and :443.
 	domain, port, _ := net.SplitHostPort(vhost.Name)
@@ -81,14 +97,181 @@ func envoyFilterNameFromLds(ldsName string) string {
 	return fmt.Sprintf("htnn-lds-%s", ldsName)
 }
 
+// mergeHTTPFilterConfigs merges the HTTP filter configurations extracted from the route into the given
+// configs. The top-level fields of map type are merged. It returns an error if the same field, or the
+// same key of the merged fields, is configured with different values.
+func mergeHTTPFilterConfigs(configs map[string]map[string]interface{}, routeConfig map[string]interface{}) error {
+	filters, _ := routeConfig[model.CategoryHTTPFilter].(map[string]*fmModel.FilterConfig)
+	for name, filter := range filters {
+		src, _ := filter.Config.(map[string]interface{})
+		dst, ok := configs[name]
+		if !ok {
+			dst = map[string]interface{}{}
+			configs[name] = dst
+		}
+		for k, v := range src {
+			srcField, ok := v.(map[string]interface{})
+			if !ok {
+				if curr, ok := dst[k]; ok && !reflect.DeepEqual(curr, v) {
+					return fmt.Errorf("field %s of HTTP filter %s is configured with different values", k, name)
+				}
+				dst[k] = v
+				continue
+			}
+			dstField, ok := dst[k].(map[string]interface{})
+			if !ok {
+				if _, exists := dst[k]; exists {
+					return fmt.Errorf("field %s of HTTP filter %s is configured with different types", k, name)
+				}
+				dstField = map[string]interface{}{}
+				dst[k] = dstField
+			}
+			for fk, fv := range srcField {
+				if curr, ok := dstField[fk]; ok && !reflect.DeepEqual(curr, fv) {
+					return fmt.Errorf("key %s of field %s of HTTP filter %s is configured with different values", fk, k, name)
+				}
+				dstField[fk] = fv
+			}
+		}
+	}
+	return nil
+}
+
 // finalState is the end of the translation. We convert the state to EnvoyFilter and write it to k8s.
 type FinalState struct {
 	EnvoyFilters map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter
+	// PolicyAttachments are the sorted attachments of each policy, keyed by {namespace}/{name}
+	PolicyAttachments map[string][]mosniov1.FilterPolicyAttachment
+	// RejectedPolicies are the policies excluded from some routes, as the generated configuration
+	// exceeds the size limit with them, keyed by {namespace}/{name}. The value is the reason.
+	RejectedPolicies map[string]string
+	// PoliciesNearSizeLimit are the policies attached to the routes whose generated configuration
+	// is close to the size limit, keyed by {namespace}/{name}. The value is the reason.
+	PoliciesNearSizeLimit map[string]string
 }
 
 type envoyFilterWrapper struct {
 	*istiov1a3.EnvoyFilter
 	info *Info
+	// the size of the ConfigPatches in JSON
+	size int
+}
+
+// configPatchesSize returns the size of the EnvoyFilter's ConfigPatches in JSON, which is how
+// it is stored in etcd.
+func configPatchesSize(ef *istiov1a3.EnvoyFilter) int {
+	size := 0
+	for _, cp := range ef.Spec.ConfigPatches {
+		b, _ := json.Marshal(cp)
+		size += len(b)
+	}
+	return size
+}
+
+// addPolicyMessage records the message for each policy in the info. When a policy has multiple messages,
+// the smallest one is kept so that the result is stable.
+func addPolicyMessage(messages map[string]string, info *Info, msg string) {
+	if info == nil {
+		return
+	}
+	for _, policy := range info.FilterPolicies {
+		if curr, ok := messages[policy]; !ok || msg < curr {
+			messages[policy] = msg
+		}
+	}
+}
+
+// shardRouteEnvoyFilters merges the route EnvoyFilters of the same domain. If they don't fit into one
+// EnvoyFilter, each route is put into one of the N shards by the hash of its name, where N is the smallest
+// power of two which makes every shard fit. So adding or removing a route doesn't move the other routes,
+// unless N is changed. The shard 0 uses the original name, and the shard i is named with the prefix
+// htnn-h$i-, like htnn-h1-example.com. As the index is put into the prefix, the name of a shard can't
+// be the same as the one of another domain.
+func shardRouteEnvoyFilters(key component.EnvoyFilterKey, efs []*envoyFilterWrapper) []*envoyFilterWrapper {
+	sort.Slice(efs, func(i, j int) bool {
+		return lessRoutePatch(efs[i].Spec.ConfigPatches[0], efs[j].Spec.ConfigPatches[0])
+	})
+
+	shardSize := config.EnvoyFilterShardSize()
+	maxRoutes := config.EnvoyFilterShardMaxRoutes()
+	fit := func(bucket []*envoyFilterWrapper) bool {
+		// a route larger than the shard size has its own shard
+		if len(bucket) <= 1 {
+			return true
+		}
+		if maxRoutes > 0 && len(bucket) > maxRoutes {
+			return false
+		}
+		size := 0
+		for _, ef := range bucket {
+			size += ef.size
+		}
+		return shardSize <= 0 || size <= shardSize
+	}
+
+	var buckets [][]*envoyFilterWrapper
+	for n := 1; ; n *= 2 {
+		buckets = make([][]*envoyFilterWrapper, n)
+		for _, ef := range efs {
+			idx := routeShardHash(ef.Spec.ConfigPatches[0]) & uint64(n-1)
+			buckets[idx] = append(buckets[idx], ef)
+		}
+		if n >= maxRouteEnvoyFilterShards || !slices.ContainsFunc(buckets, func(b []*envoyFilterWrapper) bool {
+			return !fit(b)
+		}) {
+			break
+		}
+	}
+
+	shards := []*envoyFilterWrapper{}
+	for i, bucket := range buckets {
+		if len(bucket) == 0 {
+			continue
+		}
+
+		shard := bucket[0]
+		if shard.info != nil {
+			// the Info may be shared by the routes in different shards, so copy it before merging
+			shard.info = &Info{FilterPolicies: slices.Clone(shard.info.FilterPolicies)}
+		}
+		for _, ef := range bucket[1:] {
+			shard.Spec.ConfigPatches = append(shard.Spec.ConfigPatches, ef.Spec.ConfigPatches...)
+			shard.size += ef.size
+			if ef.info != nil {
+				if shard.info == nil {
+					shard.info = &Info{}
+				}
+				shard.info.Merge(ef.info)
+			}
+		}
+		if i > 0 {
+			shard.SetName(fmt.Sprintf("htnn-h%d-%s", i, strings.TrimPrefix(key.Name, "htnn-h-")))
+		}
+		shards = append(shards, shard)
+	}
+	if len(shards) > 1 {
+		log.Infof("route EnvoyFilter %s/%s is split into %d shards", key.Namespace, key.Name, len(shards))
+	}
+	return shards
+}
+
+// routeShardHash returns the stable hash of the route to choose its shard
+func routeShardHash(patch *istioapi.EnvoyFilter_EnvoyConfigObjectPatch) uint64 {
+	vhost := patch.Match.GetRouteConfiguration().GetVhost()
+	h := fnv.New64a()
+	h.Write([]byte(vhost.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(vhost.GetRoute().Name))
+	return h.Sum64()
+}
+
+func lessRoutePatch(a, b *istioapi.EnvoyFilter_EnvoyConfigObjectPatch) bool {
+	aVhost := a.Match.GetRouteConfiguration().GetVhost()
+	bVhost := b.Match.GetRouteConfiguration().GetVhost()
+	if aVhost.Name != bVhost.Name {
+		return aVhost.Name < bVhost.Name
+	}
+	return aVhost.GetRoute().Name < bVhost.GetRoute().Name
 }
 
 func toFinalState(_ *Ctx, state *mergedState) (*FinalState, error) {
@@ -97,25 +280,49 @@ func toFinalState(_ *Ctx, state *mergedState) (*FinalState, error) {
 		ef.Spec.Priority = DefaultEnvoyFilterPriority
 	}
 	efList := []*envoyFilterWrapper{}
+	// namespace -> filter name -> config
+	httpFilterConfigs := map[string]map[string]map[string]interface{}{}
+	// The route EnvoyFilters are merged into shards by size, so we keep them separately
+	routeEnvoyFilters := map[component.EnvoyFilterKey][]*envoyFilterWrapper{}
+	policiesNearSizeLimit := map[string]string{}
+	maxRouteSize := config.EnvoyFilterMaxRouteSize()
 
 	for proxy, cfg := range state.Proxies {
 		hostRules := cfg.Hosts
 		for _, host := range hostRules {
 			for routeName, route := range host.Routes {
 				ef := istio.GenerateRouteFilter(host.VirtualHost, routeName, route.Config)
+				size := configPatchesSize(ef)
+				if maxRouteSize > 0 && size > maxRouteSize*sizeLimitWarningPercent/100 {
+					msg := fmt.Sprintf("the configuration generated for route %s of virtual host %s is %d bytes, close to the limit %d bytes",
+						routeName, host.VirtualHost.Name, size, maxRouteSize)
+					addPolicyMessage(policiesNearSizeLimit, route.Info, msg)
+				}
+
 				// Set the EnvoyFilter's namespace to the workload's namespace.
 				// For k8s Gateway API, the workload's namespace is equal to the Gateway's namespace.
 				// For Istio API, we will require env var PILOT_SCOPE_GATEWAY_TO_NAMESPACE to be set.
 				// If PILOT_SCOPE_GATEWAY_TO_NAMESPACE is not set, people need to follow the convention
 				// that the namespace of workload matches the namespace of gateway.
 				ns := proxy.Namespace
+				if httpFilterConfigs[ns] == nil {
+					httpFilterConfigs[ns] = map[string]map[string]interface{}{}
+				}
+				if err := mergeHTTPFilterConfigs(httpFilterConfigs[ns], route.Config); err != nil {
+					// Don't write the configuration which misses some HTTP filter configurations.
+					// The last written configuration is kept.
+					return nil, fmt.Errorf("failed to merge the HTTP filter configurations of route %s of virtual host %s: %w",
+						routeName, host.VirtualHost.Name, err)
+				}
 				ef.SetNamespace(ns)
 				name := envoyFilterNameFromVirtualHost(host.VirtualHost)
 				ef.SetName(name)
 
-				efList = append(efList, &envoyFilterWrapper{
+				key := component.EnvoyFilterKey{Namespace: ns, Name: name}
+				routeEnvoyFilters[key] = append(routeEnvoyFilters[key], &envoyFilterWrapper{
 					EnvoyFilter: ef,
 					info:        route.Info,
+					size:        size,
 				})
 			}
 		}
@@ -132,6 +339,10 @@ func toFinalState(_ *Ctx, state *mergedState) (*FinalState, error) {
 			}
 
 			ef := istio.GenerateLDSFilter(key, name, gateway.Gateway.HasHCM, config)
+			if len(ef.Spec.ConfigPatches) == 0 {
+				// like the L4 listener without policy
+				continue
+			}
 			ef.SetNamespace(ns)
 			// Put all LDS level filters of the same LDS into the same EnvoyFilter.
 			efName := envoyFilterNameFromLds(name)
@@ -145,8 +356,22 @@ func toFinalState(_ *Ctx, state *mergedState) (*FinalState, error) {
 		}
 	}
 
+	for ns, configs := range httpFilterConfigs {
+		if len(configs) == 0 {
+			continue
+		}
+		// The HTTP filter configurations are shared by the proxies in the same namespace, like the routes
+		efList = append(efList, &envoyFilterWrapper{
+			EnvoyFilter: istio.GenerateHTTPFilterConfigs(ns, configs),
+		})
+	}
+
+	for key, routeEfs := range routeEnvoyFilters {
+		efList = append(efList, shardRouteEnvoyFilters(key, routeEfs)...)
+	}
+
 	// Merge EnvoyFilters with same name. The number of EnvoyFilters is equal to the number of
-	// configured domains and lds.
+	// configured domains and lds, unless the route EnvoyFilters of a domain are sharded.
 	efws := map[component.EnvoyFilterKey]*envoyFilterWrapper{}
 	for _, ef := range efList {
 		key := component.EnvoyFilterKey{
@@ -178,17 +403,10 @@ func toFinalState(_ *Ctx, state *mergedState) (*FinalState, error) {
 		}
 		ef.Labels[constant.LabelCreatedBy] = "FilterPolicy"
 
-		if strings.HasPrefix(ef.Name, "htnn-h-") {
+		if routeEnvoyFilterName.MatchString(ef.Name) {
 			// Sort here to avoid EnvoyFilter change caused by the order of ConfigPatch.
 			sort.Slice(ef.Spec.ConfigPatches, func(i, j int) bool {
-				a := ef.Spec.ConfigPatches[i]
-				b := ef.Spec.ConfigPatches[j]
-				aVhost := a.Match.GetRouteConfiguration().GetVhost()
-				bVhost := b.Match.GetRouteConfiguration().GetVhost()
-				if aVhost.Name != bVhost.Name {
-					return aVhost.Name < bVhost.Name
-				}
-				return aVhost.GetRoute().Name < bVhost.GetRoute().Name
+				return lessRoutePatch(ef.Spec.ConfigPatches[i], ef.Spec.ConfigPatches[j])
 			})
 		}
 		// For EnvoyFilter to LDS, we need to keep the original filter order
@@ -196,7 +414,27 @@ func toFinalState(_ *Ctx, state *mergedState) (*FinalState, error) {
 		efs[key] = ef.EnvoyFilter
 	}
 
+	for _, attachments := range state.Attachments {
+		sort.Slice(attachments, func(i, j int) bool {
+			a := attachments[i]
+			b := attachments[j]
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+			if a.Kind != b.Kind {
+				return a.Kind < b.Kind
+			}
+			if a.VirtualHost != b.VirtualHost {
+				return a.VirtualHost < b.VirtualHost
+			}
+			return a.Name < b.Name
+		})
+	}
+
 	return &FinalState{
-		EnvoyFilters: efs,
+		EnvoyFilters:          efs,
+		PolicyAttachments:     state.Attachments,
+		RejectedPolicies:      state.RejectedPolicies,
+		PoliciesNearSizeLimit: policiesNearSizeLimit,
 	}, nil
 }
@@ -15,9 +15,25 @@
 package translation
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	fmModel "mosn.io/htnn/api/pkg/filtermanager/model"
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/istio"
+	"mosn.io/htnn/controller/internal/model"
+	"mosn.io/htnn/controller/pkg/component"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
 )
 
 func TestEnvoyFilterNameFromLds(t *testing.T) {
@@ -32,3 +48,254 @@ func TestEnvoyFilterNameFromLds(t *testing.T) {
 		assert.True(t, validEnvoyFilterName.MatchString(out))
 	}
 }
+
+func TestMergeHTTPFilterConfigs(t *testing.T) {
+	routeConfig := func(provider string) map[string]interface{} {
+		return map[string]interface{}{
+			model.CategoryHTTPFilter: map[string]*fmModel.FilterConfig{
+				"htnn.filters.http.jwtAuthn": {
+					Config: map[string]interface{}{
+						"@type": "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication",
+						"providers": map[string]interface{}{
+							provider: map[string]interface{}{},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	configs := map[string]map[string]interface{}{}
+	require.NoError(t, mergeHTTPFilterConfigs(configs, routeConfig("a")))
+	require.NoError(t, mergeHTTPFilterConfigs(configs, routeConfig("b")))
+	require.NoError(t, mergeHTTPFilterConfigs(configs, routeConfig("a")))
+	require.NoError(t, mergeHTTPFilterConfigs(configs, map[string]interface{}{}))
+	assert.Equal(t, map[string]map[string]interface{}{
+		"htnn.filters.http.jwtAuthn": {
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication",
+			"providers": map[string]interface{}{
+				"a": map[string]interface{}{},
+				"b": map[string]interface{}{},
+			},
+		},
+	}, configs)
+	conflicted := routeConfig("a")
+	filter := conflicted[model.CategoryHTTPFilter].(map[string]*fmModel.FilterConfig)["htnn.filters.http.jwtAuthn"]
+	filter.Config.(map[string]interface{})["providers"].(map[string]interface{})["a"] = map[string]interface{}{"issuer": "x"}
+	assert.ErrorContains(t, mergeHTTPFilterConfigs(configs, conflicted), "key a of field providers")
+	filter.Config = map[string]interface{}{"@type": "type.googleapis.com/other"}
+	assert.ErrorContains(t, mergeHTTPFilterConfigs(configs, conflicted), "field @type of HTTP filter htnn.filters.http.jwtAuthn is configured with different values")
+	filter.Config = map[string]interface{}{"providers": "x"}
+	assert.ErrorContains(t, mergeHTTPFilterConfigs(configs, conflicted), "field providers of HTTP filter htnn.filters.http.jwtAuthn is configured with different values")
+}
+
+// attachedPolicies returns the sorted policies attached to the route
+func attachedPolicies(fs *FinalState, route string) []string {
+	var policies []string
+	for policy, attachments := range fs.PolicyAttachments {
+		for _, attachment := range attachments {
+			if attachment.Kind == mosniov1.FilterPolicyAttachmentKindRoute && attachment.Name == route {
+				policies = append(policies, policy)
+			}
+		}
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+func TestRouteSizeLimit(t *testing.T) {
+	var gw istiov1a3.Gateway
+	require.NoError(t, yaml.Unmarshal([]byte(`
+metadata:
+  name: gateway
+  namespace: default
+spec:
+  servers:
+  - hosts:
+    - example.com
+    port:
+      name: http
+      number: 80
+      protocol: HTTP
+`), &gw))
+	var vs istiov1a3.VirtualService
+	require.NoError(t, yaml.Unmarshal([]byte(`
+metadata:
+  name: vs
+  namespace: default
+spec:
+  gateways:
+  - gateway
+  hosts:
+  - example.com
+  http:
+  - name: small
+    route:
+    - destination:
+        host: backend
+  - name: large
+    route:
+    - destination:
+        host: backend
+`), &vs))
+	policy := func(name string, section string, plugin string, pet string) *mosniov1.FilterPolicy {
+		sectionName := ""
+		if section != "" {
+			sectionName = "\n    sectionName: " + section
+		}
+		var p mosniov1.FilterPolicy
+		require.NoError(t, yaml.Unmarshal([]byte(`
+metadata:
+  name: `+name+`
+  namespace: default
+spec:
+  targetRef:
+    group: networking.istio.io
+    kind: VirtualService
+    name: vs`+sectionName+`
+  filters:
+    `+plugin+`:
+      config:
+        pet: `+pet+`
+`), &p))
+		return &p
+	}
+
+	process := func(limit int) (*FinalState, error) {
+		os.Setenv("HTNN_ENVOYFILTER_MAX_ROUTE_SIZE", strconv.Itoa(limit))
+		config.Init()
+		defer func() {
+			os.Setenv("HTNN_ENVOYFILTER_MAX_ROUTE_SIZE", strconv.Itoa(256*1024))
+			config.Init()
+		}()
+
+		s := NewInitState()
+		gws := []*istiov1a3.Gateway{&gw}
+		s.AddPolicyForVirtualService(policy("small", "small", "animal", "cat"), &vs, gws)
+		s.AddPolicyForVirtualService(policy("large", "large", "animal", strings.Repeat("x", 2048)), &vs, gws)
+		s.AddPolicyForVirtualService(policy("wide", "", "localReply", "dog"), &vs, gws)
+		return s.Process(context.Background())
+	}
+
+	fs, err := process(1024)
+	require.NoError(t, err)
+	// only the policy pushing the route over the limit is rejected, and only from that route
+	assert.Len(t, fs.RejectedPolicies, 1)
+	assert.Contains(t, fs.RejectedPolicies["default/large"], "route large of virtual host example.com:80")
+	assert.Empty(t, fs.PoliciesNearSizeLimit)
+	ef := fs.EnvoyFilters[component.EnvoyFilterKey{Namespace: "default", Name: "htnn-h-example.com"}]
+	require.NotNil(t, ef)
+	// the oversized route is still generated
+	require.Len(t, ef.Spec.ConfigPatches, 2)
+	assert.Equal(t, "large", ef.Spec.ConfigPatches[0].Match.GetRouteConfiguration().GetVhost().GetRoute().GetName())
+	assert.Equal(t, []string{"default/wide"}, attachedPolicies(fs, "large"))
+	assert.Equal(t, []string{"default/small", "default/wide"}, attachedPolicies(fs, "small"))
+
+	// the route can't be generated even without any policy, so the translation fails
+	_, err = process(16)
+	require.Error(t, err)
+
+	fs, err = process(2560)
+	require.NoError(t, err)
+	assert.Empty(t, fs.RejectedPolicies)
+	assert.Contains(t, fs.PoliciesNearSizeLimit["default/large"], "close to the limit 2560 bytes")
+	assert.NotContains(t, fs.PoliciesNearSizeLimit, "default/small")
+
+	fs, err = process(0)
+	require.NoError(t, err)
+	assert.Empty(t, fs.RejectedPolicies)
+	assert.Empty(t, fs.PoliciesNearSizeLimit)
+}
+
+func TestShardRouteEnvoyFilters(t *testing.T) {
+	os.Setenv("HTNN_ENVOYFILTER_SHARD_SIZE", "100")
+	config.Init()
+	defer func() {
+		os.Setenv("HTNN_ENVOYFILTER_SHARD_SIZE", strconv.Itoa(512*1024))
+		config.Init()
+	}()
+
+	routeConfig := map[string]interface{}{
+		model.CategoryRouteFilter: map[string]*fmModel.FilterConfig{},
+		model.CategoryRoute:       map[string]*fmModel.FilterConfig{},
+	}
+	host := &model.VirtualHost{Name: "example.com:80"}
+	key := component.EnvoyFilterKey{Namespace: "default", Name: "htnn-h-example.com"}
+	wrapper := func(route string, size int, policy string) *envoyFilterWrapper {
+		ef := istio.GenerateRouteFilter(host, route, routeConfig)
+		ef.SetNamespace(key.Namespace)
+		ef.SetName(key.Name)
+		return &envoyFilterWrapper{
+			EnvoyFilter: ef,
+			info:        &Info{FilterPolicies: []string{policy}},
+			size:        size,
+		}
+	}
+
+	routes := func(ef *envoyFilterWrapper) []string {
+		names := []string{}
+		for _, cp := range ef.Spec.ConfigPatches {
+			names = append(names, cp.Match.GetRouteConfiguration().GetVhost().GetRoute().GetName())
+		}
+		return names
+	}
+	shardOf := func(shards []*envoyFilterWrapper) map[string]string {
+		res := map[string]string{}
+		for _, shard := range shards {
+			for _, route := range routes(shard) {
+				res[route] = shard.Name
+			}
+		}
+		return res
+	}
+
+	// fit into one EnvoyFilter
+	shards := shardRouteEnvoyFilters(key, []*envoyFilterWrapper{
+		wrapper("b", 30, "default/b"),
+		wrapper("a", 60, "default/a"),
+	})
+	require.Len(t, shards, 1)
+	assert.Equal(t, "htnn-h-example.com", shards[0].Name)
+	assert.Equal(t, []string{"a", "b"}, routes(shards[0]))
+	assert.Equal(t, []string{"default/a", "default/b"}, shards[0].info.FilterPolicies)
+
+	input := []*envoyFilterWrapper{}
+	for i := 0; i < 20; i++ {
+		input = append(input, wrapper(fmt.Sprintf("r%d", i), 30, fmt.Sprintf("default/p%d", i%2)))
+	}
+	// a route larger than the shard size has its own shard
+	input = append(input, wrapper("large", 150, "default/large"))
+	shards = shardRouteEnvoyFilters(key, input)
+	require.Greater(t, len(shards), 1)
+	total := 0
+	for _, shard := range shards {
+		assert.True(t, routeEnvoyFilterName.MatchString(shard.Name), shard.Name)
+		assert.True(t, validEnvoyFilterName.MatchString(shard.Name), shard.Name)
+		if len(shard.Spec.ConfigPatches) > 1 {
+			assert.LessOrEqual(t, shard.size, 100)
+		}
+		total += len(shard.Spec.ConfigPatches)
+	}
+	assert.Equal(t, 21, total)
+	assigned := shardOf(shards)
+	for _, shard := range shards {
+		if shard.Name != key.Name {
+			// the shard name can't collide with the one of another domain, like example.com-1
+			assert.Regexp(t, `^htnn-h[0-9]+-example\.com$`, shard.Name)
+		}
+	}
+
+	// adding a route doesn't move the others if the number of shards is unchanged
+	input = []*envoyFilterWrapper{}
+	for i := 0; i < 20; i++ {
+		input = append(input, wrapper(fmt.Sprintf("r%d", i), 30, fmt.Sprintf("default/p%d", i%2)))
+	}
+	input = append(input, wrapper("large", 150, "default/large"), wrapper("new", 1, "default/new"))
+	shards2 := shardRouteEnvoyFilters(key, input)
+	require.Equal(t, len(shards), len(shards2))
+	for route, shard := range shardOf(shards2) {
+		if route != "new" {
+			assert.Equal(t, assigned[route], shard, route)
+		}
+	}
+}
@@ -21,6 +21,8 @@ import (
 
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
@@ -41,6 +43,12 @@ type HTTPRoutePolicies struct {
 	Gateways      []*gwapiv1b1.Gateway
 }
 
+type GRPCRoutePolicies struct {
+	GRPCRoute     *gwapiv1a2.GRPCRoute
+	RoutePolicies map[string][]*FilterPolicyWrapper
+	Gateways      []*gwapiv1b1.Gateway
+}
+
 type ServerPort struct {
 	Bind     string
 	Number   uint32
@@ -61,6 +69,7 @@ type ServerPortKey struct {
 type InitState struct {
 	VirtualServicePolicies map[types.NamespacedName]*VirtualServicePolicies
 	HTTPRoutePolicies      map[types.NamespacedName]*HTTPRoutePolicies
+	GRPCRoutePolicies      map[types.NamespacedName]*GRPCRoutePolicies
 
 	GatewayPolicies            map[model.GatewaySection]*GatewayPolicies
 	GatewayWithoutPolicies     map[model.GatewaySection]*ServerPort
@@ -71,6 +80,7 @@ func NewInitState() *InitState {
 	return &InitState{
 		VirtualServicePolicies: make(map[types.NamespacedName]*VirtualServicePolicies),
 		HTTPRoutePolicies:      make(map[types.NamespacedName]*HTTPRoutePolicies),
+		GRPCRoutePolicies:      make(map[types.NamespacedName]*GRPCRoutePolicies),
 
 		GatewayPolicies:            make(map[model.GatewaySection]*GatewayPolicies),
 		GatewayWithoutPolicies:     make(map[model.GatewaySection]*ServerPort),
@@ -188,6 +198,87 @@ func (s *InitState) AddPolicyForHTTPRoute(policy *mosniov1.FilterPolicy, route *
 	}
 }
 
+func (s *InitState) GetGatewaysWithGRPCRoute(route *gwapiv1a2.GRPCRoute) []*gwapiv1b1.Gateway {
+	nn := types.NamespacedName{
+		Namespace: route.Namespace,
+		Name:      route.Name,
+	}
+
+	gp, ok := s.GRPCRoutePolicies[nn]
+	if !ok {
+		return nil
+	}
+
+	return gp.Gateways
+}
+
+func (s *InitState) AddPolicyForGRPCRoute(policy *mosniov1.FilterPolicy, route *gwapiv1a2.GRPCRoute, gws []*gwapiv1b1.Gateway) {
+	nn := types.NamespacedName{
+		Namespace: route.Namespace,
+		Name:      route.Name,
+	}
+
+	gp, ok := s.GRPCRoutePolicies[nn]
+	if !ok {
+		gp = &GRPCRoutePolicies{
+			GRPCRoute:     route,
+			RoutePolicies: map[string][]*FilterPolicyWrapper{},
+			Gateways:      gws,
+		}
+		s.GRPCRoutePolicies[nn] = gp
+	}
+
+	// Istio uses the same naming convention for GRPCRoute's rules as HTTPRoute's
+	for i := range route.Spec.Rules {
+		name := fmt.Sprintf("%s.%s.%d", route.Namespace, route.Name, i)
+		gp.RoutePolicies[name] = append(gp.RoutePolicies[name], &FilterPolicyWrapper{
+			FilterPolicy: policy,
+			scope:        PolicyScopeRoute,
+		})
+	}
+}
+
+// AddPolicyForTCPRoute adds the policy to the TCP listeners which the TCPRoute is attached to.
+// As there is no HTTP route in TCP listener, only the listener level plugins take effect.
+func (s *InitState) AddPolicyForTCPRoute(policy *mosniov1.FilterPolicy, route *gwapiv1a2.TCPRoute, gws []*gwapiv1b1.Gateway) {
+	s.addPolicyForL4Route(policy, route, route.Spec.ParentRefs, gws, gwapiv1.TCPProtocolType)
+}
+
+// AddPolicyForTLSRoute adds the policy to the TLS listeners which the TLSRoute is attached to.
+// As there is no HTTP route in TLS passthrough listener, only the listener level plugins take effect.
+func (s *InitState) AddPolicyForTLSRoute(policy *mosniov1.FilterPolicy, route *gwapiv1a2.TLSRoute, gws []*gwapiv1b1.Gateway) {
+	s.addPolicyForL4Route(policy, route, route.Spec.ParentRefs, gws, gwapiv1.TLSProtocolType)
+}
+
+func (s *InitState) addPolicyForL4Route(policy *mosniov1.FilterPolicy, route client.Object,
+	parentRefs []gwapiv1.ParentReference, gws []*gwapiv1b1.Gateway, protocol gwapiv1.ProtocolType) {
+
+	for _, gw := range gws {
+		nn := types.NamespacedName{
+			Namespace: gw.Namespace,
+			Name:      gw.Name,
+		}
+		for _, ls := range gw.Spec.Listeners {
+			if ls.Protocol != protocol {
+				continue
+			}
+			if !ListenerAttachedByRoute(&ls, parentRefs, route, &nn) {
+				continue
+			}
+
+			port := ServerPort{
+				Number:   uint32(ls.Port),
+				Protocol: mosniov1.NormalizeK8sGatewayProtocol(ls.Protocol),
+			}
+			gs := model.GatewaySection{
+				NsName:      nn,
+				SectionName: string(ls.Name),
+			}
+			s.addPolicyForGateway(policy, gs, port, PolicyScopeRoute)
+		}
+	}
+}
+
 func (s *InitState) AddIstioGateway(gw *istiov1a3.Gateway) {
 	s.AddPolicyForIstioGateway(nil, gw)
 }
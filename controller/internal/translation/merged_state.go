@@ -27,6 +27,8 @@ import (
 	fmModel "mosn.io/htnn/api/pkg/filtermanager/model"
 	"mosn.io/htnn/api/pkg/plugins"
 	ctrlcfg "mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/istio"
+	"mosn.io/htnn/controller/internal/log"
 	"mosn.io/htnn/controller/internal/model"
 	mosniov1 "mosn.io/htnn/types/apis/v1"
 )
@@ -37,6 +39,11 @@ import (
 // 3. transform a plugin to different plugins if needed
 type mergedState struct {
 	Proxies map[Proxy]*mergedProxyConfig
+	// Attachments records where each policy is resolved to, keyed by {namespace}/{name}
+	Attachments map[string][]mosniov1.FilterPolicyAttachment
+	// RejectedPolicies records the policies excluded from the routes because of the size limit,
+	// keyed by {namespace}/{name}. The value is the reason.
+	RejectedPolicies map[string]string
 }
 
 type mergedProxyConfig struct {
@@ -58,6 +65,15 @@ type mergedPolicy struct {
 	Config map[string]interface{}
 	Info   *Info
 	NsName *types.NamespacedName
+	// Plugins records how the plugins of each policy are merged, keyed by {namespace}/{name}
+	Plugins map[string]*attachedPlugins
+	// Overrides records the plugins coming from the overrides, and the policy which configures them
+	Overrides map[string]string
+}
+
+type attachedPlugins struct {
+	Plugins  []string
+	Shadowed []mosniov1.FilterPolicyShadowedPlugin
 }
 
 func toNsName(policy *FilterPolicyWrapper) string {
@@ -72,16 +88,31 @@ func toNsName(policy *FilterPolicyWrapper) string {
 func sortFilterPolicy(policies []*FilterPolicyWrapper) {
 	// use Slice instead of SliceStable because each policy has unique namespace/name
 	sort.Slice(policies, func(i, j int) bool {
-		if policies[i].scope != policies[j].scope {
-			return policies[i].scope < policies[j].scope
-		}
-		if policies[i].CreationTimestamp != policies[j].CreationTimestamp {
-			return policies[i].CreationTimestamp.Before(&policies[j].CreationTimestamp)
-		}
-		return toNsName(policies[i]) < toNsName(policies[j])
+		return comparePolicyPriority(policies[i], policies[j], false)
 	})
 }
 
+// Highest priority policy will be first. Unlike sortFilterPolicy, this is used for the overrides,
+// so a Policy targeting a lesser specific scope wins. The other rules are the same.
+func sortFilterPolicyForOverrides(policies []*FilterPolicyWrapper) {
+	sort.Slice(policies, func(i, j int) bool {
+		return comparePolicyPriority(policies[i], policies[j], true)
+	})
+}
+
+func comparePolicyPriority(a, b *FilterPolicyWrapper, broaderFirst bool) bool {
+	if a.scope != b.scope {
+		if broaderFirst {
+			return a.scope > b.scope
+		}
+		return a.scope < b.scope
+	}
+	if a.CreationTimestamp != b.CreationTimestamp {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return toNsName(a) < toNsName(b)
+}
+
 type PolicyKind int
 
 const (
@@ -95,6 +126,7 @@ func translateFilterManagerConfigToPolicyInRDS(fmc *filtermanager.FilterManagerC
 	nativeFilters := map[string]map[string]*fmModel.FilterConfig{
 		model.CategoryRoute:       {},
 		model.CategoryRouteFilter: {},
+		model.CategoryHTTPFilter:  {},
 	}
 
 	goFilterManager := &filtermanager.FilterManagerConfig{
@@ -123,6 +155,30 @@ func translateFilterManagerConfigToPolicyInRDS(fmc *filtermanager.FilterManagerC
 		}
 		_ = json.Unmarshal(b, &cfg)
 
+		if _, ok := p.(plugins.WasmPlugin); ok {
+			filterName := fmt.Sprintf("htnn.filters.http.%s", plugin.Name)
+			nativeFilters[model.CategoryRouteFilter][filterName] = &fmModel.FilterConfig{
+				Name: plugin.Name,
+				Config: map[string]interface{}{
+					"@type":    istio.WasmRouteConfigTypeURL,
+					"disabled": false,
+				},
+			}
+			// The wasm filter doesn't support per-route configuration, so we pass the configuration
+			// via the route's metadata
+			nativeFilters[model.CategoryRoute][name] = &fmModel.FilterConfig{
+				Name: plugin.Name,
+				Config: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"filterMetadata": map[string]interface{}{
+							filterName: cfg,
+						},
+					},
+				},
+			}
+			continue
+		}
+
 		nativePlugin, ok := p.(plugins.NativePlugin)
 		if !ok {
 			plugin.Config = cfg
@@ -141,6 +197,16 @@ func translateFilterManagerConfigToPolicyInRDS(fmc *filtermanager.FilterManagerC
 			// We expect user to use camelCase as the field name. If not, the ToRouteConfig may not
 			// work as expected.
 
+			if filterConfigProvider, ok := p.(plugins.HTTPNativePluginHasFilterConfig); ok {
+				fc := filterConfigProvider.ToFilterConfig(m)
+				fc["@type"] = filterConfigProvider.FilterConfigTypeURL()
+				filterName := fmt.Sprintf("htnn.filters.http.%s", plugin.Name)
+				nativeFilters[model.CategoryHTTPFilter][filterName] = &fmModel.FilterConfig{
+					Name:   plugin.Name,
+					Config: fc,
+				}
+			}
+
 			if wrapper, ok := p.(plugins.HTTPNativePluginHasRouteConfigWrapper); ok {
 				m = wrapper.ToRouteConfig(m)
 			}
@@ -239,6 +305,11 @@ func translateFilterManagerConfigToPolicyInLDS(fmc *filtermanager.FilterManagerC
 		}
 		_ = json.Unmarshal(b, &cfg)
 
+		if _, ok := p.(plugins.WasmPlugin); ok {
+			// Wasm plugin is not supported
+			continue
+		}
+
 		nativePlugin, ok := p.(plugins.NativePlugin)
 		if !ok {
 			plugin.Config = cfg
@@ -306,8 +377,10 @@ func translateFilterManagerConfigToPolicyInLDS(fmc *filtermanager.FilterManagerC
 	return config
 }
 
+// toMergedPolicy merges the policies. The plugins in `enforced` are configured by the overrides of
+// the listener which the route belongs to. They are left to the listener so that the route can't override them.
 func toMergedPolicy(nsName *types.NamespacedName, policies []*FilterPolicyWrapper,
-	policyKind PolicyKind, virtualHost *model.VirtualHost) *mergedPolicy {
+	policyKind PolicyKind, virtualHost *model.VirtualHost, enforced map[string]string) *mergedPolicy {
 
 	sortFilterPolicy(policies)
 
@@ -319,11 +392,17 @@ func toMergedPolicy(nsName *types.NamespacedName, policies []*FilterPolicyWrappe
 
 	// use map to deduplicate policies, especially for the sub-policies
 	usedFP := make(map[string]struct{}, len(policies))
-	for _, policy := range policies {
+	// the policy whose plugin takes effect
+	winners := make(map[string]string, len(enforced))
+	for name, by := range enforced {
+		winners[name] = by
+	}
+	merge := func(policy *FilterPolicyWrapper, filters map[string]mosniov1.Plugin) {
 		used := false
-		for name, filter := range policy.Spec.Filters {
-			if _, ok := p.Spec.Filters[name]; !ok {
+		for name, filter := range filters {
+			if _, ok := winners[name]; !ok {
 				p.Spec.Filters[name] = filter
+				winners[name] = toNsName(policy)
 				used = true
 			}
 		}
@@ -333,6 +412,29 @@ func toMergedPolicy(nsName *types.NamespacedName, policies []*FilterPolicyWrappe
 		}
 	}
 
+	var overrides map[string]string
+	overridePolicies := make([]*FilterPolicyWrapper, 0, len(policies))
+	for _, policy := range policies {
+		if len(policy.Spec.Overrides) > 0 {
+			overridePolicies = append(overridePolicies, policy)
+		}
+	}
+	if len(overridePolicies) > 0 {
+		sortFilterPolicyForOverrides(overridePolicies)
+		overrides = make(map[string]string)
+		for _, policy := range overridePolicies {
+			merge(policy, policy.Spec.Overrides)
+		}
+		for name := range p.Spec.Filters {
+			overrides[name] = winners[name]
+		}
+	}
+
+	for _, policy := range policies {
+		merge(policy, policy.Spec.Filters)
+		merge(policy, policy.Spec.Defaults)
+	}
+
 	info := &Info{
 		FilterPolicies: make([]string, 0, len(usedFP)),
 	}
@@ -350,9 +452,111 @@ func toMergedPolicy(nsName *types.NamespacedName, policies []*FilterPolicyWrappe
 	}
 
 	return &mergedPolicy{
-		Config: config,
-		Info:   info,
-		NsName: nsName,
+		Config:    config,
+		Info:      info,
+		NsName:    nsName,
+		Plugins:   toAttachedPlugins(policies, winners),
+		Overrides: overrides,
+	}
+}
+
+func routeConfigSize(virtualHost *model.VirtualHost, routeName string, config map[string]interface{}) int {
+	return configPatchesSize(istio.GenerateRouteFilter(virtualHost, routeName, config))
+}
+
+// toMergedRoutePolicy merges the policies of a route. If the configuration generated for the route
+// exceeds the size limit, the policies are added one by one in the order of priority, and the ones
+// pushing the configuration over the limit are excluded from this route only.
+func (s *mergedState) toMergedRoutePolicy(nsName *types.NamespacedName, policies []*FilterPolicyWrapper,
+	virtualHost *model.VirtualHost, routeName string, enforced map[string]string) (*mergedPolicy, error) {
+
+	mp := toMergedPolicy(nsName, policies, PolicyKindRDS, virtualHost, enforced)
+	maxRouteSize := ctrlcfg.EnvoyFilterMaxRouteSize()
+	if maxRouteSize <= 0 || routeConfigSize(virtualHost, routeName, mp.Config) <= maxRouteSize {
+		return mp, nil
+	}
+
+	// the policies are sorted by toMergedPolicy
+	kept := make([]*FilterPolicyWrapper, 0, len(policies))
+	mp = toMergedPolicy(nsName, kept, PolicyKindRDS, virtualHost, enforced)
+	if size := routeConfigSize(virtualHost, routeName, mp.Config); size > maxRouteSize {
+		// Don't write a configuration without the route. The last written configuration is kept.
+		return nil, fmt.Errorf("the configuration generated for route %s of virtual host %s is %d bytes without any policy, exceeding the limit %d bytes",
+			routeName, virtualHost.Name, size, maxRouteSize)
+	}
+	for _, policy := range policies {
+		candidate := toMergedPolicy(nsName, append(slices.Clone(kept), policy), PolicyKindRDS, virtualHost, enforced)
+		size := routeConfigSize(virtualHost, routeName, candidate.Config)
+		if size > maxRouteSize {
+			msg := fmt.Sprintf("the policy is not applied to route %s of virtual host %s, because the generated configuration is %d bytes with it, exceeding the limit %d bytes",
+				routeName, virtualHost.Name, size, maxRouteSize)
+			log.Errorf("policy %s: %s", toNsName(policy), msg)
+			if curr, ok := s.RejectedPolicies[toNsName(policy)]; !ok || msg < curr {
+				s.RejectedPolicies[toNsName(policy)] = msg
+			}
+			continue
+		}
+		kept = append(kept, policy)
+		mp = candidate
+	}
+	return mp, nil
+}
+
+func toAttachedPlugins(policies []*FilterPolicyWrapper, winners map[string]string) map[string]*attachedPlugins {
+	effective := make(map[string]map[string]struct{}, len(policies))
+	shadowed := make(map[string]map[string]string, len(policies))
+	for _, policy := range policies {
+		nsName := toNsName(policy)
+		if _, ok := effective[nsName]; !ok {
+			effective[nsName] = make(map[string]struct{})
+			shadowed[nsName] = make(map[string]string)
+		}
+		for _, filters := range []map[string]mosniov1.Plugin{
+			policy.Spec.Overrides, policy.Spec.Filters, policy.Spec.Defaults,
+		} {
+			for name := range filters {
+				by := winners[name]
+				if by == nsName {
+					effective[nsName][name] = struct{}{}
+				} else {
+					shadowed[nsName][name] = by
+				}
+			}
+		}
+	}
+
+	res := make(map[string]*attachedPlugins, len(effective))
+	for nsName, names := range effective {
+		ap := &attachedPlugins{}
+		for name := range names {
+			ap.Plugins = append(ap.Plugins, name)
+		}
+		slices.Sort(ap.Plugins)
+
+		for name, by := range shadowed[nsName] {
+			// the plugin may be shadowed by the sub-policy of the same policy
+			if _, ok := names[name]; ok {
+				continue
+			}
+			ap.Shadowed = append(ap.Shadowed, mosniov1.FilterPolicyShadowedPlugin{
+				Name: name,
+				By:   by,
+			})
+		}
+		sort.Slice(ap.Shadowed, func(i, j int) bool {
+			return ap.Shadowed[i].Name < ap.Shadowed[j].Name
+		})
+		res[nsName] = ap
+	}
+	return res
+}
+
+func (s *mergedState) addAttachments(policy *mergedPolicy, attachment mosniov1.FilterPolicyAttachment) {
+	for nsName, ap := range policy.Plugins {
+		a := attachment
+		a.Plugins = ap.Plugins
+		a.ShadowedPlugins = ap.Shadowed
+		s.Attachments[nsName] = append(s.Attachments[nsName], a)
 	}
 }
 
@@ -379,10 +583,35 @@ func sortPlugins(ps []*fmModel.FilterConfig) {
 
 func toMergedState(ctx *Ctx, state *dataPlaneState) (*FinalState, error) {
 	s := &mergedState{
-		Proxies: make(map[Proxy]*mergedProxyConfig),
+		Proxies:          make(map[Proxy]*mergedProxyConfig),
+		Attachments:      make(map[string][]mosniov1.FilterPolicyAttachment),
+		RejectedPolicies: make(map[string]string),
 	}
 
 	for proxy, cfg := range state.Proxies {
+		// The gateways are merged first, so that the plugins enforced by their overrides can be
+		// excluded from the routes. The data plane prefers the route's configuration to the listener's.
+		enforcedPlugins := make(map[string]map[string]string)
+		mergedGateways := make(map[string]*mergedGatewayPolicy)
+		for name, gateway := range cfg.Gateways {
+			mg := &mergedGatewayPolicy{
+				Gateway: gateway.Gateway,
+			}
+			if len(gateway.Policies) > 0 {
+				mg.Policy = toMergedPolicy(&gateway.Gateway.GatewaySection.NsName, gateway.Policies, PolicyKindLDS, nil, nil)
+				s.addAttachments(mg.Policy, mosniov1.FilterPolicyAttachment{
+					Kind:      mosniov1.FilterPolicyAttachmentKindListener,
+					Namespace: proxy.Namespace,
+					Name:      name,
+				})
+				if len(mg.Policy.Overrides) > 0 {
+					enforcedPlugins[getECDSResourceName(proxy.Namespace, name)] = mg.Policy.Overrides
+				}
+			}
+
+			mergedGateways[name] = mg
+		}
+
 		mergedHosts := make(map[string]*mergedHostPolicy)
 		for name, host := range cfg.Hosts {
 			mh := &mergedHostPolicy{
@@ -390,26 +619,24 @@ func toMergedState(ctx *Ctx, state *dataPlaneState) (*FinalState, error) {
 				Routes:      make(map[string]*mergedPolicy),
 			}
 
+			enforced := enforcedPlugins[mh.VirtualHost.ECDSResourceName]
 			for routeName, route := range host.Routes {
-				mergedPolicy := toMergedPolicy(route.NsName, route.Policies, PolicyKindRDS, mh.VirtualHost)
+				mergedPolicy, err := s.toMergedRoutePolicy(route.NsName, route.Policies, mh.VirtualHost, routeName, enforced)
+				if err != nil {
+					return nil, err
+				}
 				mh.Routes[routeName] = mergedPolicy
+				s.addAttachments(mergedPolicy, mosniov1.FilterPolicyAttachment{
+					Kind:        mosniov1.FilterPolicyAttachmentKindRoute,
+					Namespace:   proxy.Namespace,
+					VirtualHost: mh.VirtualHost.Name,
+					Name:        routeName,
+				})
 			}
 
 			mergedHosts[name] = mh
 		}
 
-		mergedGateways := make(map[string]*mergedGatewayPolicy)
-		for name, gateway := range cfg.Gateways {
-			mg := &mergedGatewayPolicy{
-				Gateway: gateway.Gateway,
-			}
-			if len(gateway.Policies) > 0 {
-				mg.Policy = toMergedPolicy(&gateway.Gateway.GatewaySection.NsName, gateway.Policies, PolicyKindLDS, nil)
-			}
-
-			mergedGateways[name] = mg
-		}
-
 		s.Proxies[proxy] = &mergedProxyConfig{
 			Hosts:    mergedHosts,
 			Gateways: mergedGateways,
@@ -19,7 +19,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
+	"mosn.io/htnn/controller/internal/model"
 	mosniov1 "mosn.io/htnn/types/apis/v1"
 )
 
@@ -68,3 +71,113 @@ func TestSortFilterPolicy(t *testing.T) {
 	assert.Equal(t, "route-policy-latest", ps[3].GetName())
 	assert.Equal(t, "gateway-policy", ps[4].GetName())
 }
+
+func TestToAttachedPlugins(t *testing.T) {
+	newPolicy := func(name string, scope PolicyScope, plugins ...string) *FilterPolicyWrapper {
+		filters := map[string]mosniov1.Plugin{}
+		for _, p := range plugins {
+			filters[p] = mosniov1.Plugin{}
+		}
+		return &FilterPolicyWrapper{
+			FilterPolicy: &mosniov1.FilterPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: mosniov1.FilterPolicySpec{
+					Filters: filters,
+				},
+			},
+			scope: scope,
+		}
+	}
+
+	ps := []*FilterPolicyWrapper{
+		newPolicy("gateway", PolicyScopeGateway, "limitReq", "demo", "opa"),
+		newPolicy("route", PolicyScopeRoute, "demo", "keyAuth"),
+		// sub-policy of the route policy
+		newPolicy("route", PolicyScopeRule, "keyAuth"),
+		newPolicy("rule", PolicyScopeRule, "opa", "limitReq"),
+	}
+	winners := map[string]string{
+		"keyAuth":  "default/route",
+		"opa":      "default/rule",
+		"limitReq": "default/rule",
+		"demo":     "default/route",
+	}
+	res := toAttachedPlugins(ps, winners)
+	assert.Equal(t, map[string]*attachedPlugins{
+		"default/gateway": {
+			Shadowed: []mosniov1.FilterPolicyShadowedPlugin{
+				{Name: "demo", By: "default/route"},
+				{Name: "limitReq", By: "default/rule"},
+				{Name: "opa", By: "default/rule"},
+			},
+		},
+		"default/route": {
+			Plugins: []string{"demo", "keyAuth"},
+		},
+		"default/rule": {
+			Plugins: []string{"limitReq", "opa"},
+		},
+	}, res)
+}
+
+func TestToMergedPolicyWithOverrides(t *testing.T) {
+	newPolicy := func(name string, scope PolicyScope, overrides []string, filters ...string) *FilterPolicyWrapper {
+		spec := mosniov1.FilterPolicySpec{
+			Filters:   map[string]mosniov1.Plugin{},
+			Overrides: map[string]mosniov1.Plugin{},
+		}
+		for _, p := range filters {
+			spec.Filters[p] = mosniov1.Plugin{Config: runtime.RawExtension{Raw: []byte("{}")}}
+		}
+		for _, p := range overrides {
+			spec.Overrides[p] = mosniov1.Plugin{Config: runtime.RawExtension{Raw: []byte("{}")}}
+		}
+		return &FilterPolicyWrapper{
+			FilterPolicy: &mosniov1.FilterPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: spec,
+			},
+			scope: scope,
+		}
+	}
+
+	ps := []*FilterPolicyWrapper{
+		newPolicy("rule", PolicyScopeRule, []string{"waf"}, "limitReq", "opa", "cors"),
+		newPolicy("route", PolicyScopeRoute, []string{"limitReq", "waf"}, "keyAuth"),
+		newPolicy("port", PolicyScopePort, []string{"opa"}),
+	}
+	enforced := map[string]string{
+		"keyAuth": "default/gateway",
+	}
+	mp := toMergedPolicy(&types.NamespacedName{}, ps, PolicyKindRDS, &model.VirtualHost{}, enforced)
+	assert.Equal(t, map[string]string{
+		"limitReq": "default/route",
+		"opa":      "default/port",
+		"waf":      "default/route",
+	}, mp.Overrides)
+	assert.Equal(t, map[string]*attachedPlugins{
+		"default/port": {
+			Plugins: []string{"opa"},
+		},
+		"default/route": {
+			Plugins: []string{"limitReq", "waf"},
+			Shadowed: []mosniov1.FilterPolicyShadowedPlugin{
+				{Name: "keyAuth", By: "default/gateway"},
+			},
+		},
+		"default/rule": {
+			Plugins: []string{"cors"},
+			Shadowed: []mosniov1.FilterPolicyShadowedPlugin{
+				{Name: "limitReq", By: "default/route"},
+				{Name: "opa", By: "default/port"},
+				{Name: "waf", By: "default/route"},
+			},
+		},
+	}, mp.Plugins)
+}
@@ -21,11 +21,13 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	"sigs.k8s.io/yaml"
 
@@ -75,8 +77,9 @@ func mustUnmarshal(t *testing.T, fn string, out interface{}) {
 }
 
 type Features struct {
-	EnableLDSPluginViaECDS   bool `json:"enableLDSPluginViaECDS"`
-	UseWildcardIPv6InLDSName bool `json:"useWildcardIPv6InLDSName"`
+	EnableLDSPluginViaECDS    bool `json:"enableLDSPluginViaECDS"`
+	UseWildcardIPv6InLDSName  bool `json:"useWildcardIPv6InLDSName"`
+	EnvoyFilterShardMaxRoutes int  `json:"envoyFilterShardMaxRoutes"`
 }
 
 type testInput struct {
@@ -87,6 +90,9 @@ type testInput struct {
 	IstioGateway   []*istiov1a3.Gateway                   `json:"istioGateway"`
 
 	HTTPRoute map[string][]*gwapiv1b1.HTTPRoute `json:"httpRoute"`
+	GRPCRoute map[string][]*gwapiv1a2.GRPCRoute `json:"grpcRoute"`
+	TCPRoute  map[string][]*gwapiv1a2.TCPRoute  `json:"tcpRoute"`
+	TLSRoute  map[string][]*gwapiv1a2.TLSRoute  `json:"tlsRoute"`
 	Gateway   []*gwapiv1b1.Gateway              `json:"gateway"`
 
 	Features *Features `json:"features"`
@@ -110,6 +116,9 @@ func TestTranslate(t *testing.T) {
 				if feats.UseWildcardIPv6InLDSName {
 					os.Setenv("HTNN_USE_WILDCARD_IPV6_IN_LDS_NAME", "true")
 				}
+				if feats.EnvoyFilterShardMaxRoutes > 0 {
+					os.Setenv("HTNN_ENVOYFILTER_SHARD_MAX_ROUTES", strconv.Itoa(feats.EnvoyFilterShardMaxRoutes))
+				}
 				config.Init()
 
 				defer func() {
@@ -119,6 +128,9 @@ func TestTranslate(t *testing.T) {
 					if feats.UseWildcardIPv6InLDSName {
 						os.Setenv("HTNN_USE_WILDCARD_IPV6_IN_LDS_NAME", "false")
 					}
+					if feats.EnvoyFilterShardMaxRoutes > 0 {
+						os.Setenv("HTNN_ENVOYFILTER_SHARD_MAX_ROUTES", "0")
+					}
 					config.Init()
 				}()
 			}
@@ -128,9 +140,12 @@ func TestTranslate(t *testing.T) {
 			// set up resources
 			type gwapiWrapper struct {
 				hr  *gwapiv1b1.HTTPRoute
+				gr  *gwapiv1a2.GRPCRoute
+				tcp *gwapiv1a2.TCPRoute
+				tls *gwapiv1a2.TLSRoute
 				gws []*gwapiv1b1.Gateway
 			}
-			hrToGws := map[string]gwapiWrapper{}
+			routeToGws := map[string]gwapiWrapper{}
 			for _, gw := range input.Gateway {
 				// fulfill default fields
 				if gw.Namespace == "" {
@@ -141,14 +156,41 @@ func TestTranslate(t *testing.T) {
 					if hr.Namespace == "" {
 						hr.SetNamespace("default")
 					}
-					hrToGws[hr.Name] = gwapiWrapper{
+					routeToGws[hr.Name] = gwapiWrapper{
 						hr:  hr,
-						gws: append(hrToGws[hr.Name].gws, gw),
+						gws: append(routeToGws[hr.Name].gws, gw),
+					}
+				}
+				for _, gr := range input.GRPCRoute[gw.Name] {
+					if gr.Namespace == "" {
+						gr.SetNamespace("default")
+					}
+					routeToGws[gr.Name] = gwapiWrapper{
+						gr:  gr,
+						gws: append(routeToGws[gr.Name].gws, gw),
+					}
+				}
+				for _, tcp := range input.TCPRoute[gw.Name] {
+					if tcp.Namespace == "" {
+						tcp.SetNamespace("default")
+					}
+					routeToGws[tcp.Name] = gwapiWrapper{
+						tcp: tcp,
+						gws: append(routeToGws[tcp.Name].gws, gw),
+					}
+				}
+				for _, tls := range input.TLSRoute[gw.Name] {
+					if tls.Namespace == "" {
+						tls.SetNamespace("default")
+					}
+					routeToGws[tls.Name] = gwapiWrapper{
+						tls: tls,
+						gws: append(routeToGws[tls.Name].gws, gw),
 					}
 				}
 			}
 			fpsMap := maps.Clone(input.FilterPolicy)
-			for name, wrapper := range hrToGws {
+			for name, wrapper := range routeToGws {
 				fps := input.FilterPolicy[name]
 				if fps != nil {
 					// Currently, a policy can only target one resource.
@@ -158,7 +200,16 @@ func TestTranslate(t *testing.T) {
 					if fp.Namespace == "" {
 						fp.SetNamespace("default")
 					}
-					s.AddPolicyForHTTPRoute(fp, wrapper.hr, wrapper.gws)
+					switch {
+					case wrapper.hr != nil:
+						s.AddPolicyForHTTPRoute(fp, wrapper.hr, wrapper.gws)
+					case wrapper.gr != nil:
+						s.AddPolicyForGRPCRoute(fp, wrapper.gr, wrapper.gws)
+					case wrapper.tcp != nil:
+						s.AddPolicyForTCPRoute(fp, wrapper.tcp, wrapper.gws)
+					case wrapper.tls != nil:
+						s.AddPolicyForTLSRoute(fp, wrapper.tls, wrapper.gws)
+					}
 				}
 			}
 
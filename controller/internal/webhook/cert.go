@@ -0,0 +1,328 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	caCertFile = "ca.crt"
+	caKeyFile  = "ca.key"
+	certFile   = corev1.TLSCertKey
+	keyFile    = corev1.TLSPrivateKeyKey
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+	// regenerate the certificate if it's going to expire soon
+	certRenewBefore = 30 * 24 * time.Hour
+	// rotate the CA if it can't outlive a newly issued certificate
+	caRenewBefore = certValidity + certRenewBefore
+)
+
+// certBundle contains the PEM encoded self-signed certificate
+type certBundle struct {
+	// CACert is the CA which signs the serving certificate, followed by the previous CAs which are not expired.
+	// The previous CAs are kept so that the replicas still serving the certificates signed by them are trusted
+	// during the rotation.
+	CACert []byte
+	CAKey  []byte
+	Cert   []byte
+	Key    []byte
+}
+
+func serviceDNSNames(service, namespace string) []string {
+	return []string{
+		service,
+		service + "." + namespace,
+		service + "." + namespace + ".svc",
+		service + "." + namespace + ".svc.cluster.local",
+	}
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// generateCA generates a self-signed CA
+func generateCA(now time.Time) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "htnn-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ca, caKey, nil
+}
+
+// issueCert issues a serving certificate signed by the CA for the given DNS names
+func issueCert(ca *x509.Certificate, caKey *ecdsa.PrivateKey, dnsNames []string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// parseCA returns the CA which signs the serving certificate, and its private key
+func parseCA(b *certBundle) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(b.CACert)
+	if block == nil {
+		return nil, nil, errors.New("no CA certificate")
+	}
+	ca, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ = pem.Decode(b.CAKey)
+	if block == nil {
+		return nil, nil, errors.New("no CA private key")
+	}
+	caKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !caKey.PublicKey.Equal(ca.PublicKey) {
+		return nil, nil, errors.New("the CA private key doesn't match the certificate")
+	}
+	return ca, caKey, nil
+}
+
+// appendUnexpiredCerts appends the certificates in the PEM encoded bundle which are not expired and not in the dst
+func appendUnexpiredCerts(dst []byte, bundle []byte, now time.Time) []byte {
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return dst
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.After(cert.NotAfter) {
+			continue
+		}
+		encoded := pem.EncodeToMemory(block)
+		if !bytes.Contains(dst, encoded) {
+			dst = append(dst, encoded...)
+		}
+	}
+}
+
+// renewCert issues a new serving certificate. The CA of the previous bundle is reused unless it's going to expire,
+// so that the certificates served by other replicas are still trusted. When the CA is rotated, the previous CAs
+// are kept in the bundle until they are expired.
+func renewCert(prev *certBundle, dnsNames []string, now time.Time) (*certBundle, error) {
+	var prevCAs []byte
+	if prev != nil {
+		ca, caKey, err := parseCA(prev)
+		if err == nil && ca.NotAfter.After(now.Add(caRenewBefore)) {
+			cert, key, err := issueCert(ca, caKey, dnsNames, now)
+			if err != nil {
+				return nil, err
+			}
+			caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+			return &certBundle{
+				CACert: appendUnexpiredCerts(caPEM, prev.CACert, now),
+				CAKey:  prev.CAKey,
+				Cert:   cert,
+				Key:    key,
+			}, nil
+		}
+		prevCAs = prev.CACert
+	}
+
+	ca, caKey, err := generateCA(now)
+	if err != nil {
+		return nil, err
+	}
+	caKeyPEM, err := encodeKey(caKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, key, err := issueCert(ca, caKey, dnsNames, now)
+	if err != nil {
+		return nil, err
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+	return &certBundle{
+		CACert: appendUnexpiredCerts(caPEM, prevCAs, now),
+		CAKey:  caKeyPEM,
+		Cert:   cert,
+		Key:    key,
+	}, nil
+}
+
+func certFromSecret(secret *corev1.Secret) *certBundle {
+	return &certBundle{
+		CACert: secret.Data[caCertFile],
+		CAKey:  secret.Data[caKeyFile],
+		Cert:   secret.Data[certFile],
+		Key:    secret.Data[keyFile],
+	}
+}
+
+// isCertUsable checks if the serving certificate is signed by the CA, covers the DNS names and is not
+// going to expire soon, and the CA can be used to renew it later
+func isCertUsable(b *certBundle, dnsNames []string, now time.Time) bool {
+	if _, _, err := parseCA(b); err != nil {
+		return false
+	}
+	block, _ := pem.Decode(b.Cert)
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b.CACert) {
+		return false
+	}
+	for _, name := range dnsNames {
+		_, err := cert.Verify(x509.VerifyOptions{
+			DNSName:     name,
+			Roots:       pool,
+			CurrentTime: now.Add(certRenewBefore),
+		})
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureCert returns the self-signed certificate stored in the Secret, so that all the replicas share the same one.
+// A new one will be issued and stored if the stored one is missing or not usable.
+func ensureCert(ctx context.Context, client kubernetes.Interface, nsName types.NamespacedName,
+	dnsNames []string, now time.Time) (*certBundle, error) {
+
+	secrets := client.CoreV1().Secrets(nsName.Namespace)
+	secret, err := secrets.Get(ctx, nsName.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get webhook certificate: %w", err)
+	}
+	var prev *certBundle
+	if err == nil {
+		prev = certFromSecret(secret)
+		if isCertUsable(prev, dnsNames, now) {
+			return prev, nil
+		}
+	}
+
+	b, genErr := renewCert(prev, dnsNames, now)
+	if genErr != nil {
+		return nil, fmt.Errorf("failed to generate webhook certificate: %w", genErr)
+	}
+	data := map[string][]byte{
+		caCertFile: b.CACert,
+		caKeyFile:  b.CAKey,
+		certFile:   b.Cert,
+		keyFile:    b.Key,
+	}
+
+	if err != nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nsName.Name,
+				Namespace: nsName.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret.Data = data
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
+		// another replica wins the race, use its certificate
+		secret, err = secrets.Get(ctx, nsName.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get webhook certificate: %w", err)
+		}
+		return certFromSecret(secret), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store webhook certificate: %w", err)
+	}
+	return b, nil
+}
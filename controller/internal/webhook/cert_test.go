@@ -0,0 +1,176 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureCert(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	nsName := types.NamespacedName{Namespace: "istio-system", Name: "htnn-webhook-cert"}
+	dnsNames := serviceDNSNames("istiod", "istio-system")
+	now := time.Now()
+
+	b, err := ensureCert(ctx, client, nsName, dnsNames, now)
+	require.NoError(t, err)
+	assert.True(t, isCertUsable(b, dnsNames, now))
+	assert.False(t, isCertUsable(b, serviceDNSNames("htnn", "istio-system"), now))
+
+	// reuse the stored one
+	b2, err := ensureCert(ctx, client, nsName, dnsNames, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, b, b2)
+
+	// renew before it's expired
+	b3, err := ensureCert(ctx, client, nsName, dnsNames, now.Add(certValidity-certRenewBefore/2))
+	require.NoError(t, err)
+	assert.NotEqual(t, b.Cert, b3.Cert)
+	// the CA is kept so that the certificate served by other replicas is still trusted
+	assert.Equal(t, b.CACert, b3.CACert)
+	assert.Equal(t, b.CAKey, b3.CAKey)
+
+	secret, err := client.CoreV1().Secrets(nsName.Namespace).Get(ctx, nsName.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, b3, certFromSecret(secret))
+
+	// rotate the CA before it's expired, when the certificate issued by it is going to expire
+	rotateAt := now.Add(caValidity - caRenewBefore + time.Hour)
+	ca, caKey, err := parseCA(b3)
+	require.NoError(t, err)
+	b3.Cert, b3.Key, err = issueCert(ca, caKey, dnsNames, rotateAt.Add(-certValidity+certRenewBefore/2))
+	require.NoError(t, err)
+	secret.Data[certFile], secret.Data[keyFile] = b3.Cert, b3.Key
+	_, err = client.CoreV1().Secrets(nsName.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	b4, err := ensureCert(ctx, client, nsName, dnsNames, rotateAt)
+	require.NoError(t, err)
+	assert.NotEqual(t, b3.CAKey, b4.CAKey)
+	assert.True(t, bytes.HasSuffix(b4.CACert, b3.CACert))
+	assert.True(t, isCertUsable(b4, dnsNames, rotateAt))
+	// the previous leaf is still trusted by the published bundle
+	old := &certBundle{CACert: b4.CACert, CAKey: b4.CAKey, Cert: b3.Cert, Key: b3.Key}
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(old.CACert))
+	block, _ := pem.Decode(old.Cert)
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: dnsNames[0], Roots: pool, CurrentTime: rotateAt})
+	assert.NoError(t, err)
+
+	// the expired CA is dropped from the bundle
+	b5, err := ensureCert(ctx, client, nsName, dnsNames, now.Add(caValidity+certValidity))
+	require.NoError(t, err)
+	assert.Equal(t, b4.CAKey, b5.CAKey)
+	assert.False(t, bytes.Contains(b5.CACert, b3.CACert))
+}
+
+func TestEnsureCertWithoutCAKey(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	nsName := types.NamespacedName{Namespace: "istio-system", Name: "htnn-webhook-cert"}
+	dnsNames := serviceDNSNames("istiod", "istio-system")
+	now := time.Now()
+
+	b, err := ensureCert(ctx, client, nsName, dnsNames, now)
+	require.NoError(t, err)
+
+	// the Secret created by the previous version doesn't contain the CA private key
+	secret, err := client.CoreV1().Secrets(nsName.Namespace).Get(ctx, nsName.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	delete(secret.Data, caKeyFile)
+	_, err = client.CoreV1().Secrets(nsName.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	b2, err := ensureCert(ctx, client, nsName, dnsNames, now)
+	require.NoError(t, err)
+	assert.NotEmpty(t, b2.CAKey)
+	assert.True(t, bytes.HasSuffix(b2.CACert, b.CACert))
+}
+
+func TestCertReloader(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "istiod-htnn-validator"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "validation.htnn.mosn.io"},
+		},
+	})
+	nsName := types.NamespacedName{Namespace: "istio-system", Name: "htnn-webhook-cert"}
+	r := newCertReloader(client, nsName, serviceDNSNames("istiod", "istio-system"), "istiod-htnn-validator")
+
+	_, err := r.getCertificate(nil)
+	assert.Error(t, err)
+
+	require.NoError(t, r.sync(ctx))
+	cert, err := r.getCertificate(nil)
+	require.NoError(t, err)
+	vwc, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "istiod-htnn-validator", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, r.caBundle, vwc.Webhooks[0].ClientConfig.CABundle)
+
+	// the certificate renewed by another replica is reloaded
+	secret, err := client.CoreV1().Secrets(nsName.Namespace).Get(ctx, nsName.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	b := certFromSecret(secret)
+	ca, caKey, err := parseCA(b)
+	require.NoError(t, err)
+	secret.Data[certFile], secret.Data[keyFile], err = issueCert(ca, caKey, r.dnsNames, time.Now())
+	require.NoError(t, err)
+	_, err = client.CoreV1().Secrets(nsName.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go r.run(ctx)
+	require.Eventually(t, func() bool {
+		newCert, err := r.getCertificate(nil)
+		return err == nil && newCert != cert
+	}, 5*time.Second, 10*time.Millisecond)
+	newCert, _ := r.getCertificate(nil)
+	assert.NotEqual(t, cert.Certificate[0], newCert.Certificate[0])
+}
+
+func TestPatchCABundle(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "istiod-htnn-validator"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "validation.htnn.mosn.io"},
+		},
+	})
+
+	err := patchCABundle(ctx, client, "istiod-htnn-validator", []byte("ca"))
+	require.NoError(t, err)
+	vwc, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "istiod-htnn-validator", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ca"), vwc.Webhooks[0].ClientConfig.CABundle)
+
+	err = patchCABundle(ctx, client, "nonexistent", []byte("ca"))
+	assert.Error(t, err)
+}
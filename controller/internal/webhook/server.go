@@ -0,0 +1,213 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+)
+
+// ValidationPath is the path to serve the validating webhook
+const ValidationPath = "/validate-htnn"
+
+// patchCABundle sets the caBundle of the ValidatingWebhookConfiguration to the self-signed CA
+func patchCABundle(ctx context.Context, client kubernetes.Interface, name string, caBundle []byte) error {
+	configs := client.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		vwc, err := configs.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		for i := range vwc.Webhooks {
+			wh := &vwc.Webhooks[i]
+			if !bytes.Equal(wh.ClientConfig.CABundle, caBundle) {
+				wh.ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		_, err = configs.Update(ctx, vwc, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// certResyncPeriod is the interval to check if the certificate needs to be renewed, in case no Secret events come
+const certResyncPeriod = time.Hour
+
+// certReloader keeps the serving certificate in sync with the one stored in the Secret. All the replicas share the
+// same Secret, so the certificate renewed by one replica will be picked up by the others via the Secret watch.
+type certReloader struct {
+	client     kubernetes.Interface
+	nsName     types.NamespacedName
+	dnsNames   []string
+	webhookCfg string
+
+	// the fields below are only accessed in sync, except the cert
+	cert     atomic.Pointer[tls.Certificate]
+	certPEM  []byte
+	caBundle []byte
+}
+
+func newCertReloader(client kubernetes.Interface, nsName types.NamespacedName, dnsNames []string,
+	webhookCfg string) *certReloader {
+
+	return &certReloader{
+		client:     client,
+		nsName:     nsName,
+		dnsNames:   dnsNames,
+		webhookCfg: webhookCfg,
+	}
+}
+
+// sync renews the certificate if needed, publishes the CA bundle and reloads the serving certificate
+func (r *certReloader) sync(ctx context.Context) error {
+	b, err := ensureCert(ctx, r.client, r.nsName, r.dnsNames, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// The caBundle should be updated before serving the certificate signed by the new CA.
+	// As the bundle also contains the previous CAs, the certificates served by other replicas are still trusted.
+	if !bytes.Equal(r.caBundle, b.CACert) {
+		if err := patchCABundle(ctx, r.client, r.webhookCfg, b.CACert); err != nil {
+			return fmt.Errorf("failed to patch caBundle of ValidatingWebhookConfiguration %s: %w",
+				r.webhookCfg, err)
+		}
+		r.caBundle = b.CACert
+	}
+
+	if !bytes.Equal(r.certPEM, b.Cert) {
+		cert, err := tls.X509KeyPair(b.Cert, b.Key)
+		if err != nil {
+			return fmt.Errorf("invalid webhook certificate: %w", err)
+		}
+		r.cert.Store(&cert)
+		r.certPEM = b.Cert
+		log.Infof("webhook certificate reloaded from Secret %s", r.nsName)
+	}
+	return nil
+}
+
+func (r *certReloader) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := r.cert.Load()
+	if cert == nil {
+		return nil, errors.New("webhook certificate is not loaded")
+	}
+	return cert, nil
+}
+
+// run watches the Secret and re-syncs the certificate periodically until the ctx is done
+func (r *certReloader) run(ctx context.Context) {
+	trigger := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(r.client, 0,
+		informers.WithNamespace(r.nsName.Namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", r.nsName.Name).String()
+		}))
+	informer := factory.Core().V1().Secrets().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ interface{}) { notify() },
+		UpdateFunc: func(_, _ interface{}) { notify() },
+		DeleteFunc: func(_ interface{}) { notify() },
+	})
+	if err != nil {
+		log.Errorf("failed to watch webhook certificate Secret %s: %v", r.nsName, err)
+	}
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	ticker := time.NewTicker(certResyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+		case <-ticker.C:
+		}
+		if err := r.sync(ctx); err != nil {
+			log.Errorf("failed to sync webhook certificate: %v", err)
+		}
+	}
+}
+
+// ServeWithSelfSignedCert serves the handlers, keyed by the path, with a dedicated TLS server, whose certificate
+// is self-signed. The certificate is renewed before it's expired and reloaded when the Secret is changed.
+// The server is closed when the ctx is done.
+func ServeWithSelfSignedCert(ctx context.Context, client kubernetes.Interface, handlers map[string]http.Handler) error {
+	ns := config.RootNamespace()
+	nsName := types.NamespacedName{Namespace: ns, Name: config.WebhookCertSecret()}
+	r := newCertReloader(client, nsName, serviceDNSNames(config.WebhookService(), ns), config.WebhookConfigName())
+	if err := r.sync(ctx); err != nil {
+		return err
+	}
+	go r.run(ctx)
+
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
+	}
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", config.WebhookPort()),
+		Handler: mux,
+		TLSConfig: &tls.Config{
+			GetCertificate: r.getCertificate,
+			MinVersion:     tls.VersionTLS12,
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	go func() {
+		log.Infof("serving validating webhook on %s", srv.Addr)
+		err := srv.ListenAndServeTLS("", "")
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("failed to serve validating webhook: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,252 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/pkg/component"
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+	"mosn.io/htnn/types/pkg/proto"
+)
+
+// Validator validates the HTNN resources before they are persisted.
+// Unlike the validation in the reconciliation, the plugin configuration is validated strictly,
+// so unknown plugins or fields are rejected.
+type Validator struct {
+	manager component.ResourceManager
+}
+
+func NewValidator(manager component.ResourceManager) *Validator {
+	return &Validator{
+		manager: manager,
+	}
+}
+
+// NewHandler returns a handler which serves the AdmissionReview requests
+func NewHandler(manager component.ResourceManager) http.Handler {
+	return &admission.Webhook{
+		Handler: NewValidator(manager),
+	}
+}
+
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete || req.Kind.Group != mosniov1.GroupVersion.Group {
+		return admission.Allowed("")
+	}
+
+	switch req.Kind.Kind {
+	case "FilterPolicy":
+		var policy mosniov1.FilterPolicy
+		if err := json.Unmarshal(req.Object.Raw, &policy); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := mosniov1.ValidateFilterPolicyStrictly(&policy); err != nil {
+			return admission.Denied(err.Error())
+		}
+		return admission.Allowed("").WithWarnings(v.checkTargetRef(ctx, &policy)...)
+
+	case "HTTPFilterPolicy":
+		var policy mosniov1.HTTPFilterPolicy
+		if err := json.Unmarshal(req.Object.Raw, &policy); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := mosniov1.ValidateHTTPFilterPolicyStrictly(&policy); err != nil {
+			return admission.Denied(err.Error())
+		}
+
+	case "Consumer":
+		var consumer mosniov1.Consumer
+		if err := json.Unmarshal(req.Object.Raw, &consumer); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := mosniov1.ValidateConsumer(&consumer); err != nil {
+			return admission.Denied(err.Error())
+		}
+		if err := v.checkConsumerCollision(ctx, &consumer); err != nil {
+			return admission.Denied(err.Error())
+		}
+
+	case "ServiceRegistry":
+		var sr mosniov1.ServiceRegistry
+		if err := json.Unmarshal(req.Object.Raw, &sr); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := mosniov1.ValidateServiceRegistry(&sr); err != nil {
+			return admission.Denied(err.Error())
+		}
+
+	case "DynamicConfig":
+		var dc mosniov1.DynamicConfig
+		if err := json.Unmarshal(req.Object.Raw, &dc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := mosniov1.ValidateDynamicConfig(&dc); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+func targetObject(group, kind string) client.Object {
+	switch group {
+	case "networking.istio.io":
+		switch kind {
+		case "VirtualService":
+			return &istiov1a3.VirtualService{}
+		case "Gateway":
+			return &istiov1a3.Gateway{}
+		}
+	case "gateway.networking.k8s.io":
+		if !config.EnableGatewayAPI() {
+			return nil
+		}
+		switch kind {
+		case "HTTPRoute":
+			return &gwapiv1b1.HTTPRoute{}
+		case "Gateway":
+			return &gwapiv1b1.Gateway{}
+		}
+		if !config.EnableAlphaGatewayAPI() {
+			return nil
+		}
+		switch kind {
+		case "GRPCRoute":
+			return &gwapiv1a2.GRPCRoute{}
+		case "TCPRoute":
+			return &gwapiv1a2.TCPRoute{}
+		case "TLSRoute":
+			return &gwapiv1a2.TLSRoute{}
+		}
+	}
+	return nil
+}
+
+// checkTargetRef warns if the target of the FilterPolicy doesn't exist. As the target may be created later,
+// the policy is not rejected.
+func (v *Validator) checkTargetRef(ctx context.Context, policy *mosniov1.FilterPolicy) []string {
+	ref := policy.Spec.TargetRef
+	if ref == nil {
+		return nil
+	}
+
+	obj := targetObject(string(ref.Group), string(ref.Kind))
+	if obj == nil {
+		return []string{fmt.Sprintf("target %s/%s is not supported or not enabled", ref.Group, ref.Kind)}
+	}
+
+	nsName := types.NamespacedName{Name: string(ref.Name), Namespace: policy.Namespace}
+	err := v.manager.Get(ctx, nsName, obj)
+	if err == nil {
+		return nil
+	}
+	if apierrors.IsNotFound(err) {
+		return []string{fmt.Sprintf("target %s %s not found, the policy won't take effect until it's created", ref.Kind, nsName)}
+	}
+
+	log.Errorf("failed to get target of FilterPolicy: %v, NamespacedName: %v", err, nsName)
+	return nil
+}
+
+// consumerIndexes returns the indexes of the consumer, grouped by the authn plugin.
+func consumerIndexes(consumer *mosniov1.Consumer) map[string][]string {
+	res := make(map[string][]string, len(consumer.Spec.Auth))
+	for name, filter := range consumer.Spec.Auth {
+		p, ok := plugins.LoadPluginType(name).(plugins.ConsumerPlugin)
+		if !ok {
+			continue
+		}
+
+		conf := p.ConsumerConfig()
+		if err := proto.UnmarshalJSON(filter.Config.Raw, conf); err != nil {
+			continue
+		}
+
+		if multi, ok := conf.(api.PluginConsumerMultiIndexConfig); ok {
+			res[name] = multi.Indexes()
+		} else {
+			res[name] = []string{conf.Index()}
+		}
+	}
+	return res
+}
+
+func consumerName(consumer *mosniov1.Consumer) string {
+	if consumer.Spec.Name != "" {
+		return consumer.Spec.Name
+	}
+	return consumer.Name
+}
+
+// checkConsumerCollision rejects the Consumer which has the same name or the same credential
+// as another Consumer in the same namespace.
+func (v *Validator) checkConsumerCollision(ctx context.Context, consumer *mosniov1.Consumer) error {
+	var consumers mosniov1.ConsumerList
+	if err := v.manager.List(ctx, &consumers); err != nil {
+		// Don't block the request if the cache is not ready. The collision will be reported
+		// in the status.
+		log.Errorf("failed to list Consumer: %v", err)
+		return nil
+	}
+
+	name := consumerName(consumer)
+	indexes := consumerIndexes(consumer)
+	for i := range consumers.Items {
+		other := &consumers.Items[i]
+		if other.Namespace != consumer.Namespace || other.Name == consumer.Name {
+			continue
+		}
+
+		if consumerName(other) == name {
+			return fmt.Errorf("duplicate with another consumer %s/%s, k8s name %s", other.Namespace, name, other.Name)
+		}
+
+		for plugin, otherIndexes := range consumerIndexes(other) {
+			var dup []string
+			for _, idx := range indexes[plugin] {
+				for _, otherIdx := range otherIndexes {
+					if idx == otherIdx {
+						dup = append(dup, idx)
+					}
+				}
+			}
+			if len(dup) > 0 {
+				sort.Strings(dup)
+				return fmt.Errorf("filter %s: index %s collides with consumer %s/%s",
+					plugin, strings.Join(dup, ","), other.Namespace, other.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	_ "mosn.io/htnn/controller/plugins" // register plugins
+	mosniov1 "mosn.io/htnn/types/apis/v1"
+)
+
+type fakeResourceManager struct {
+	virtualServices map[string]bool
+	consumers       []mosniov1.Consumer
+}
+
+func (m *fakeResourceManager) Get(_ context.Context, key client.ObjectKey, out client.Object) error {
+	if _, ok := out.(*istiov1a3.VirtualService); ok && m.virtualServices[key.String()] {
+		return nil
+	}
+	return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+}
+
+func (m *fakeResourceManager) List(_ context.Context, list client.ObjectList) error {
+	l := list.(*mosniov1.ConsumerList)
+	for _, item := range m.consumers {
+		l.Items = append(l.Items, *item.DeepCopy())
+	}
+	return nil
+}
+
+func (m *fakeResourceManager) UpdateStatus(_ context.Context, _ client.Object, _ any) error {
+	return nil
+}
+
+func newRequest(t *testing.T, kind string, op admissionv1.Operation, obj any) admission.Request {
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "htnn.mosn.io", Version: "v1", Kind: kind},
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func newFilterPolicy(plugin string, config string) *mosniov1.FilterPolicy {
+	return &mosniov1.FilterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "policy"},
+		Spec: mosniov1.FilterPolicySpec{
+			TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+				PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+					Group: "networking.istio.io",
+					Kind:  "VirtualService",
+					Name:  "vs",
+				},
+			},
+			Filters: map[string]mosniov1.Plugin{
+				plugin: {Config: runtime.RawExtension{Raw: []byte(config)}},
+			},
+		},
+	}
+}
+
+func newConsumer(name string, key string) *mosniov1.Consumer {
+	return &mosniov1.Consumer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: mosniov1.ConsumerSpec{
+			Auth: map[string]mosniov1.ConsumerPlugin{
+				"keyAuth": {Config: runtime.RawExtension{Raw: []byte(key)}},
+			},
+		},
+	}
+}
+
+func TestValidatorFilterPolicy(t *testing.T) {
+	v := NewValidator(&fakeResourceManager{
+		virtualServices: map[string]bool{"default/vs": true},
+	})
+
+	tests := []struct {
+		name    string
+		policy  *mosniov1.FilterPolicy
+		op      admissionv1.Operation
+		allowed bool
+		warning string
+		reason  string
+	}{
+		{
+			name:    "ok",
+			policy:  newFilterPolicy("demo", `{"hostName":"doraemon"}`),
+			op:      admissionv1.Create,
+			allowed: true,
+		},
+		{
+			name:   "unknown field",
+			policy: newFilterPolicy("demo", `{"hostName":"doraemon","unknown":"x"}`),
+			op:     admissionv1.Update,
+			reason: "unknown field",
+		},
+		{
+			name:   "unknown plugin",
+			policy: newFilterPolicy("unknown", `{}`),
+			op:     admissionv1.Create,
+			reason: "unknown http filter: unknown",
+		},
+		{
+			name:    "skip delete",
+			policy:  newFilterPolicy("unknown", `{}`),
+			op:      admissionv1.Delete,
+			allowed: true,
+		},
+		{
+			name: "target not found",
+			policy: func() *mosniov1.FilterPolicy {
+				p := newFilterPolicy("demo", `{"hostName":"doraemon"}`)
+				p.Spec.TargetRef.Name = "vs2"
+				return p
+			}(),
+			op:      admissionv1.Create,
+			allowed: true,
+			warning: "target VirtualService default/vs2 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), newRequest(t, "FilterPolicy", tt.op, tt.policy))
+			assert.Equal(t, tt.allowed, resp.Allowed)
+			if tt.reason != "" {
+				assert.Contains(t, resp.Result.Message, tt.reason)
+			}
+			if tt.warning != "" {
+				require.Len(t, resp.Warnings, 1)
+				assert.Contains(t, resp.Warnings[0], tt.warning)
+			} else {
+				assert.Empty(t, resp.Warnings)
+			}
+		})
+	}
+}
+
+func TestValidatorConsumer(t *testing.T) {
+	existing := newConsumer("rick", `{"credentials":[{"key":"old"},{"key":"new"}]}`)
+	existing.Spec.Name = "rick"
+	v := NewValidator(&fakeResourceManager{
+		consumers: []mosniov1.Consumer{*existing},
+	})
+
+	tests := []struct {
+		name     string
+		consumer *mosniov1.Consumer
+		reason   string
+	}{
+		{
+			name:     "ok",
+			consumer: newConsumer("morty", `{"key":"morty"}`),
+		},
+		{
+			name:     "update itself",
+			consumer: newConsumer("rick", `{"key":"new"}`),
+		},
+		{
+			name:     "invalid",
+			consumer: newConsumer("morty", `{}`),
+			reason:   "invalid config for filter keyAuth",
+		},
+		{
+			name:     "index collision",
+			consumer: newConsumer("morty", `{"key":"new"}`),
+			reason:   "filter keyAuth: index new collides with consumer default/rick",
+		},
+		{
+			name: "name collision",
+			consumer: func() *mosniov1.Consumer {
+				c := newConsumer("morty", `{"key":"morty"}`)
+				c.Spec.Name = "rick"
+				return c
+			}(),
+			reason: "duplicate with another consumer default/rick",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), newRequest(t, "Consumer", admissionv1.Create, tt.consumer))
+			if tt.reason == "" {
+				assert.True(t, resp.Allowed, resp.Result)
+			} else {
+				assert.False(t, resp.Allowed)
+				assert.Contains(t, resp.Result.Message, tt.reason)
+			}
+		})
+	}
+}
+
+func TestValidatorBadRequest(t *testing.T) {
+	v := NewValidator(&fakeResourceManager{})
+	req := newRequest(t, "DynamicConfig", admissionv1.Create, nil)
+	req.Object.Raw = []byte("{")
+	resp := v.Handle(context.Background(), req)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(400), resp.Result.Code)
+}
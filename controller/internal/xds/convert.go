@@ -0,0 +1,203 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	// register the types used in the generated configuration
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	istioapi "istio.io/api/networking/v1alpha3"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	"mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/controller/internal/log"
+)
+
+type routeKey struct {
+	// namespace is the namespace of the EnvoyFilter. The patches from different namespaces target the
+	// gateways in different namespaces, so they are not merged into the same route.
+	namespace string
+	vhost     string
+	route     string
+}
+
+// resources are the xDS resources converted from the EnvoyFilters
+type resources struct {
+	extensionConfigs map[string]*corev3.TypedExtensionConfig
+	routePatches     map[routeKey][]*routev3.Route
+	// the number of the patches which can't be converted to xDS resources
+	unsupported int
+}
+
+func unmarshalStruct(value *structpb.Struct, msg proto.Message) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(data, msg)
+}
+
+// convertEnvoyFilters converts the EnvoyFilters to xDS resources. The EnvoyFilters are applied in the order
+// of namespace and name, so the result is stable. If the namespace is given, like Istio, only the EnvoyFilters
+// in this namespace and the root namespace are applied.
+// Only the patches which add the HTTP filters and merge the routes are supported. The HTTP filters are
+// served via ECDS, and the route patches are applied to the route configurations served via RDS.
+func convertEnvoyFilters(efs []*istiov1a3.EnvoyFilter, namespace string) (*resources, error) {
+	sort.Slice(efs, func(i, j int) bool {
+		if efs[i].Namespace != efs[j].Namespace {
+			return efs[i].Namespace < efs[j].Namespace
+		}
+		return efs[i].Name < efs[j].Name
+	})
+
+	res := &resources{
+		extensionConfigs: make(map[string]*corev3.TypedExtensionConfig),
+		routePatches:     make(map[routeKey][]*routev3.Route),
+	}
+	// The filter configuration added via EXTENSION_CONFIG takes precedence over the placeholder
+	// in the HTTP_FILTER patch, so they are handled after all the HTTP_FILTER patches.
+	var extensionConfigPatches []*istioapi.EnvoyFilter_EnvoyConfigObjectPatch
+	for _, ef := range efs {
+		if namespace != "" && ef.Namespace != namespace && ef.Namespace != config.RootNamespace() {
+			continue
+		}
+		for _, cp := range ef.Spec.ConfigPatches {
+			switch cp.ApplyTo {
+			case istioapi.EnvoyFilter_EXTENSION_CONFIG:
+				extensionConfigPatches = append(extensionConfigPatches, cp)
+
+			case istioapi.EnvoyFilter_HTTP_FILTER:
+				filter := &hcmv3.HttpFilter{}
+				if err := unmarshalStruct(cp.Patch.GetValue(), filter); err != nil {
+					return nil, fmt.Errorf("failed to convert HTTP filter in EnvoyFilter %s/%s: %w",
+						ef.Namespace, ef.Name, err)
+				}
+				if filter.GetTypedConfig() == nil {
+					// the filter is already configured via ECDS
+					continue
+				}
+				res.extensionConfigs[filter.Name] = &corev3.TypedExtensionConfig{
+					Name:        filter.Name,
+					TypedConfig: filter.GetTypedConfig(),
+				}
+
+			case istioapi.EnvoyFilter_HTTP_ROUTE:
+				vhost := cp.Match.GetRouteConfiguration().GetVhost()
+				if cp.Patch.GetOperation() != istioapi.EnvoyFilter_Patch_MERGE || vhost.GetRoute().GetName() == "" {
+					res.unsupported++
+					continue
+				}
+
+				route := &routev3.Route{}
+				if err := unmarshalStruct(cp.Patch.GetValue(), route); err != nil {
+					return nil, fmt.Errorf("failed to convert route patch in EnvoyFilter %s/%s: %w",
+						ef.Namespace, ef.Name, err)
+				}
+				key := routeKey{namespace: ef.Namespace, vhost: vhost.GetName(), route: vhost.GetRoute().GetName()}
+				res.routePatches[key] = append(res.routePatches[key], route)
+
+			default:
+				res.unsupported++
+			}
+		}
+	}
+
+	for _, cp := range extensionConfigPatches {
+		cfg := &corev3.TypedExtensionConfig{}
+		if err := unmarshalStruct(cp.Patch.GetValue(), cfg); err != nil {
+			return nil, fmt.Errorf("failed to convert extension config: %w", err)
+		}
+		res.extensionConfigs[cfg.Name] = cfg
+	}
+
+	return res, nil
+}
+
+// applyRoutePatches merges the route patches into the copy of the given route configurations.
+// Like the MERGE operation in EnvoyFilter, the map fields like typed_per_filter_config are merged by key.
+// If a route is patched from multiple namespaces, only the patches from the first namespace are applied.
+func applyRoutePatches(bases []*routev3.RouteConfiguration, patches map[routeKey][]*routev3.Route) []*routev3.RouteConfiguration {
+	namespaces := make(map[routeKey][]string)
+	for key := range patches {
+		k := routeKey{vhost: key.vhost, route: key.route}
+		namespaces[k] = append(namespaces[k], key.namespace)
+	}
+
+	rcs := make([]*routev3.RouteConfiguration, 0, len(bases))
+	for _, base := range bases {
+		rc := proto.Clone(base).(*routev3.RouteConfiguration)
+		for _, vhost := range rc.VirtualHosts {
+			for _, route := range vhost.Routes {
+				nss := namespaces[routeKey{vhost: vhost.Name, route: route.Name}]
+				if len(nss) == 0 {
+					continue
+				}
+				sort.Strings(nss)
+				if len(nss) > 1 {
+					log.Errorf("route %s of virtual host %s is patched from namespaces %v, only the patches from %s "+
+						"are applied. Set the namespace of the xDS output to serve the gateways in a single namespace",
+						route.Name, vhost.Name, nss, nss[0])
+				}
+				for _, patch := range patches[routeKey{namespace: nss[0], vhost: vhost.Name, route: route.Name}] {
+					proto.Merge(route, patch)
+				}
+			}
+		}
+		rcs = append(rcs, rc)
+	}
+	return rcs
+}
+
+// LoadRouteConfigurations loads the route configurations from a YAML file, which contains a list of
+// envoy.config.route.v3.RouteConfiguration.
+func LoadRouteConfigurations(path string) ([]*routev3.RouteConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse route configurations in %s: %w", path, err)
+	}
+
+	rcs := make([]*routev3.RouteConfiguration, 0, len(items))
+	names := make(map[string]struct{}, len(items))
+	for i, item := range items {
+		rc := &routev3.RouteConfiguration{}
+		if err := protojson.Unmarshal(item, rc); err != nil {
+			return nil, fmt.Errorf("invalid route configuration %d in %s: %w", i, path, err)
+		}
+		if rc.Name == "" {
+			return nil, fmt.Errorf("route configuration %d in %s has no name", i, path)
+		}
+		if _, ok := names[rc.Name]; ok {
+			return nil, fmt.Errorf("duplicate route configuration %s in %s", rc.Name, path)
+		}
+		names[rc.Name] = struct{}{}
+		rcs = append(rcs, rc)
+	}
+	return rcs, nil
+}
@@ -0,0 +1,233 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xds serves the configuration generated by HTNN to Envoy directly as an xDS server,
+// so that HTNN can be used without Istio's EnvoyFilter.
+package xds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	extensionservice "github.com/envoyproxy/go-control-plane/envoy/service/extension/v3"
+	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"google.golang.org/grpc"
+	istioapi "istio.io/api/networking/v1alpha3"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/controller/internal/log"
+	"mosn.io/htnn/controller/pkg/component"
+)
+
+const (
+	creatorFilterPolicy  = "FilterPolicy"
+	creatorConsumer      = "Consumer"
+	creatorDynamicConfig = "DynamicConfig"
+)
+
+// Options configures the xDS output
+type Options struct {
+	// Address is the address the xDS server listens to
+	Address string
+	// RouteConfigurations are the base route configurations served via RDS. The route patches generated
+	// by HTNN are applied to the routes which match the virtual host name and the route name.
+	RouteConfigurations []*routev3.RouteConfiguration
+	// Namespace is the namespace of the gateways served by the Envoy. Like Istio, only the configuration
+	// generated for this namespace and the root namespace is served. All the namespaces are served if it's empty.
+	Namespace string
+}
+
+// Output is a component.Output which serves the generated configuration via xDS, instead of writing
+// EnvoyFilters. The HTTP filters are served via ECDS and the routes are served via RDS. They can be
+// fetched via ADS or the dedicated services.
+type Output struct {
+	address   string
+	routes    []*routev3.RouteConfiguration
+	namespace string
+
+	lock sync.Mutex
+	// creator -> the latest generated EnvoyFilters
+	envoyFilters map[string]map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter
+
+	extensionConfigCache *cache.LinearCache
+	routeCache           *cache.LinearCache
+	cache                cache.Cache
+}
+
+// NewOutput creates an Output. The xDS server is started when the Output is added to the manager.
+func NewOutput(opts Options) *Output {
+	o := &Output{
+		address:              opts.Address,
+		routes:               opts.RouteConfigurations,
+		namespace:            opts.Namespace,
+		envoyFilters:         make(map[string]map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter),
+		extensionConfigCache: cache.NewLinearCache(resource.ExtensionConfigType),
+		routeCache:           cache.NewLinearCache(resource.RouteType),
+	}
+	o.cache = &cache.MuxCache{
+		Classify: func(r *cache.Request) string {
+			return r.GetTypeUrl()
+		},
+		ClassifyDelta: func(r *cache.DeltaRequest) string {
+			return r.GetTypeUrl()
+		},
+		Caches: map[string]cache.Cache{
+			resource.ExtensionConfigType: o.extensionConfigCache,
+			resource.RouteType:           o.routeCache,
+		},
+	}
+	// serve the base routes before the first reconciliation
+	o.routeCache.SetResources(routeResources(applyRoutePatches(o.routes, nil)))
+	return o
+}
+
+func routeResources(rcs []*routev3.RouteConfiguration) map[string]types.Resource {
+	res := make(map[string]types.Resource, len(rcs))
+	for _, rc := range rcs {
+		res[rc.Name] = rc
+	}
+	return res
+}
+
+func (o *Output) update(creator string, efs map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	var all []*istiov1a3.EnvoyFilter
+	for c, generated := range o.envoyFilters {
+		if c == creator {
+			continue
+		}
+		for _, ef := range generated {
+			all = append(all, ef)
+		}
+	}
+	for _, ef := range efs {
+		all = append(all, ef)
+	}
+
+	res, err := convertEnvoyFilters(all, o.namespace)
+	if err != nil {
+		// keep serving the previous configuration
+		return err
+	}
+	o.envoyFilters[creator] = efs
+
+	if res.unsupported > 0 {
+		// The FilterPolicies which configure the listener are rejected, so it's unexpected
+		log.Errorf("ignore %d patches which are not supported by the xDS output, like the listener patches",
+			res.unsupported)
+	}
+
+	extensionConfigs := make(map[string]types.Resource, len(res.extensionConfigs))
+	for name, cfg := range res.extensionConfigs {
+		extensionConfigs[name] = cfg
+	}
+	o.extensionConfigCache.SetResources(extensionConfigs)
+	o.routeCache.SetResources(routeResources(applyRoutePatches(o.routes, res.routePatches)))
+	return nil
+}
+
+func (o *Output) FromFilterPolicy(_ context.Context, generatedEnvoyFilters map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
+	return o.update(creatorFilterPolicy, generatedEnvoyFilters)
+}
+
+func (o *Output) FromConsumer(_ context.Context, ef *istiov1a3.EnvoyFilter) error {
+	return o.update(creatorConsumer, map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter{
+		{Namespace: ef.Namespace, Name: ef.Name}: ef,
+	})
+}
+
+func (o *Output) FromDynamicConfig(_ context.Context, efs map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter) error {
+	// The internal listener which runs the DynamicConfig filters is configured in the Envoy, so only the
+	// filters are served
+	served := make(map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter, len(efs))
+	for key, ef := range efs {
+		ef = ef.DeepCopy()
+		patches := ef.Spec.ConfigPatches[:0]
+		for _, cp := range ef.Spec.ConfigPatches {
+			if cp.ApplyTo != istioapi.EnvoyFilter_LISTENER {
+				patches = append(patches, cp)
+			}
+		}
+		ef.Spec.ConfigPatches = patches
+		served[key] = ef
+	}
+	return o.update(creatorDynamicConfig, served)
+}
+
+var _ component.PluginChecker = &Output{}
+
+// CheckPlugin rejects the plugins which configure the listener or the network filters, as they are not served
+// by the xDS output
+func (o *Output) CheckPlugin(name string) error {
+	p := plugins.LoadPluginType(name)
+	if p == nil {
+		return nil
+	}
+	switch p.Order().Position {
+	case plugins.OrderPositionListener, plugins.OrderPositionNetwork:
+		return fmt.Errorf("plugin %s configures the listener, which is not supported by the xDS output", name)
+	}
+	return nil
+}
+
+func (o *Output) FromServiceRegistry(_ context.Context, serviceEntries map[string]*istioapi.ServiceEntry) {
+	if len(serviceEntries) > 0 {
+		log.Infof("ServiceRegistry is not supported by the xDS output, ignore %d ServiceEntries", len(serviceEntries))
+	}
+}
+
+// Start serves xDS until the ctx is done
+func (o *Output) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", o.address)
+	if err != nil {
+		return err
+	}
+	return o.serve(ctx, lis)
+}
+
+func (o *Output) serve(ctx context.Context, lis net.Listener) error {
+	srv := server.NewServer(ctx, o.cache, nil)
+	grpcServer := grpc.NewServer()
+	discoverygrpc.RegisterAggregatedDiscoveryServiceServer(grpcServer, srv)
+	extensionservice.RegisterExtensionConfigDiscoveryServiceServer(grpcServer, srv)
+	routeservice.RegisterRouteDiscoveryServiceServer(grpcServer, srv)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	log.Infof("serving xDS on %s", lis.Addr())
+	if err := grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false as every replica serves xDS
+func (o *Output) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,247 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	"mosn.io/htnn/controller/internal/istio"
+	"mosn.io/htnn/controller/pkg/component"
+	_ "mosn.io/htnn/controller/plugins" // register plugins
+)
+
+func loadEnvoyFilters(t *testing.T) map[component.EnvoyFilterKey]*istiov1a3.EnvoyFilter {
+	data, err := os.ReadFile("testdata/envoyfilters.yaml")
+	require.NoError(t, err)
+	var efs []*istiov1a3.EnvoyFilter
+	require.NoError(t, yaml.Unmarshal(data, &efs))
+
+	res := istio.DefaultEnvoyFilters()
+	for _, ef := range efs {
+		res[component.EnvoyFilterKey{Namespace: ef.Namespace, Name: ef.Name}] = ef
+	}
+	return res
+}
+
+func TestConvertEnvoyFilters(t *testing.T) {
+	efs := loadEnvoyFilters(t)
+	list := make([]*istiov1a3.EnvoyFilter, 0, len(efs))
+	for _, ef := range efs {
+		list = append(list, ef)
+	}
+
+	res, err := convertEnvoyFilters(list, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.unsupported)
+
+	// the placeholder of the HTTP filters
+	assert.NotNil(t, res.extensionConfigs["htnn.filters.http.golang"])
+	assert.NotNil(t, res.extensionConfigs["htnn.filters.http.localRatelimit"])
+	// the filter configured via EXTENSION_CONFIG
+	cfg := res.extensionConfigs["htnn-default-0.0.0.0_80-golang-filter"]
+	require.NotNil(t, cfg)
+	assert.Contains(t, cfg.TypedConfig.TypeUrl, "golang.v3alpha.Config")
+
+	rcs, err := LoadRouteConfigurations("testdata/routes.yaml")
+	require.NoError(t, err)
+	patched := applyRoutePatches(rcs, res.routePatches)
+	require.Len(t, patched, 1)
+
+	routes := patched[0].VirtualHosts[0].Routes
+	assert.Len(t, routes[0].TypedPerFilterConfig, 2)
+	assert.Equal(t, "backend", routes[0].GetRoute().GetCluster())
+	md := routes[0].Metadata.FilterMetadata
+	assert.Equal(t, "default/policy", md["htnn"].Fields["policy"].GetStringValue())
+	assert.Equal(t, "value", md["other"].Fields["key"].GetStringValue())
+	assert.Empty(t, routes[1].TypedPerFilterConfig)
+	// the base is not modified
+	assert.Empty(t, rcs[0].VirtualHosts[0].Routes[0].TypedPerFilterConfig)
+}
+
+func routePatchEnvoyFilter(t *testing.T, ns string) *istiov1a3.EnvoyFilter {
+	ef := &istiov1a3.EnvoyFilter{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+metadata:
+  name: htnn-h-example.com
+  namespace: `+ns+`
+spec:
+  configPatches:
+  - applyTo: HTTP_ROUTE
+    match:
+      routeConfiguration:
+        vhost:
+          name: example.com:80
+          route:
+            name: policy
+    patch:
+      operation: MERGE
+      value:
+        metadata:
+          filterMetadata:
+            htnn:
+              namespace: `+ns+`
+`), ef))
+	return ef
+}
+
+func TestRoutePatchNamespace(t *testing.T) {
+	rcs, err := LoadRouteConfigurations("testdata/routes.yaml")
+	require.NoError(t, err)
+	efs := []*istiov1a3.EnvoyFilter{routePatchEnvoyFilter(t, "b"), routePatchEnvoyFilter(t, "a")}
+
+	patchedNamespace := func(res *resources) string {
+		patched := applyRoutePatches(rcs, res.routePatches)
+		md := patched[0].VirtualHosts[0].Routes[0].Metadata.FilterMetadata
+		return md["htnn"].Fields["namespace"].GetStringValue()
+	}
+
+	// the patches from different namespaces are not merged
+	res, err := convertEnvoyFilters(efs, "")
+	require.NoError(t, err)
+	assert.Len(t, res.routePatches, 2)
+	assert.Equal(t, "a", patchedNamespace(res))
+
+	// only the configuration of the given namespace is served
+	res, err = convertEnvoyFilters(efs, "b")
+	require.NoError(t, err)
+	assert.Len(t, res.routePatches, 1)
+	assert.Equal(t, "b", patchedNamespace(res))
+}
+
+func TestCheckPlugin(t *testing.T) {
+	o := NewOutput(Options{})
+	assert.NoError(t, o.CheckPlugin("localRatelimit"))
+	assert.NoError(t, o.CheckPlugin("unknown"))
+	assert.ErrorContains(t, o.CheckPlugin("listenerPatch"), "not supported by the xDS output")
+	assert.ErrorContains(t, o.CheckPlugin("networkRBAC"), "not supported by the xDS output")
+}
+
+func TestLoadRouteConfigurations(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "no name",
+			input: "- virtual_hosts: []",
+			err:   "has no name",
+		},
+		{
+			name:  "duplicate",
+			input: "- name: a\n- name: a",
+			err:   "duplicate route configuration a",
+		},
+		{
+			name:  "unknown field",
+			input: "- name: a\n  unknown: 1",
+			err:   "invalid route configuration 0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := dir + "/routes.yaml"
+			require.NoError(t, os.WriteFile(path, []byte(tc.input), 0644))
+			_, err := LoadRouteConfigurations(path)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestOutputServe(t *testing.T) {
+	rcs, err := LoadRouteConfigurations("testdata/routes.yaml")
+	require.NoError(t, err)
+	o := NewOutput(Options{RouteConfigurations: rcs})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = o.serve(ctx, lis)
+	}()
+
+	require.NoError(t, o.FromFilterPolicy(ctx, loadEnvoyFilters(t)))
+	o.FromServiceRegistry(ctx, nil)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	streamCtx, streamCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer streamCancel()
+	stream, err := discoverygrpc.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(streamCtx)
+	require.NoError(t, err)
+
+	node := &corev3.Node{Id: "test"}
+	require.NoError(t, stream.Send(&discoverygrpc.DiscoveryRequest{
+		Node:          node,
+		TypeUrl:       resource.ExtensionConfigType,
+		ResourceNames: []string{"htnn-default-0.0.0.0_80-golang-filter"},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.Resources, 1)
+	cfg := &corev3.TypedExtensionConfig{}
+	require.NoError(t, resp.Resources[0].UnmarshalTo(cfg))
+	assert.Equal(t, "htnn-default-0.0.0.0_80-golang-filter", cfg.Name)
+
+	require.NoError(t, stream.Send(&discoverygrpc.DiscoveryRequest{
+		Node:          node,
+		TypeUrl:       resource.RouteType,
+		ResourceNames: []string{"http.80"},
+	}))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.Resources, 1)
+	rc := &routev3.RouteConfiguration{}
+	require.NoError(t, resp.Resources[0].UnmarshalTo(rc))
+	assert.Len(t, rc.VirtualHosts[0].Routes[0].TypedPerFilterConfig, 2)
+
+	// the invalid configuration is rejected and the previous one is kept
+	ef := &istiov1a3.EnvoyFilter{}
+	ef.Namespace = "default"
+	ef.Name = "invalid"
+	require.NoError(t, yaml.Unmarshal([]byte(`
+spec:
+  configPatches:
+  - applyTo: EXTENSION_CONFIG
+    patch:
+      operation: ADD
+      value:
+        name: invalid
+        typed_config:
+          '@type': type.googleapis.com/unknown.Type
+`), ef))
+	require.Error(t, o.FromConsumer(ctx, ef))
+	_, ok := o.extensionConfigCache.GetResources()["invalid"]
+	assert.False(t, ok)
+	assert.True(t, len(o.extensionConfigCache.GetResources()) > 1)
+}
@@ -38,6 +38,13 @@ type Output interface {
 	FromDynamicConfig(ctx context.Context, envoyFilters map[EnvoyFilterKey]*istiov1a3.EnvoyFilter) error
 }
 
+// PluginChecker is an optional interface of Output. It's implemented by the Output which can't serve
+// all kinds of plugins, so that the FilterPolicy using the unsupported plugins can be rejected.
+type PluginChecker interface {
+	// CheckPlugin returns an error if the plugin can't be served by the output
+	CheckPlugin(name string) error
+}
+
 type ResourceManager interface {
 	Get(ctx context.Context, key client.ObjectKey, out client.Object) error
 	List(ctx context.Context, list client.ObjectList) error
@@ -50,6 +57,7 @@ type ResourceMeta interface {
 	GetNamespace() string
 	GetName() string
 	GetAnnotations() map[string]string
+	GetLabels() map[string]string
 }
 
 type CtrlLogger interface {
@@ -64,8 +72,46 @@ type Distribution interface {
 	Record(value float64)
 }
 
+type Sum interface {
+	// Increment records a value of 1 for the current measure.
+	Increment()
+}
+
+type Gauge interface {
+	// Record sets the current value of the measure.
+	Record(value float64)
+}
+
+type LabeledSum interface {
+	// With returns the Sum with the given label values, in the order of the labels.
+	With(labelValues ...string) Sum
+}
+
+type LabeledGauge interface {
+	// With returns the Gauge with the given label values, in the order of the labels.
+	With(labelValues ...string) Gauge
+}
+
 type MetricProvider interface {
 	// NewDistribution creates a new Metric type called Distribution. This means that the
 	// data collected by the Metric will be collected and exported as a histogram, with the specified bounds.
 	NewDistribution(name, description string, bounds []float64) Distribution
 }
+
+// SumProvider can be implemented by the MetricProvider to provide the Sum metrics.
+// The Sum metrics are not collected if it's not implemented.
+type SumProvider interface {
+	// NewSum creates a new Metric type called Sum. This means that the data collected by the Metric
+	// will be summed and exported as a counter.
+	NewSum(name, description string) Sum
+}
+
+// LabeledMetricProvider can be implemented by the MetricProvider to provide the metrics with labels.
+// The labeled metrics are not collected if it's not implemented.
+type LabeledMetricProvider interface {
+	// NewLabeledSum creates a Sum which has the given labels.
+	NewLabeledSum(name, description string, labels []string) LabeledSum
+	// NewLabeledGauge creates a new Metric type called Gauge, which has the given labels.
+	// The data collected by the Metric is the latest recorded value.
+	NewLabeledGauge(name, description string, labels []string) LabeledGauge
+}
@@ -18,8 +18,10 @@ package istio
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"mosn.io/htnn/controller/internal/config"
@@ -27,6 +29,7 @@ import (
 	"mosn.io/htnn/controller/internal/log"
 	"mosn.io/htnn/controller/internal/metrics"
 	"mosn.io/htnn/controller/internal/registry"
+	"mosn.io/htnn/controller/internal/webhook"
 	"mosn.io/htnn/controller/pkg/component"
 )
 
@@ -76,10 +79,65 @@ type DynamicConfigReconciler interface {
 }
 
 func NewDynamicConfigReconciler(output component.Output, manager component.ResourceManager) DynamicConfigReconciler {
-	return &controller.DynamicConfigReconciler{
+	r := &controller.DynamicConfigReconciler{
 		Output:          output,
 		ResourceManager: manager,
 	}
+	if kubeClient != nil {
+		r.RevisionStore = controller.NewConfigMapRevisionStore(kubeClient)
+	}
+	return r
+}
+
+// DynamicConfigStatusPath is the path to receive the apply status of DynamicConfig reported by the data plane
+const DynamicConfigStatusPath = "/htnn/dynamicconfig/status"
+
+// DynamicConfigStatusHandler collects the apply status of DynamicConfig reported by the data plane
+type DynamicConfigStatusHandler interface {
+	http.Handler
+	// Start shares the collected statuses and writes the Applied condition of DynamicConfig
+	// periodically until the ctx is done. It should run in every replica.
+	Start(ctx context.Context) error
+}
+
+// DynamicConfigStatusOptions configures the DynamicConfigStatusHandler
+type DynamicConfigStatusOptions = controller.DynamicConfigStatusOptions
+
+// NewDynamicConfigStatusHandler returns a handler which collects the apply status of DynamicConfig reported by the data plane
+// and updates the Applied condition of DynamicConfig accordingly. The reporters are authenticated by their service
+// account tokens, and the status is only written when opts.IsLeader returns true.
+func NewDynamicConfigStatusHandler(manager component.ResourceManager, opts DynamicConfigStatusOptions) DynamicConfigStatusHandler {
+	return controller.NewDynamicConfigStatusCollector(manager, opts)
+}
+
+// ValidationWebhookPath is the path to serve the validating webhook of HTNN resources
+const ValidationWebhookPath = webhook.ValidationPath
+
+// NewValidationWebhookHandler returns a handler which validates HTNN resources strictly before they are persisted.
+func NewValidationWebhookHandler(manager component.ResourceManager) http.Handler {
+	return webhook.NewHandler(manager)
+}
+
+// ValidationWebhookSelfSignedCert returns true if the validating webhook should be served by a dedicated server
+// with the self-signed certificate.
+func ValidationWebhookSelfSignedCert() bool {
+	return config.WebhookSelfSignedCert()
+}
+
+// ServeValidationWebhookWithSelfSignedCert serves the validating webhook with a dedicated server until the ctx is done.
+// The caBundle of the ValidatingWebhookConfiguration is patched with the self-signed certificate.
+func ServeValidationWebhookWithSelfSignedCert(ctx context.Context, client kubernetes.Interface, handler http.Handler) error {
+	return webhook.ServeWithSelfSignedCert(ctx, client, map[string]http.Handler{
+		webhook.ValidationPath: handler,
+	})
+}
+
+var kubeClient kubernetes.Interface
+
+// SetKubeClient sets the client to store the revisions of DynamicConfig in ConfigMaps.
+// It should be called before creating the reconcilers. Rolling back DynamicConfig is not supported without it.
+func SetKubeClient(client kubernetes.Interface) {
+	kubeClient = client
 }
 
 func SetLogger(logger component.CtrlLogger) {
@@ -24,6 +24,9 @@ func (f *fakeServiceEntryStore) Delete(service string) {
 func (f *fakeServiceEntryStore) Update(service string, se *ServiceEntryWrapper) {
 }
 
+func (f *fakeServiceEntryStore) ReportSyncError(err error) {
+}
+
 func FakeServiceEntryStore() *fakeServiceEntryStore {
 	return &fakeServiceEntryStore{}
 }
@@ -70,6 +70,9 @@ type ServiceEntryWrapper struct {
 type ServiceEntryStore interface {
 	Update(service string, se *ServiceEntryWrapper)
 	Delete(service string)
+	// ReportSyncError reports the error happened when syncing services from the registry.
+	// The registry should retry by itself after reporting the error.
+	ReportSyncError(err error)
 }
 
 // Registry is the interface that all registries must implement
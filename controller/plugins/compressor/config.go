@@ -0,0 +1,84 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compressor
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/compressor"
+)
+
+func init() {
+	plugins.RegisterPlugin(compressor.GzipName, &gzipPlugin{})
+	plugins.RegisterPlugin(compressor.BrotliName, &brotliPlugin{})
+	plugins.RegisterPlugin(compressor.ZstdName, &zstdPlugin{})
+}
+
+type plugin struct {
+}
+
+func (p *plugin) ToRouteConfig(config map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		// v3.CompressorOverrides -> v3.CompressorPerRoute
+		"overrides": config,
+	}
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.compressor.v3.CompressorPerRoute"
+}
+
+func placeholder(name string, libraryTypeURL string) map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.compressor.v3.Compressor",
+			"compressorLibrary": map[string]interface{}{
+				"name": name,
+				"typedConfig": map[string]interface{}{
+					"@type": libraryTypeURL,
+				},
+			},
+		},
+	}
+}
+
+type gzipPlugin struct {
+	plugin
+	compressor.GzipPlugin
+}
+
+func (p *gzipPlugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return placeholder(compressor.GzipName,
+		"type.googleapis.com/envoy.extensions.compression.gzip.compressor.v3.Gzip")
+}
+
+type brotliPlugin struct {
+	plugin
+	compressor.BrotliPlugin
+}
+
+func (p *brotliPlugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return placeholder(compressor.BrotliName,
+		"type.googleapis.com/envoy.extensions.compression.brotli.compressor.v3.Brotli")
+}
+
+type zstdPlugin struct {
+	plugin
+	compressor.ZstdPlugin
+}
+
+func (p *zstdPlugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return placeholder(compressor.ZstdName,
+		"type.googleapis.com/envoy.extensions.compression.zstd.compressor.v3.Zstd")
+}
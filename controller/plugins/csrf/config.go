@@ -0,0 +1,45 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csrf
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/csrf"
+)
+
+func init() {
+	plugins.RegisterPlugin(csrf.Name, &plugin{})
+}
+
+type plugin struct {
+	csrf.Plugin
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.csrf.v3.CsrfPolicy"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": p.ConfigTypeURL(),
+			"filterEnabled": map[string]interface{}{
+				"defaultValue": map[string]interface{}{
+					"numerator": 100,
+				},
+			},
+		},
+	}
+}
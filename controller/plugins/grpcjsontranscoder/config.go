@@ -0,0 +1,43 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcjsontranscoder
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/grpcjsontranscoder"
+)
+
+func init() {
+	plugins.RegisterPlugin(grpcjsontranscoder.Name, &plugin{})
+}
+
+type plugin struct {
+	grpcjsontranscoder.Plugin
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.grpc_json_transcoder.v3.GrpcJsonTranscoder"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": p.ConfigTypeURL(),
+			// An empty descriptor set to satisfy the Envoy validation. The real one is provided
+			// in the route.
+			"protoDescriptorBin": "",
+		},
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcweb
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/grpcweb"
+)
+
+func init() {
+	plugins.RegisterPlugin(grpcweb.Name, &plugin{})
+}
+
+type plugin struct {
+	grpcweb.Plugin
+}
+
+func (p *plugin) ToRouteConfig(_ map[string]interface{}) map[string]interface{} {
+	// grpc_web doesn't have per-route configuration, so we use the generic FilterConfig to enable it
+	return map[string]interface{}{
+		"disabled": false,
+	}
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.config.route.v3.FilterConfig"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.grpc_web.v3.GrpcWeb",
+		},
+	}
+}
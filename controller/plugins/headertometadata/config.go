@@ -0,0 +1,49 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package headertometadata
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/headertometadata"
+)
+
+func init() {
+	plugins.RegisterPlugin(headertometadata.Name, &plugin{})
+}
+
+type plugin struct {
+	headertometadata.Plugin
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.header_to_metadata.v3.Config"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": p.ConfigTypeURL(),
+			// Envoy requires at least one rule in the filter level configuration
+			"requestRules": []interface{}{
+				map[string]interface{}{
+					"header": "x-htnn-placeholder",
+					"onHeaderPresent": map[string]interface{}{
+						"key": "placeholder",
+					},
+				},
+			},
+		},
+	}
+}
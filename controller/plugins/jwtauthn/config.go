@@ -0,0 +1,157 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtauthn
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/jwtauthn"
+)
+
+func init() {
+	plugins.RegisterPlugin(jwtauthn.Name, &plugin{})
+}
+
+// The providers can only be configured in the HTTP filter, so we deliver the providers of all the
+// routes in the same HTTP filter via ECDS. The providers and the requirement of each configuration
+// are renamed with the hash of the configuration to avoid conflict. Each route refers to its
+// requirement by name.
+type plugin struct {
+	jwtauthn.Plugin
+}
+
+const filterConfigTypeURL = "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.JwtAuthentication"
+
+func requirementName(config map[string]interface{}) string {
+	// The keys of map are sorted when marshaling, so the result is stable
+	b, _ := json.Marshal(config)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+func providerName(prefix string, name string) string {
+	return prefix + "." + name
+}
+
+// renameProviders rewrites the provider names referred in the requirement
+func renameProviders(prefix string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, field := range v {
+			if name, ok := field.(string); ok && k == "providerName" {
+				res[k] = providerName(prefix, name)
+			} else {
+				res[k] = renameProviders(prefix, field)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = renameProviders(prefix, item)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+func (p *plugin) FilterConfigTypeURL() string {
+	return filterConfigTypeURL
+}
+
+func (p *plugin) ToFilterConfig(config map[string]interface{}) map[string]interface{} {
+	name := requirementName(config)
+
+	rawProviders, _ := config["providers"].(map[string]interface{})
+	providers := make(map[string]interface{}, len(rawProviders))
+	names := make([]string, 0, len(rawProviders))
+	for k, v := range rawProviders {
+		providers[providerName(name, k)] = v
+		names = append(names, providerName(name, k))
+	}
+	sort.Strings(names)
+
+	var requirement interface{}
+	requirementMap, _ := config["requirementMap"].(map[string]interface{})
+	if len(requirementMap) > 0 {
+		// The validation only allows one requirement. Choose the first one in order to be deterministic
+		// in case the validation is bypassed.
+		keys := make([]string, 0, len(requirementMap))
+		for k := range requirementMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		requirement = renameProviders(name, requirementMap[keys[0]])
+	}
+	if requirement == nil {
+		// Require any of the providers by default
+		if len(names) == 1 {
+			requirement = map[string]interface{}{
+				"providerName": names[0],
+			}
+		} else {
+			requirements := make([]interface{}, len(names))
+			for i, n := range names {
+				requirements[i] = map[string]interface{}{
+					"providerName": n,
+				}
+			}
+			requirement = map[string]interface{}{
+				"requiresAny": map[string]interface{}{
+					"requirements": requirements,
+				},
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"providers": providers,
+		"requirementMap": map[string]interface{}{
+			name: requirement,
+		},
+	}
+}
+
+func (p *plugin) ToRouteConfig(config map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"requirementName": requirementName(config),
+	}
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.PerRouteConfig"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"config_discovery": map[string]interface{}{
+			"config_source": map[string]interface{}{
+				"ads": map[string]interface{}{},
+			},
+			// Use an empty configuration before any FilterPolicy configures this plugin
+			"default_config": map[string]interface{}{
+				"@type": filterConfigTypeURL,
+			},
+			"apply_default_config_without_warming": true,
+			"type_urls":                            []interface{}{filterConfigTypeURL},
+		},
+	}
+}
@@ -17,13 +17,21 @@ package plugins
 import (
 	_ "mosn.io/htnn/controller/plugins/bandwidthlimit"
 	_ "mosn.io/htnn/controller/plugins/buffer"
+	_ "mosn.io/htnn/controller/plugins/compressor"
 	_ "mosn.io/htnn/controller/plugins/cors"
+	_ "mosn.io/htnn/controller/plugins/csrf"
 	_ "mosn.io/htnn/controller/plugins/extproc"
 	_ "mosn.io/htnn/controller/plugins/fault"
+	_ "mosn.io/htnn/controller/plugins/grpcjsontranscoder"
+	_ "mosn.io/htnn/controller/plugins/grpcweb"
+	_ "mosn.io/htnn/controller/plugins/headertometadata"
+	_ "mosn.io/htnn/controller/plugins/jwtauthn"
 	_ "mosn.io/htnn/controller/plugins/listenerpatch"
 	_ "mosn.io/htnn/controller/plugins/localratelimit"
 	_ "mosn.io/htnn/controller/plugins/lua"
 	_ "mosn.io/htnn/controller/plugins/networkrbac"
+	_ "mosn.io/htnn/controller/plugins/ratelimit"
+	_ "mosn.io/htnn/controller/plugins/rbac"
 	_ "mosn.io/htnn/controller/plugins/routepatch"
 	_ "mosn.io/htnn/controller/plugins/tlsinspector"
 	_ "mosn.io/htnn/types/plugins"
@@ -0,0 +1,51 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	ctrlcfg "mosn.io/htnn/controller/internal/config"
+	"mosn.io/htnn/types/plugins/ratelimit"
+)
+
+func init() {
+	plugins.RegisterPlugin(ratelimit.Name, &plugin{})
+}
+
+type plugin struct {
+	ratelimit.Plugin
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimitPerRoute"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimit",
+			// The domain can be overridden in the route
+			"domain": "htnn",
+			"rateLimitService": map[string]interface{}{
+				"grpcService": map[string]interface{}{
+					"envoyGrpc": map[string]interface{}{
+						"clusterName": ctrlcfg.RateLimitServiceCluster(),
+					},
+				},
+				"transportApiVersion": "V3",
+			},
+		},
+	}
+}
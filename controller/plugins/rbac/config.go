@@ -0,0 +1,47 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/rbac"
+)
+
+func init() {
+	plugins.RegisterPlugin(rbac.Name, &plugin{})
+}
+
+type plugin struct {
+	rbac.Plugin
+}
+
+func (p *plugin) ToRouteConfig(config map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		// v3.RBAC -> v3.RBACPerRoute
+		"rbac": config,
+	}
+}
+
+func (p *plugin) ConfigTypeURL() string {
+	return "type.googleapis.com/envoy.extensions.filters.http.rbac.v3.RBACPerRoute"
+}
+
+func (p *plugin) HTTPFilterConfigPlaceholder() map[string]interface{} {
+	return map[string]interface{}{
+		"typed_config": map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.rbac.v3.RBAC",
+		},
+	}
+}
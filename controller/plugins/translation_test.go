@@ -26,16 +26,54 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"google.golang.org/protobuf/types/known/structpb"
 	istioapi "istio.io/api/networking/v1alpha3"
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	"sigs.k8s.io/yaml"
 
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
 	"mosn.io/htnn/controller/internal/istio"
 	"mosn.io/htnn/controller/internal/translation"
 	"mosn.io/htnn/controller/tests/pkg"
 	mosniov1 "mosn.io/htnn/types/apis/v1"
 )
 
+type wasmDemoConfig struct {
+	structpb.Struct
+}
+
+func (c *wasmDemoConfig) Validate() error {
+	return nil
+}
+
+type wasmDemoPlugin struct {
+	plugins.PluginMethodDefaultImpl
+}
+
+func (p *wasmDemoPlugin) Config() api.PluginConfig {
+	return &wasmDemoConfig{}
+}
+
+func (p *wasmDemoPlugin) Order() plugins.PluginOrder {
+	return plugins.PluginOrder{
+		Position: plugins.OrderPositionOuter,
+	}
+}
+
+func (p *wasmDemoPlugin) Module() plugins.WasmModule {
+	return plugins.WasmModule{
+		URL:           "oci://ghcr.io/mosn/htnn-wasm-demo:v1",
+		SHA256:        "2c6f0b38e7e8e5f1d4b8a2c3e9f0a1b2c3d4e5f60718293a4b5c6d7e8f901234",
+		RootID:        "demo",
+		Configuration: `{"log_level":"info"}`,
+	}
+}
+
+func init() {
+	plugins.RegisterPlugin("wasmDemo", &wasmDemoPlugin{})
+}
+
 func testName(inputFile string) string {
 	_, fileName := filepath.Split(inputFile)
 	return strings.TrimSuffix(fileName, ".in.yml")
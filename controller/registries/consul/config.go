@@ -131,6 +131,7 @@ func (reg *Consul) Start(c registrytype.RegistryConfig) error {
 		err = reg.subscribe(key.Tag, key.ServiceName)
 		if err != nil {
 			reg.logger.Errorf("failed to subscribe service, err: %v, service: %v", err, key)
+			reg.store.ReportSyncError(err)
 			// the service will be resubscribed after refresh interval
 			delete(services, key)
 		}
@@ -161,6 +162,7 @@ func (reg *Consul) Start(c registrytype.RegistryConfig) error {
 			services, meta, err := reg.client.consulCatalog.Services(q)
 			if err != nil {
 				reg.logger.Errorf("failed to get services, err: %v", err)
+				reg.store.ReportSyncError(err)
 				time.Sleep(dur)
 				continue
 			}
@@ -230,6 +232,7 @@ func (reg *Consul) Reload(c registrytype.RegistryConfig) error {
 		err = reg.subscribe(key.Tag, key.ServiceName)
 		if err != nil {
 			reg.logger.Errorf("failed to subscribe service, err: %v, service: %v", err, key)
+			reg.store.ReportSyncError(err)
 			delete(fetchedServices, key)
 		}
 	}
@@ -341,6 +344,7 @@ func (reg *Consul) subscribe(tag, serviceName string) error {
 		err := plan.Run(reg.client.Address)
 		if err != nil {
 			reg.logger.Errorf("failed to subscribe ,err=%v", err)
+			reg.store.ReportSyncError(err)
 		}
 	}()
 
@@ -389,6 +393,7 @@ func (reg *Consul) refresh(services map[string][]string) {
 			err := reg.subscribe("", service.ServiceName)
 			if err != nil {
 				reg.logger.Errorf("failed to subscribe service, err: %v, service: %v", err, service.ServiceName)
+				reg.store.ReportSyncError(err)
 				delete(serviceMap, service)
 			}
 		}
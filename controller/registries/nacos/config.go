@@ -101,6 +101,7 @@ func (reg *Nacos) getSubscribeCallback(groupName string, serviceName string) fun
 		if err != nil {
 			if !strings.Contains(err.Error(), "hosts is empty") {
 				reg.logger.Errorf("callback failed, err: %v, host: %s", err, host)
+				reg.store.ReportSyncError(err)
 			} else {
 				reg.logger.Infof("delete service entry because there are no hosts, service: %s", host)
 				reg.store.Delete(host)
@@ -187,6 +188,7 @@ func (reg *Nacos) Start(c registrytype.RegistryConfig) error {
 		err = reg.client.Subscribe(key.GroupName, key.ServiceName, callback)
 		if err != nil {
 			reg.logger.Errorf("failed to subscribe service, err: %v, service: %v", err, key)
+			reg.store.ReportSyncError(err)
 			// the service will be resubscribed after refresh interval
 			delete(fetchedServices, key)
 		}
@@ -209,6 +211,7 @@ func (reg *Nacos) Start(c registrytype.RegistryConfig) error {
 				err := reg.refresh()
 				if err != nil {
 					reg.logger.Errorf("failed to refresh services, err: %v", err)
+					reg.store.ReportSyncError(err)
 				}
 			case <-reg.done:
 				reg.logger.Infof("stop refreshing services")
@@ -245,6 +248,7 @@ func (reg *Nacos) refresh() error {
 			err = reg.client.Subscribe(key.GroupName, key.ServiceName, callback)
 			if err != nil {
 				reg.logger.Errorf("failed to subscribe service, err: %v, service: %v", err, key)
+				reg.store.ReportSyncError(err)
 			}
 		}
 	}
@@ -328,6 +332,7 @@ func (reg *Nacos) Reload(c registrytype.RegistryConfig) error {
 		err = reg.client.Subscribe(key.GroupName, key.ServiceName, callback)
 		if err != nil {
 			reg.logger.Errorf("failed to subscribe service, err: %v, service: %v", err, key)
+			reg.store.ReportSyncError(err)
 		}
 	}
 	reg.watchingServices = fetchedServices
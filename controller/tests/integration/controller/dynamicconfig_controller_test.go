@@ -100,7 +100,8 @@ var _ = Describe("DynamicConfig controller", func() {
 					return false
 				}
 				for _, item := range envoyfilters.Items {
-					if item.Name == "htnn-dynamic-config" {
+					// the one in the root namespace always exists to dispatch the index
+					if item.Name == "htnn-dynamic-config" && item.Namespace == c.Namespace {
 						return true
 					}
 				}
@@ -135,7 +136,7 @@ var _ = Describe("DynamicConfig controller", func() {
 					return false
 				}
 				for _, item := range envoyfilters.Items {
-					if item.Name == "htnn-dynamic-config" {
+					if item.Name == "htnn-dynamic-config" && item.Namespace == c.Namespace {
 						return false
 					}
 				}
@@ -169,12 +170,63 @@ var _ = Describe("DynamicConfig controller", func() {
 					return false
 				}
 				for _, item := range envoyfilters.Items {
-					if item.Name == "htnn-dynamic-config" {
+					if item.Name == "htnn-dynamic-config" && item.Namespace == c.Namespace {
 						return true
 					}
 				}
 				return false
 			}, timeout, interval).Should(BeTrue())
+
+			// the config is stored in a ConfigMap
+			Expect(len(c.Status.History)).ToNot(BeZero())
+			rev := c.Status.History[len(c.Status.History)-1]
+			cm, err := clientset.CoreV1().ConfigMaps(c.Namespace).Get(ctx, rev.ConfigMap, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cm.Data["config"]).To(MatchJSON(c.Spec.Config.Raw))
+
+			// roll back to unknown version
+			prevVersion := c.Status.History[0].Version
+			base = client.MergeFrom(c.DeepCopy())
+			c.Spec.RollbackTo = "unknown"
+			Expect(k8sClient.Patch(ctx, c, base)).Should(Succeed())
+			Eventually(func() bool {
+				if err := k8sClient.List(ctx, &configs); err != nil {
+					return false
+				}
+				for _, item := range configs.Items {
+					if item.Name == "test" {
+						c = &item
+						cs = c.Status.Conditions
+						if cs[0].Reason == string(mosniov1.ReasonInvalid) {
+							return true
+						}
+					}
+				}
+
+				return false
+			}, timeout, interval).Should(BeTrue())
+			Expect(cs[0].Message).To(ContainSubstring("version unknown to roll back is not found"))
+
+			// roll back to the recorded version
+			base = client.MergeFrom(c.DeepCopy())
+			c.Spec.RollbackTo = prevVersion
+			Expect(k8sClient.Patch(ctx, c, base)).Should(Succeed())
+			Eventually(func() bool {
+				if err := k8sClient.List(ctx, &configs); err != nil {
+					return false
+				}
+				for _, item := range configs.Items {
+					if item.Name == "test" {
+						c = &item
+						cs = c.Status.Conditions
+						if cs[0].Reason == string(mosniov1.ReasonAccepted) {
+							return true
+						}
+					}
+				}
+
+				return false
+			}, timeout, interval).Should(BeTrue())
 		})
 	})
 })
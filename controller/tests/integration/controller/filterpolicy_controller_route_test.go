@@ -407,6 +407,38 @@ var _ = Describe("FilterPolicy controller, for route", func() {
 			}
 			Expect(names).To(ConsistOf([]string{"htnn-http-filter", "htnn-h-default.local"}))
 
+			var policies mosniov1.FilterPolicyList
+			Eventually(func() bool {
+				if err := k8sClient.List(ctx, &policies); err != nil {
+					return false
+				}
+				for _, p := range policies.Items {
+					if len(p.Status.Attachments) != 1 {
+						return false
+					}
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+			winners := []string{}
+			for _, p := range policies.Items {
+				a := p.Status.Attachments[0]
+				Expect(a.Kind).To(Equal(mosniov1.FilterPolicyAttachmentKindRoute))
+				Expect(a.VirtualHost).To(Equal("default.local:8888"))
+				if len(a.Plugins) > 0 {
+					Expect(a.Plugins).To(Equal([]string{"demo"}))
+					winners = append(winners, p.Namespace+"/"+p.Name)
+				}
+			}
+			Expect(len(winners)).To(Equal(1))
+			for _, p := range policies.Items {
+				a := p.Status.Attachments[0]
+				if len(a.Plugins) == 0 {
+					Expect(a.ShadowedPlugins).To(Equal([]mosniov1.FilterPolicyShadowedPlugin{
+						{Name: "demo", By: winners[0]},
+					}))
+				}
+			}
+
 			Expect(k8sClient.Delete(ctx, DefaultVirtualService)).Should(Succeed())
 			Eventually(func() bool {
 				if err := k8sClient.List(ctx, &envoyfilters); err != nil {
@@ -658,6 +690,87 @@ var _ = Describe("FilterPolicy controller, for route", func() {
 			Expect(names).To(ConsistOf([]string{"htnn-http-filter", "htnn-h-default.local"}))
 		})
 
+		It("deal with virtualservices selected by targetSelector", func() {
+			ctx := context.Background()
+			input := []map[string]interface{}{}
+			mustReadFilterPolicy("virtualservice_target_selector", &input)
+
+			for _, in := range input {
+				obj := pkg.MapToObj(in)
+				Expect(k8sClient.Create(ctx, obj)).Should(Succeed())
+			}
+
+			var policies mosniov1.FilterPolicyList
+			var policy mosniov1.FilterPolicy
+			Eventually(func() bool {
+				if err := k8sClient.List(ctx, &policies); err != nil {
+					return false
+				}
+				if len(policies.Items) == 0 || len(policies.Items[0].Status.Conditions) == 0 {
+					return false
+				}
+				policy = policies.Items[0]
+				return policy.Status.Conditions[0].Reason == string(gwapiv1a2.PolicyReasonAccepted)
+			}, timeout, interval).Should(BeTrue())
+			Expect(policy.Status.Targets).To(Equal([]mosniov1.FilterPolicyTargetStatus{
+				{Group: "networking.istio.io", Kind: "VirtualService", Name: "vs-a"},
+			}))
+
+			routeNames := func() []string {
+				var envoyfilters istiov1a3.EnvoyFilterList
+				if err := k8sClient.List(ctx, &envoyfilters); err != nil {
+					return nil
+				}
+				names := []string{}
+				for _, ef := range envoyfilters.Items {
+					if ef.Name != "htnn-h-default.local" {
+						continue
+					}
+					for _, cp := range ef.Spec.ConfigPatches {
+						names = append(names, cp.Match.GetRouteConfiguration().GetVhost().GetRoute().GetName())
+					}
+				}
+				return names
+			}
+			Eventually(routeNames, timeout, interval).Should(ConsistOf("route-a"))
+
+			// label the other VirtualService
+			var vs istiov1a3.VirtualService
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "vs-b"}, &vs)).Should(Succeed())
+			base := client.MergeFrom(vs.DeepCopy())
+			vs.Labels = map[string]string{"tier": "public"}
+			Expect(k8sClient.Patch(ctx, &vs, base)).Should(Succeed())
+			Eventually(routeNames, timeout, interval).Should(ConsistOf("route-a", "route-b"))
+			Eventually(func() int {
+				if err := k8sClient.List(ctx, &policies); err != nil {
+					return 0
+				}
+				return len(policies.Items[0].Status.Targets)
+			}, timeout, interval).Should(Equal(2))
+
+			// unlabel the first VirtualService
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "vs-a"}, &vs)).Should(Succeed())
+			base = client.MergeFrom(vs.DeepCopy())
+			vs.Labels = nil
+			Expect(k8sClient.Patch(ctx, &vs, base)).Should(Succeed())
+			Eventually(routeNames, timeout, interval).Should(ConsistOf("route-b"))
+
+			for _, name := range []string{"vs-a", "vs-b"} {
+				vs := &istiov1a3.VirtualService{}
+				vs.SetNamespace("default")
+				vs.SetName(name)
+				pkg.DeleteK8sResource(ctx, k8sClient, vs)
+			}
+			Eventually(func() bool {
+				if err := k8sClient.List(ctx, &policies); err != nil {
+					return false
+				}
+				policy = policies.Items[0]
+				return policy.Status.Conditions[0].Reason == string(gwapiv1a2.PolicyReasonTargetNotFound)
+			}, timeout, interval).Should(BeTrue())
+			Expect(policy.Status.Targets).To(BeEmpty())
+		})
+
 		It("deal with embedded FilterPolicy", func() {
 			ctx := context.Background()
 			input := []map[string]interface{}{}
@@ -156,6 +156,7 @@ var _ = BeforeSuite(func() {
 	err = (&controller.DynamicConfigReconciler{
 		ResourceManager: rm,
 		Output:          output,
+		RevisionStore:   controller.NewConfigMapRevisionStore(clientset),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
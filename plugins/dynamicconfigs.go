@@ -15,5 +15,8 @@
 package plugins
 
 import (
+	_ "mosn.io/htnn/plugins/dynamicconfigs/consumerstore"
 	_ "mosn.io/htnn/plugins/dynamicconfigs/demo"
+	_ "mosn.io/htnn/plugins/dynamicconfigs/featureflags"
+	_ "mosn.io/htnn/plugins/dynamicconfigs/iplist"
 )
@@ -0,0 +1,169 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"mosn.io/htnn/api/pkg/consumer"
+	"mosn.io/htnn/api/pkg/consumer/model"
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	fmModel "mosn.io/htnn/api/pkg/filtermanager/model"
+	"mosn.io/htnn/types/dynamicconfigs/consumerstore"
+)
+
+const (
+	defaultCacheSize   = 10000
+	defaultTTL         = 60 * time.Second
+	defaultNegativeTTL = 5 * time.Second
+)
+
+func init() {
+	dynamicconfig.RegisterDynamicConfigHandler(consumerstore.Name, &handler{})
+}
+
+type handler struct {
+	consumerstore.Provider
+
+	// the store which is in use, closed when a new one is set
+	current io.Closer
+}
+
+func (h *handler) OnUpdate(config any) error {
+	c := config.(*consumerstore.Config)
+	api.LogInfof("consumer store dynamic config: %s", describeConfig(c))
+
+	var store interface {
+		consumer.Store
+		io.Closer
+	}
+	switch s := c.Store.(type) {
+	case *consumerstore.Config_Redis:
+		store = newRedisStore(s.Redis)
+	case *consumerstore.Config_Http:
+		hs, err := newHTTPStore(s.Http)
+		if err != nil {
+			return err
+		}
+		store = hs
+	}
+
+	opts := consumer.StoreOptions{
+		CacheSize:   defaultCacheSize,
+		TTL:         defaultTTL,
+		NegativeTTL: defaultNegativeTTL,
+	}
+	if cache := c.Cache; cache != nil {
+		if cache.Size > 0 {
+			opts.CacheSize = int(cache.Size)
+		}
+		if cache.Ttl != nil {
+			opts.TTL = cache.Ttl.AsDuration()
+		}
+		if cache.NegativeTtl != nil {
+			opts.NegativeTTL = cache.NegativeTtl.AsDuration()
+		}
+	}
+
+	consumer.SetStore(store, opts)
+	if h.current != nil {
+		// the in-flight lookup may still use the old store, but it's fine as the error is
+		// not cached and will be retried with the new store
+		err := h.current.Close()
+		if err != nil {
+			api.LogWarnf("failed to close the previous consumer store: %v", err)
+		}
+	}
+	h.current = store
+	return nil
+}
+
+// describeConfig returns the description of the config without the secrets, like the Redis password
+// and the credentials in the HTTP URL, so that it can be logged.
+func describeConfig(c *consumerstore.Config) string {
+	var desc string
+	switch s := c.Store.(type) {
+	case *consumerstore.Config_Redis:
+		r := s.Redis
+		desc = fmt.Sprintf("redis{address: %s, username: %s, password set: %t, tls: %t, tls skip verify: %t, prefix: %s, timeout: %s}",
+			r.Address, r.Username, r.Password != "", r.Tls, r.TlsSkipVerify, r.Prefix, r.Timeout.AsDuration())
+	case *consumerstore.Config_Http:
+		h := s.Http
+		desc = fmt.Sprintf("http{url: %s, timeout: %s}", redactURL(h.Url), h.Timeout.AsDuration())
+	}
+	if cache := c.Cache; cache != nil {
+		desc += fmt.Sprintf(", cache{size: %d, ttl: %s, negative ttl: %s}",
+			cache.Size, cache.Ttl.AsDuration(), cache.NegativeTtl.AsDuration())
+	}
+	return desc
+}
+
+// redactURL removes the password and the query, which may carry a token, from the URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<invalid>"
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = "redacted"
+	}
+	return u.Redacted()
+}
+
+// storedConsumer has the same format as the spec of the Consumer resource
+type storedConsumer struct {
+	Name string `json:"name"`
+	Auth map[string]struct {
+		Config json.RawMessage `json:"config"`
+	} `json:"auth"`
+	Filters map[string]struct {
+		Config interface{} `json:"config"`
+	} `json:"filters,omitempty"`
+}
+
+func parseConsumer(data []byte) (string, *model.Consumer, error) {
+	var sc storedConsumer
+	err := json.Unmarshal(data, &sc)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal consumer: %w", err)
+	}
+	if sc.Name == "" {
+		return "", nil, errors.New("consumer name is required")
+	}
+
+	auth := make(map[string]string, len(sc.Auth))
+	for k, v := range sc.Auth {
+		auth[k] = string(v.Config)
+	}
+	c := &model.Consumer{
+		Auth: auth,
+	}
+	if len(sc.Filters) > 0 {
+		filters := make(map[string]*fmModel.FilterConfig, len(sc.Filters))
+		for k, v := range sc.Filters {
+			filters[k] = &fmModel.FilterConfig{
+				Config: v.Config,
+			}
+		}
+		c.Filters = filters
+	}
+	return sc.Name, c, nil
+}
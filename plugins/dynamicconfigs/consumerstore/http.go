@@ -0,0 +1,84 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerstore
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"mosn.io/htnn/api/pkg/consumer/model"
+	"mosn.io/htnn/types/dynamicconfigs/consumerstore"
+)
+
+const (
+	defaultHTTPTimeout = 200 * time.Millisecond
+)
+
+type httpStore struct {
+	client *http.Client
+	url    *url.URL
+}
+
+func newHTTPStore(conf *consumerstore.HTTP) (*httpStore, error) {
+	u, err := url.Parse(conf.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultHTTPTimeout
+	if conf.Timeout != nil {
+		timeout = conf.Timeout.AsDuration()
+	}
+	return &httpStore{
+		client: &http.Client{Timeout: timeout},
+		url:    u,
+	}, nil
+}
+
+func (s *httpStore) Get(ns, pluginName, key string) (string, *model.Consumer, error) {
+	u := *s.url
+	query := u.Query()
+	query.Set("namespace", ns)
+	query.Set("plugin", pluginName)
+	query.Set("key", key)
+	u.RawQuery = query.Encode()
+
+	resp, err := s.client.Get(u.String())
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("unexpected status code %d from consumer lookup service", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	return parseConsumer(data)
+}
+
+func (s *httpStore) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
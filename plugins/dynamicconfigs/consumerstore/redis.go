@@ -0,0 +1,88 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumerstore
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"mosn.io/htnn/api/pkg/consumer/model"
+	"mosn.io/htnn/types/dynamicconfigs/consumerstore"
+)
+
+const (
+	defaultRedisPrefix  = "htnn:consumer:"
+	defaultRedisTimeout = 200 * time.Millisecond
+)
+
+type redisStore struct {
+	client  *redis.Client
+	prefix  string
+	timeout time.Duration
+}
+
+func newRedisStore(conf *consumerstore.Redis) *redisStore {
+	timeout := defaultRedisTimeout
+	if conf.Timeout != nil {
+		timeout = conf.Timeout.AsDuration()
+	}
+	// The lookup is on the request path, so a slow Redis should not stall the requests
+	opt := &redis.Options{
+		Addr:                  conf.Address,
+		Username:              conf.Username,
+		Password:              conf.Password,
+		DialTimeout:           timeout,
+		ReadTimeout:           timeout,
+		WriteTimeout:          timeout,
+		PoolTimeout:           timeout,
+		ContextTimeoutEnabled: true,
+	}
+	if conf.Tls {
+		opt.TLSConfig = &tls.Config{
+			InsecureSkipVerify: conf.TlsSkipVerify,
+		}
+	}
+
+	prefix := conf.Prefix
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	return &redisStore{
+		client:  redis.NewClient(opt),
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+func (s *redisStore) Get(ns, pluginName, key string) (string, *model.Consumer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	data, err := s.client.Get(ctx, s.prefix+ns+":"+pluginName+":"+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	return parseConsumer(data)
+}
+
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
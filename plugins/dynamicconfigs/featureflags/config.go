@@ -0,0 +1,37 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/types/dynamicconfigs/featureflags"
+)
+
+func init() {
+	dynamicconfig.RegisterDynamicConfigHandler(featureflags.Name, &handler{})
+}
+
+type handler struct {
+	featureflags.Provider
+}
+
+// OnUpdate does nothing except logging. The flags are consumed by the featureFlags plugin
+// which refers the DynamicConfig.
+func (h *handler) OnUpdate(config any) error {
+	c := config.(*featureflags.CustomConfig)
+	api.LogInfof("receive %d feature flags", len(c.Flags))
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iplist
+
+import (
+	"net/netip"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/plugins/pkg/iptrie"
+	"mosn.io/htnn/types/dynamicconfigs/iplist"
+)
+
+func init() {
+	dynamicconfig.RegisterDynamicConfigHandler(iplist.Name, &handler{})
+}
+
+type handler struct {
+	iplist.Provider
+}
+
+// Config extends the ipList DynamicConfig with the compiled lists. It is passed to the plugins
+// which refer the DynamicConfig, so that the lists are compiled only once for each update.
+type Config struct {
+	iplist.CustomConfig
+
+	lists map[string]*iptrie.Trie
+}
+
+func (h *handler) Config() dynamicconfig.DynamicConfig {
+	return &Config{}
+}
+
+// Contains reports whether the address is in the given list. The second return value is false
+// if the list is not found.
+// Has reports whether the list exists
+func (c *Config) Has(list string) bool {
+	_, ok := c.lists[list]
+	return ok
+}
+
+// Contains reports whether the address is in the list, and whether the list exists
+func (c *Config) Contains(list string, addr netip.Addr) (bool, bool) {
+	trie, ok := c.lists[list]
+	if !ok {
+		return false, false
+	}
+	return trie.Contains(addr), true
+}
+
+func (h *handler) OnUpdate(config any) error {
+	c := config.(*Config)
+	lists := make(map[string]*iptrie.Trie, len(c.Lists))
+	total := 0
+	for _, list := range c.Lists {
+		trie := iptrie.New()
+		for _, cidr := range list.Cidrs {
+			// the cidr is validated
+			prefix, _ := iplist.ParseCIDR(cidr)
+			trie.Insert(prefix)
+		}
+		lists[list.Name] = trie
+		total += trie.Len()
+	}
+	c.lists = lists
+
+	api.LogInfof("receive %d ip lists with %d cidrs", len(lists), total)
+	return nil
+}
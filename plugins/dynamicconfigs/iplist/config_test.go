@@ -0,0 +1,100 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iplist
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy" // for log implementation
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "duplicate list",
+			input: `{"lists":[{"name":"a","cidrs":["10.0.0.0/8"]},{"name":"a","cidrs":["::1"]}]}`,
+			err:   "duplicate list a",
+		},
+		{
+			name:  "bad cidr",
+			input: `{"lists":[{"name":"a","cidrs":["10.0.0.0/33"]}]}`,
+			err:   "invalid cidr in list a",
+		},
+		{
+			name:  "bad ip",
+			input: `{"lists":[{"name":"a","cidrs":["10.0.0"]}]}`,
+			err:   "invalid cidr in list a",
+		},
+		{
+			name:  "bad name",
+			input: `{"lists":[{"name":"a b","cidrs":["10.0.0.1"]}]}`,
+			err:   "invalid List.Name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &Config{}
+			err := protojson.Unmarshal([]byte(tt.input), conf)
+			if err == nil {
+				err = conf.Validate()
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestOnUpdate(t *testing.T) {
+	h := &handler{}
+	conf := h.Config().(*Config)
+	input := `{"lists":[
+		{"name":"office","cidrs":["10.0.0.0/8","2001:db8::/32"]},
+		{"name":"bots","cidrs":["1.2.3.4","1.2.3.0/30"]}
+	]}`
+	require.NoError(t, protojson.Unmarshal([]byte(input), conf))
+	require.NoError(t, conf.Validate())
+	require.NoError(t, h.OnUpdate(conf))
+
+	tests := []struct {
+		list  string
+		ip    string
+		found bool
+		exist bool
+	}{
+		{"office", "10.1.1.1", true, true},
+		{"office", "2001:db8::1", true, true},
+		{"office", "1.2.3.4", false, true},
+		{"bots", "1.2.3.3", true, true},
+		{"bots", "::ffff:1.2.3.4", true, true},
+		{"bots", "1.2.3.5", false, true},
+		{"unknown", "1.2.3.4", false, false},
+	}
+	for _, tt := range tests {
+		found, exist := conf.Contains(tt.list, netip.MustParseAddr(tt.ip))
+		assert.Equal(t, tt.found, found, tt.list+" "+tt.ip)
+		assert.Equal(t, tt.exist, exist, tt.list+" "+tt.ip)
+	}
+	assert.True(t, conf.Has("office"))
+	assert.False(t, conf.Has("unknown"))
+}
@@ -0,0 +1,145 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package iptrie provides a path-compressed binary radix trie to match IP addresses against
+// a large set of CIDRs.
+package iptrie
+
+import (
+	"math/bits"
+	"net/netip"
+)
+
+type node struct {
+	// the masked prefix bits, only the first 'bits' bits are significant
+	key  [16]byte
+	bits int
+	// terminal is true if a CIDR ends at this node
+	terminal bool
+	children [2]*node
+}
+
+// Trie is a set of CIDRs. It is not safe to insert concurrently, but it is safe to lookup
+// concurrently once all the CIDRs are inserted.
+type Trie struct {
+	v4   *node
+	v6   *node
+	size int
+}
+
+func New() *Trie {
+	return &Trie{}
+}
+
+// Len returns the number of CIDRs inserted
+func (t *Trie) Len() int {
+	return t.size
+}
+
+func bitAt(key *[16]byte, i int) int {
+	return int(key[i>>3]>>(7-uint(i&7))) & 1
+}
+
+// commonBits returns the length of the common prefix of a and b, which is no more than n
+func commonBits(a, b *[16]byte, n int) int {
+	for i := 0; i < n; i += 8 {
+		x := a[i>>3] ^ b[i>>3]
+		if x != 0 {
+			return min(i+bits.LeadingZeros8(x), n)
+		}
+	}
+	return n
+}
+
+func toKey(addr netip.Addr) (key [16]byte, maxBits int) {
+	if addr.Is4() {
+		a4 := addr.As4()
+		copy(key[:], a4[:])
+		return key, 32
+	}
+	return addr.As16(), 128
+}
+
+func (t *Trie) root(addr netip.Addr) **node {
+	if addr.Is4() {
+		return &t.v4
+	}
+	return &t.v6
+}
+
+// Insert adds the CIDR to the trie. The IPv4-mapped IPv6 prefix is not unmapped.
+func (t *Trie) Insert(prefix netip.Prefix) {
+	prefix = prefix.Masked()
+	addr := prefix.Addr()
+	key, _ := toKey(addr)
+	n := prefix.Bits()
+
+	t.size++
+	link := t.root(addr)
+	for {
+		cur := *link
+		if cur == nil {
+			*link = &node{key: key, bits: n, terminal: true}
+			return
+		}
+
+		common := commonBits(&cur.key, &key, min(cur.bits, n))
+		if common == cur.bits {
+			if common == n {
+				cur.terminal = true
+				return
+			}
+			link = &cur.children[bitAt(&key, common)]
+			continue
+		}
+
+		// split the current node at the first different bit
+		split := &node{bits: common}
+		for i := 0; i < common; i += 8 {
+			split.key[i>>3] = key[i>>3]
+		}
+		if common&7 != 0 {
+			split.key[common>>3] &= ^byte(0xff >> uint(common&7))
+		}
+		split.children[bitAt(&cur.key, common)] = cur
+		if common == n {
+			split.terminal = true
+		} else {
+			split.children[bitAt(&key, common)] = &node{key: key, bits: n, terminal: true}
+		}
+		*link = split
+		return
+	}
+}
+
+// Contains reports whether the address is covered by any CIDR in the trie. The IPv4-mapped
+// IPv6 address is matched as IPv4.
+func (t *Trie) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	key, maxBits := toKey(addr)
+	cur := *t.root(addr)
+	for cur != nil {
+		if commonBits(&cur.key, &key, cur.bits) < cur.bits {
+			return false
+		}
+		if cur.terminal {
+			return true
+		}
+		if cur.bits == maxBits {
+			return false
+		}
+		cur = cur.children[bitAt(&key, cur.bits)]
+	}
+	return false
+}
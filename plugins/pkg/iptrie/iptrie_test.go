@@ -0,0 +1,129 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iptrie
+
+import (
+	"math/rand"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrie(t *testing.T) {
+	trie := New()
+	for _, s := range []string{
+		"10.0.0.0/8",
+		"192.168.1.0/24",
+		"192.168.1.128/25",
+		"192.168.2.1/32",
+		"172.16.0.0/12",
+		"2001:db8::/32",
+		"2001:db9::1/128",
+	} {
+		trie.Insert(netip.MustParsePrefix(s))
+	}
+	assert.Equal(t, 7, trie.Len())
+
+	tests := []struct {
+		ip       string
+		contains bool
+	}{
+		{"10.1.2.3", true},
+		{"11.0.0.0", false},
+		{"192.168.1.1", true},
+		{"192.168.1.200", true},
+		{"192.168.0.255", false},
+		{"192.168.2.1", true},
+		{"192.168.2.2", false},
+		{"172.31.255.255", true},
+		{"172.32.0.0", false},
+		{"::ffff:10.0.0.1", true},
+		{"2001:db8:1::1", true},
+		{"2001:db9::1", true},
+		{"2001:db9::2", false},
+		{"::1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.contains, trie.Contains(netip.MustParseAddr(tt.ip)), tt.ip)
+	}
+
+	empty := New()
+	assert.False(t, empty.Contains(netip.MustParseAddr("10.0.0.1")))
+
+	all := New()
+	all.Insert(netip.MustParsePrefix("0.0.0.0/0"))
+	assert.True(t, all.Contains(netip.MustParseAddr("1.2.3.4")))
+	assert.False(t, all.Contains(netip.MustParseAddr("::1")))
+}
+
+func randomAddr(r *rand.Rand, v6 bool) netip.Addr {
+	if v6 {
+		var b [16]byte
+		r.Read(b[:])
+		// make the addresses denser so that they share prefixes
+		b[0], b[1] = 0x20, 0x01
+		return netip.AddrFrom16(b)
+	}
+	var b [4]byte
+	r.Read(b[:])
+	b[0] = 10
+	return netip.AddrFrom4(b)
+}
+
+func TestTrieRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, v6 := range []bool{false, true} {
+		trie := New()
+		var prefixes []netip.Prefix
+		for i := 0; i < 2000; i++ {
+			addr := randomAddr(r, v6)
+			n := addr.BitLen() - r.Intn(addr.BitLen()/2)
+			p := netip.PrefixFrom(addr, n).Masked()
+			prefixes = append(prefixes, p)
+			trie.Insert(p)
+		}
+
+		for i := 0; i < 20000; i++ {
+			addr := randomAddr(r, v6)
+			expected := false
+			for _, p := range prefixes {
+				if p.Contains(addr) {
+					expected = true
+					break
+				}
+			}
+			assert.Equal(t, expected, trie.Contains(addr), addr.String())
+		}
+	}
+}
+
+func BenchmarkTrieContains(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	trie := New()
+	for i := 0; i < 100000; i++ {
+		addr := randomAddr(r, false)
+		trie.Insert(netip.PrefixFrom(addr, 16+r.Intn(17)))
+	}
+	addrs := make([]netip.Addr, 1024)
+	for i := range addrs {
+		addrs[i] = randomAddr(r, false)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.Contains(addrs[i%len(addrs)])
+	}
+}
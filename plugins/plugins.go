@@ -16,13 +16,18 @@ package plugins
 
 import (
 	_ "mosn.io/htnn/plugins/plugins/aicontentsecurity"
+	_ "mosn.io/htnn/plugins/plugins/anonymousconsumer"
+	_ "mosn.io/htnn/plugins/plugins/authnpolicy"
 	_ "mosn.io/htnn/plugins/plugins/casbin"
 	_ "mosn.io/htnn/plugins/plugins/celscript"
+	_ "mosn.io/htnn/plugins/plugins/consumermetadataheaders"
 	_ "mosn.io/htnn/plugins/plugins/consumerrestriction"
 	_ "mosn.io/htnn/plugins/plugins/debugmode"
 	_ "mosn.io/htnn/plugins/plugins/demo"
 	_ "mosn.io/htnn/plugins/plugins/extauth"
+	_ "mosn.io/htnn/plugins/plugins/featureflags"
 	_ "mosn.io/htnn/plugins/plugins/hmacauth"
+	_ "mosn.io/htnn/plugins/plugins/iprestriction"
 	_ "mosn.io/htnn/plugins/plugins/keyauth"
 	_ "mosn.io/htnn/plugins/plugins/limitcountredis"
 	_ "mosn.io/htnn/plugins/plugins/limitreq"
@@ -0,0 +1,48 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authnpolicy
+
+import (
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/plugins/authnpolicy"
+)
+
+func init() {
+	plugins.RegisterPlugin(authnpolicy.Name, &plugin{})
+}
+
+type plugin struct {
+	authnpolicy.Plugin
+}
+
+func (p *plugin) Factory() api.FilterFactory {
+	return factory
+}
+
+func (p *plugin) Config() api.PluginConfig {
+	return &config{}
+}
+
+type config struct {
+	authnpolicy.Config
+}
+
+func (conf *config) AuthnMode() plugins.AuthnMode {
+	if conf.GetMode() == authnpolicy.Mode_ALL {
+		return plugins.AuthnModeAll
+	}
+	return plugins.AuthnModeAny
+}
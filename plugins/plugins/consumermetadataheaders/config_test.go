@@ -0,0 +1,58 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumermetadataheaders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestBadConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty headers",
+			input: `{}`,
+			err:   "value must contain at least 1 pair(s)",
+		},
+		{
+			name:  "invalid header name",
+			input: `{"headers":{"tenant":"x consumer"}}`,
+			err:   "value does not match regex pattern",
+		},
+		{
+			name:  "pseudo header",
+			input: `{"headers":{"tenant":":authority"}}`,
+			err:   "pseudo header :authority is not allowed for metadata tenant",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config{}
+			err := protojson.Unmarshal([]byte(tt.input), conf)
+			if err == nil {
+				err = conf.Validate()
+			}
+			assert.NotNil(t, err)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
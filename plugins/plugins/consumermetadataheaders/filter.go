@@ -0,0 +1,35 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumermetadataheaders
+
+import (
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+)
+
+func factory(c interface{}, callbacks api.FilterCallbackHandler) api.Filter {
+	return &filter{}
+}
+
+// The headers are removed by the filtermanager before the Consumer plugins are run, and set
+// after the consumer is finally determined, so the filter itself does nothing.
+type filter struct {
+	api.PassThroughFilter
+}
+
+// DecodeHeaders is defined so that the filtermanager doesn't skip DecodeHeaders, which removes the
+// headers from the client, even if this plugin is the only one configured.
+func (f *filter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api.ResultAction {
+	return api.Continue
+}
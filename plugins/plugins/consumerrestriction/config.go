@@ -37,15 +37,18 @@ func (p *plugin) Config() api.PluginConfig {
 }
 
 type config struct {
-	consumerrestriction.Config
+	consumerrestriction.CustomConfig
 
 	allow     bool
-	consumers map[string]*Rule
+	consumers map[string][]*Rule
+	// rules which select the consumers by metadata only
+	selectors []*Rule
 }
 
 type Rule struct {
-	Name    string
-	Methods map[string]bool
+	Name     string
+	Metadata map[string]string
+	Methods  map[string]bool
 }
 
 func (conf *config) Init(cb api.ConfigCallbackHandler) error {
@@ -59,16 +62,50 @@ func (conf *config) Init(cb api.ConfigCallbackHandler) error {
 		conf.allow = true
 	}
 
-	conf.consumers = make(map[string]*Rule, len(rules.Rules))
+	conf.consumers = make(map[string][]*Rule, len(rules.Rules))
 	for _, r := range rules.Rules {
 		methods := make(map[string]bool)
 		for _, method := range r.GetMethods() {
 			methods[method] = true
 		}
-		conf.consumers[r.Name] = &Rule{
-			Name:    r.Name,
-			Methods: methods,
+		rule := &Rule{
+			Name:     r.Name,
+			Metadata: r.Metadata,
+			Methods:  methods,
+		}
+		if r.Name == "" {
+			conf.selectors = append(conf.selectors, rule)
+		} else {
+			conf.consumers[r.Name] = append(conf.consumers[r.Name], rule)
 		}
 	}
 	return nil
 }
+
+func (r *Rule) match(consumer api.Consumer, method string) bool {
+	if len(r.Methods) > 0 && !r.Methods[method] {
+		return false
+	}
+
+	md := consumer.Metadata()
+	for k, v := range r.Metadata {
+		if mv, ok := md[k]; !ok || mv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (conf *config) match(consumer api.Consumer, method string) bool {
+	for _, rule := range conf.consumers[consumer.Name()] {
+		if rule.match(consumer, method) {
+			return true
+		}
+	}
+	for _, rule := range conf.selectors {
+		if rule.match(consumer, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -39,6 +39,15 @@ func TestBadConfig(t *testing.T) {
 			}`,
 			err: "oneof types.plugins.consumerrestriction.Config.config_type is already set",
 		},
+		{
+			name: "either name or metadata is required",
+			input: `{
+				"allow": {
+					"rules": [{"methods":["GET"]}]
+				}
+			}`,
+			err: "either name or metadata is required in the rule",
+		},
 	}
 
 	for _, tt := range tests {
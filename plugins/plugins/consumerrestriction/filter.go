@@ -44,10 +44,9 @@ func (f *filter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api
 	}
 
 	consumerName := consumer.Name()
-	rule, ok := f.config.consumers[consumerName]
-	methodMatched := ok && (len(rule.Methods) == 0 || rule.Methods[headers.Method()])
+	matched := f.config.match(consumer, headers.Method())
 
-	if methodMatched != f.config.allow {
+	if matched != f.config.allow {
 		api.LogInfof("consumerRestriction: consumer %s not allowed", consumerName)
 		return &api.LocalResponse{Code: 403, Msg: "consumer not allowed"}
 	}
@@ -0,0 +1,103 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"sync/atomic"
+
+	"github.com/google/cel-go/cel"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+	dcFeatureflags "mosn.io/htnn/types/dynamicconfigs/featureflags"
+	"mosn.io/htnn/types/pkg/expr"
+	"mosn.io/htnn/types/plugins/featureflags"
+)
+
+const (
+	defaultHeaderPrefix = "x-htnn-feature-"
+)
+
+func init() {
+	plugins.RegisterPlugin(featureflags.Name, &plugin{})
+}
+
+type plugin struct {
+	featureflags.Plugin
+}
+
+func (p *plugin) Factory() api.FilterFactory {
+	return factory
+}
+
+func (p *plugin) Config() api.PluginConfig {
+	return &config{}
+}
+
+type flag struct {
+	name   string
+	header string
+	script expr.Script
+}
+
+type config struct {
+	featureflags.CustomConfig
+
+	// the flags are replaced as a whole when the DynamicConfig is updated
+	flags atomic.Pointer[[]*flag]
+}
+
+func (conf *config) headerPrefix() string {
+	if conf.HeaderPrefix == "" {
+		return defaultHeaderPrefix
+	}
+	return conf.HeaderPrefix
+}
+
+func (conf *config) DynamicConfigRefs() []string {
+	return []string{conf.DynamicConfigName()}
+}
+
+func (conf *config) OnDynamicConfigUpdate(name string, c any) {
+	flags := []*flag{}
+	if c != nil {
+		dc := c.(*dcFeatureflags.CustomConfig)
+		selected := make(map[string]bool, len(conf.Flags))
+		for _, f := range conf.Flags {
+			selected[f] = true
+		}
+
+		prefix := conf.headerPrefix()
+		for _, f := range dc.Flags {
+			if len(selected) > 0 && !selected[f.Name] {
+				continue
+			}
+			// the rule is validated in the DynamicConfig
+			script, err := expr.CompileCel(f.Rule, cel.BoolType)
+			if err != nil {
+				api.LogErrorf("failed to compile rule of flag %s: %v", f.Name, err)
+				continue
+			}
+			flags = append(flags, &flag{
+				name:   f.Name,
+				header: prefix + f.Name,
+				script: script,
+			})
+		}
+	}
+
+	api.LogInfof("update %d feature flags from dynamic config %s", len(flags), name)
+	conf.flags.Store(&flags)
+}
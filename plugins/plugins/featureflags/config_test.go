@@ -0,0 +1,72 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	_ "mosn.io/htnn/types/dynamicconfigs/demo"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "default",
+			input: `{}`,
+		},
+		{
+			name:  "unknown dynamic config",
+			input: `{"dynamicConfigRef":"unknown"}`,
+			err:   "unknown dynamic config unknown",
+		},
+		{
+			name:  "not featureFlags dynamic config",
+			input: `{"dynamicConfigRef":"demo"}`,
+			err:   "is not a featureFlags DynamicConfig",
+		},
+		{
+			name:  "duplicate flags",
+			input: `{"flags":["a","a"]}`,
+			err:   "must contain unique items",
+		},
+		{
+			name:  "bad header prefix",
+			input: `{"headerPrefix":"x y"}`,
+			err:   "invalid Config.HeaderPrefix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config{}
+			err := protojson.Unmarshal([]byte(tt.input), conf)
+			if err == nil {
+				err = conf.Validate()
+			}
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
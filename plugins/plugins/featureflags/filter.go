@@ -0,0 +1,60 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"strconv"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/types/plugins/featureflags"
+)
+
+func factory(c interface{}, callbacks api.FilterCallbackHandler) api.Filter {
+	return &filter{
+		callbacks: callbacks,
+		config:    c.(*config),
+	}
+}
+
+type filter struct {
+	api.PassThroughFilter
+
+	callbacks api.FilterCallbackHandler
+	config    *config
+}
+
+func (f *filter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api.ResultAction {
+	flags := f.config.flags.Load()
+	if flags == nil {
+		// the DynamicConfig is not received yet
+		return api.Continue
+	}
+
+	state := f.callbacks.PluginState()
+	for _, fl := range *flags {
+		enabled := false
+		res, err := fl.script.EvalWithRequest(f.callbacks, headers)
+		if err != nil {
+			api.LogErrorf("failed to eval rule of flag %s: %v", fl.name, err)
+		} else {
+			enabled = res.(bool)
+		}
+
+		// overwrite the header sent by the client
+		headers.Set(fl.header, strconv.FormatBool(enabled))
+		state.Set(featureflags.Name, fl.name, enabled)
+	}
+	return api.Continue
+}
@@ -0,0 +1,115 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+	dcFeatureflags "mosn.io/htnn/types/dynamicconfigs/featureflags"
+	"mosn.io/htnn/types/plugins/featureflags"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	dc := &dcFeatureflags.CustomConfig{
+		Config: dcFeatureflags.Config{
+			Flags: []*dcFeatureflags.Flag{
+				{Name: "get", Rule: `request.method() == "GET"`},
+				{Name: "post", Rule: `request.method() == "POST"`},
+				{Name: "bad", Rule: `int(request.header("x-n")) == 2`},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config *config
+		update bool
+		dc     any
+		hdrs   http.Header
+		state  map[string]any
+	}{
+		{
+			name:   "not received",
+			config: &config{},
+			hdrs:   http.Header{"X-Htnn-Feature-Get": {"true"}},
+		},
+		{
+			name:   "all flags",
+			config: &config{},
+			update: true,
+			dc:     dc,
+			hdrs: http.Header{
+				"X-Htnn-Feature-Get":  {"true"},
+				"X-Htnn-Feature-Post": {"false"},
+				"X-Htnn-Feature-Bad":  {"false"},
+			},
+			state: map[string]any{"get": true, "post": false, "bad": false},
+		},
+		{
+			name: "selected flags with custom prefix",
+			config: &config{
+				CustomConfig: featureflags.CustomConfig{
+					Config: featureflags.Config{
+						Flags:        []string{"post"},
+						HeaderPrefix: "x-flag-",
+					},
+				},
+			},
+			update: true,
+			dc:     dc,
+			hdrs: http.Header{
+				"X-Htnn-Feature-Get": {"true"},
+				"X-Flag-Post":        {"false"},
+			},
+			state: map[string]any{"post": false},
+		},
+		{
+			name:   "cleared",
+			config: &config{},
+			update: true,
+			dc:     nil,
+			hdrs:   http.Header{"X-Htnn-Feature-Get": {"true"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.update {
+				tt.config.OnDynamicConfigUpdate("featureFlags", tt.dc)
+			}
+			cb := envoy.NewFilterCallbackHandler()
+			f := factory(tt.config, cb)
+			// the header sent by the client is overwritten
+			hdr := envoy.NewRequestHeaderMap(http.Header{
+				":method":            {"GET"},
+				"X-Htnn-Feature-Get": {"true"},
+			})
+			res := f.DecodeHeaders(hdr, true)
+			assert.Equal(t, api.Continue, res)
+
+			for k, v := range tt.hdrs {
+				assert.Equal(t, v, hdr.Values(k), k)
+			}
+			for name, v := range tt.state {
+				assert.Equal(t, v, cb.PluginState().Get(featureflags.Name, name), name)
+			}
+		})
+	}
+}
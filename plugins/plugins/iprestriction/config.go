@@ -0,0 +1,106 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iprestriction
+
+import (
+	"net/netip"
+	"sync/atomic"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/plugins/dynamicconfigs/iplist"
+	dcIplist "mosn.io/htnn/types/dynamicconfigs/iplist"
+	"mosn.io/htnn/types/plugins/iprestriction"
+)
+
+const (
+	defaultStatusCode = 403
+)
+
+func init() {
+	plugins.RegisterPlugin(iprestriction.Name, &plugin{})
+}
+
+type plugin struct {
+	iprestriction.Plugin
+}
+
+func (p *plugin) Factory() api.FilterFactory {
+	return factory
+}
+
+func (p *plugin) Config() api.PluginConfig {
+	return &config{}
+}
+
+type config struct {
+	iprestriction.CustomConfig
+
+	hop        int
+	statusCode int
+
+	// the ipList DynamicConfig in use, nil if it is not received yet
+	ipList atomic.Pointer[iplist.Config]
+}
+
+func (conf *config) Init(cb api.ConfigCallbackHandler) error {
+	conf.hop = 1
+	if xff := conf.XForwardedFor; xff != nil && xff.Hop > 0 {
+		conf.hop = int(xff.Hop)
+	}
+
+	conf.statusCode = defaultStatusCode
+	if conf.StatusCode != 0 {
+		conf.statusCode = int(conf.StatusCode)
+	}
+	return nil
+}
+
+func (conf *config) DynamicConfigRefs() []string {
+	return []string{dcIplist.Name}
+}
+
+func (conf *config) OnDynamicConfigUpdate(name string, c any) {
+	if c == nil {
+		api.LogErrorf("ipRestriction: %s DynamicConfig is not found, requests are rejected", dcIplist.Name)
+		conf.ipList.Store(nil)
+		return
+	}
+
+	lists := c.(*iplist.Config)
+	for _, deny := range conf.Deny {
+		if !lists.Has(deny.Name) {
+			api.LogErrorf("ipRestriction: deny list %s is not found, requests are rejected", deny.Name)
+		}
+	}
+	for _, allow := range conf.Allow {
+		if !lists.Has(allow) {
+			api.LogErrorf("ipRestriction: allow list %s is not found, it is treated as empty", allow)
+		}
+	}
+	conf.ipList.Store(lists)
+}
+
+// inList reports whether the address is in the list, and whether the list exists.
+// The lists are not found if the DynamicConfig is not received yet.
+func inList(lists *iplist.Config, name string, addr netip.Addr) (bool, bool) {
+	if lists == nil {
+		return false, false
+	}
+	if !addr.IsValid() {
+		return false, lists.Has(name)
+	}
+	return lists.Contains(name, addr)
+}
@@ -0,0 +1,98 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iprestriction
+
+import (
+	"net/netip"
+	"strings"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+)
+
+func factory(c interface{}, callbacks api.FilterCallbackHandler) api.Filter {
+	return &filter{
+		callbacks: callbacks,
+		config:    c.(*config),
+	}
+}
+
+type filter struct {
+	api.PassThroughFilter
+
+	callbacks api.FilterCallbackHandler
+	config    *config
+}
+
+// clientIP returns the address to check. The invalid address is not in any list.
+func (f *filter) clientIP(headers api.RequestHeaderMap) netip.Addr {
+	ip := ""
+	if f.config.XForwardedFor != nil {
+		var hops []string
+		for _, v := range headers.Values("x-forwarded-for") {
+			hops = append(hops, strings.Split(v, ",")...)
+		}
+		if idx := len(hops) - f.config.hop; idx >= 0 {
+			ip = strings.TrimSpace(hops[idx])
+		}
+	}
+	if ip == "" {
+		// fall back to the downstream remote address if the hop is not found
+		ip = f.callbacks.StreamInfo().DownstreamRemoteParsedAddress().IP
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		api.LogInfof("ipRestriction: invalid ip %q", ip)
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func (f *filter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api.ResultAction {
+	addr := f.clientIP(headers)
+	lists := f.config.ipList.Load()
+
+	for _, deny := range f.config.Deny {
+		found, exist := inList(lists, deny.Name, addr)
+		if !exist {
+			// fail closed, so that a typo in the list name doesn't let the denied IP in
+			api.LogWarnf("ipRestriction: deny list %s is not found, reject ip %s", deny.Name, addr)
+		} else if found {
+			api.LogInfof("ipRestriction: ip %s is in the deny list %s", addr, deny.Name)
+		}
+		if found || !exist {
+			code := f.config.statusCode
+			if deny.StatusCode != 0 {
+				code = int(deny.StatusCode)
+			}
+			return &api.LocalResponse{Code: code, Msg: "ip not allowed"}
+		}
+	}
+
+	if len(f.config.Allow) == 0 {
+		return api.Continue
+	}
+	for _, allow := range f.config.Allow {
+		found, exist := inList(lists, allow, addr)
+		if !exist {
+			api.LogWarnf("ipRestriction: allow list %s is not found", allow)
+		}
+		if found {
+			return api.Continue
+		}
+	}
+	api.LogInfof("ipRestriction: ip %s is not in the allow lists", addr)
+	return &api.LocalResponse{Code: f.config.statusCode, Msg: "ip not allowed"}
+}
@@ -0,0 +1,159 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iprestriction
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+	"mosn.io/htnn/plugins/dynamicconfigs/iplist"
+	dcIplist "mosn.io/htnn/types/dynamicconfigs/iplist"
+)
+
+func newIPList(t *testing.T, input string) *iplist.Config {
+	h, ok := dynamicconfig.LoadDynamicConfigProvider(dcIplist.Name).(dynamicconfig.DynamicConfigHandler)
+	require.True(t, ok)
+	conf := h.Config().(*iplist.Config)
+	require.NoError(t, protojson.Unmarshal([]byte(input), conf))
+	require.NoError(t, conf.Validate())
+	require.NoError(t, h.OnUpdate(conf))
+	return conf
+}
+
+func TestIPRestriction(t *testing.T) {
+	lists := newIPList(t, `{"lists":[
+		{"name":"office","cidrs":["183.128.0.0/16","2001:db8::/32"]},
+		{"name":"bots","cidrs":["1.2.3.0/24"]},
+		{"name":"abuse","cidrs":["183.128.130.43"]}
+	]}`)
+
+	tests := []struct {
+		name   string
+		input  string
+		lists  *iplist.Config
+		header http.Header
+		code   int
+	}{
+		{
+			name:  "allow",
+			input: `{"allow":["office"]}`,
+			lists: lists,
+		},
+		{
+			name:  "not in allow lists",
+			input: `{"allow":["bots"],"statusCode":401}`,
+			lists: lists,
+			code:  401,
+		},
+		{
+			name:  "dynamic config not received",
+			input: `{"allow":["office"]}`,
+			code:  403,
+		},
+		{
+			name:  "unknown allow list",
+			input: `{"allow":["unknown"]}`,
+			lists: lists,
+			code:  403,
+		},
+		{
+			name:  "unknown deny list",
+			input: `{"deny":[{"name":"unknown","statusCode":429}],"allow":["office"]}`,
+			lists: lists,
+			code:  429,
+		},
+		{
+			name:  "deny without dynamic config",
+			input: `{"deny":[{"name":"bots"}]}`,
+			code:  403,
+		},
+		{
+			name:  "deny with per-list status code",
+			input: `{"deny":[{"name":"bots"},{"name":"abuse","statusCode":429}]}`,
+			lists: lists,
+			code:  429,
+		},
+		{
+			name:  "deny before allow",
+			input: `{"deny":[{"name":"abuse"}],"allow":["office"]}`,
+			lists: lists,
+			code:  403,
+		},
+		{
+			name:   "xff",
+			input:  `{"deny":[{"name":"bots"}],"xForwardedFor":{}}`,
+			lists:  lists,
+			header: http.Header{"X-Forwarded-For": {"1.1.1.1, 1.2.3.4"}},
+			code:   403,
+		},
+		{
+			name:   "xff hop",
+			input:  `{"deny":[{"name":"bots"}],"xForwardedFor":{"hop":3}}`,
+			lists:  lists,
+			header: http.Header{"X-Forwarded-For": {"1.2.3.4, 1.1.1.1", "2.2.2.2"}},
+			code:   403,
+		},
+		{
+			name:   "xff hop not found",
+			input:  `{"allow":["office"],"xForwardedFor":{"hop":3}}`,
+			lists:  lists,
+			header: http.Header{"X-Forwarded-For": {"1.2.3.4"}},
+		},
+		{
+			name:   "xff ipv6",
+			input:  `{"allow":["office"],"xForwardedFor":{}}`,
+			lists:  lists,
+			header: http.Header{"X-Forwarded-For": {"2001:db8::1"}},
+		},
+		{
+			name:   "invalid xff",
+			input:  `{"allow":["office"],"xForwardedFor":{}}`,
+			lists:  lists,
+			header: http.Header{"X-Forwarded-For": {"unknown"}},
+			code:   403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config{}
+			require.NoError(t, protojson.Unmarshal([]byte(tt.input), conf))
+			require.NoError(t, conf.Validate())
+			require.NoError(t, conf.Init(nil))
+			if tt.lists != nil {
+				conf.OnDynamicConfigUpdate(dcIplist.Name, tt.lists)
+			}
+
+			cb := envoy.NewFilterCallbackHandler()
+			f := factory(conf, cb)
+			hdr := envoy.NewRequestHeaderMap(tt.header)
+			res := f.DecodeHeaders(hdr, true)
+			if tt.code == 0 {
+				assert.Equal(t, api.Continue, res)
+			} else {
+				resp, ok := res.(*api.LocalResponse)
+				require.True(t, ok)
+				assert.Equal(t, tt.code, resp.Code)
+			}
+		})
+	}
+}
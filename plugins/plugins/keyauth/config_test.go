@@ -16,9 +16,13 @@ package keyauth
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/protojson"
+
+	"mosn.io/htnn/types/plugins/keyauth"
 )
 
 func TestConfig(t *testing.T) {
@@ -56,3 +60,75 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConsumerConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: `{}`,
+			err:   "either key or credentials is required",
+		},
+		{
+			name:  "key",
+			input: `{"key":"rick"}`,
+		},
+		{
+			name: "credentials",
+			input: `{"credentials":[
+				{"key":"rick"},
+				{"key":"morty", "notBefore":"2024-01-01T00:00:00Z", "expiresAt":"2025-01-01T00:00:00Z"}
+			]}`,
+		},
+		{
+			name:  "empty key in credentials",
+			input: `{"credentials":[{"key":""}]}`,
+			err:   "invalid Credential.Key: value length must be at least 1 runes",
+		},
+		{
+			name:  "duplicate key",
+			input: `{"key":"rick","credentials":[{"key":"rick"}]}`,
+			err:   "duplicate key rick",
+		},
+		{
+			name:  "invalid time range",
+			input: `{"credentials":[{"key":"rick", "notBefore":"2025-01-01T00:00:00Z", "expiresAt":"2024-01-01T00:00:00Z"}]}`,
+			err:   "notBefore should be earlier than expiresAt for key rick",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := (&plugin{}).ConsumerConfig()
+			err := protojson.Unmarshal([]byte(tt.input), conf)
+			if err == nil {
+				err = conf.Validate()
+			}
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCredentialStatus(t *testing.T) {
+	conf := &keyauth.CustomConsumerConfig{}
+	err := protojson.Unmarshal([]byte(`{"key":"rick","credentials":[
+		{"key":"morty", "notBefore":"2024-01-01T00:00:00Z", "expiresAt":"2025-01-01T00:00:00Z"}
+	]}`), conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"rick", "morty"}, conf.Indexes())
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, keyauth.CredentialValid, conf.CredentialStatus("rick", now))
+	assert.Equal(t, keyauth.CredentialValid, conf.CredentialStatus("morty", now))
+	assert.Equal(t, keyauth.CredentialNotYetValid, conf.CredentialStatus("morty", now.AddDate(-1, 0, 0)))
+	assert.Equal(t, keyauth.CredentialExpired, conf.CredentialStatus("morty", now.AddDate(1, 0, 0)))
+	assert.Equal(t, keyauth.CredentialNotFound, conf.CredentialStatus("summer", now))
+}
@@ -16,6 +16,7 @@ package keyauth
 
 import (
 	"net/url"
+	"time"
 
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/types/plugins/keyauth"
@@ -41,6 +42,21 @@ func (f *filter) verify(value string) api.ResultAction {
 		return &api.LocalResponse{Code: 401, Msg: "invalid key"}
 	}
 
+	conf, ok := c.PluginConfig(keyauth.Name).(*keyauth.CustomConsumerConfig)
+	if !ok {
+		// the consumer from the store may not have the keyAuth configuration
+		api.LogInfof("consumer %s has no keyAuth configuration", c.Name())
+		return &api.LocalResponse{Code: 401, Msg: "invalid key"}
+	}
+	switch conf.CredentialStatus(value, time.Now()) {
+	case keyauth.CredentialNotYetValid:
+		api.LogInfof("key of consumer %s is not valid yet", c.Name())
+		return &api.LocalResponse{Code: 401, Msg: "invalid key", Details: "key_not_yet_valid"}
+	case keyauth.CredentialExpired:
+		api.LogInfof("key of consumer %s is expired", c.Name())
+		return &api.LocalResponse{Code: 401, Msg: "expired key", Details: "key_expired"}
+	}
+
 	f.callbacks.SetConsumer(c)
 	return api.Continue
 }
@@ -0,0 +1,129 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mosn.io/htnn/api/pkg/filtermanager"
+	"mosn.io/htnn/api/pkg/filtermanager/model"
+	"mosn.io/htnn/api/plugins/tests/integration/controlplane"
+	"mosn.io/htnn/api/plugins/tests/integration/dataplane"
+)
+
+func TestAnonymousConsumer(t *testing.T) {
+	dp, err := dataplane.StartDataPlane(t, &dataplane.Option{
+		Bootstrap: dataplane.Bootstrap().AddConsumer("tom", map[string]interface{}{
+			"auth": map[string]interface{}{
+				"keyAuth": `{"key":"tom"}`,
+			},
+		}).AddConsumer("anonymous", map[string]interface{}{
+			"auth": map[string]interface{}{
+				"keyAuth": `{"key":"anonymous"}`,
+			},
+			"filters": map[string]interface{}{
+				"demo": map[string]interface{}{
+					"config": `{"hostName": "Anonymous"}`,
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("failed to start data plane: %v", err)
+		return
+	}
+	defer dp.Stop()
+
+	tests := []struct {
+		name   string
+		config *filtermanager.FilterManagerConfig
+		run    func(t *testing.T)
+	}{
+		{
+			name: "fallback",
+			config: controlplane.NewPluginConfig([]*model.FilterConfig{
+				{
+					Name: "keyAuth",
+					Config: map[string]interface{}{
+						"keys": []interface{}{
+							map[string]interface{}{
+								"name": "Authorization",
+							},
+						},
+					},
+				},
+				{
+					Name: "anonymousConsumer",
+					Config: map[string]interface{}{
+						"consumer": "anonymous",
+					},
+				},
+				{
+					Name: "consumerRestriction",
+					Config: map[string]interface{}{
+						"denyIfNoConsumer": true,
+					},
+				},
+			}),
+			run: func(t *testing.T) {
+				resp, err := dp.Get("/echo", nil)
+				require.NoError(t, err)
+				assert.Equal(t, 200, resp.StatusCode)
+				assert.Equal(t, "hello,", resp.Header.Get("Echo-Anonymous"), resp)
+
+				resp, _ = dp.Get("/echo", http.Header{"Authorization": []string{"tom"}})
+				assert.Equal(t, 200, resp.StatusCode)
+				assert.Equal(t, "", resp.Header.Get("Echo-Anonymous"), resp)
+
+				// the request with invalid credential is still rejected
+				resp, _ = dp.Get("/echo", http.Header{"Authorization": []string{"jerry"}})
+				assert.Equal(t, 401, resp.StatusCode)
+			},
+		},
+		{
+			name: "anonymous consumer not found",
+			config: controlplane.NewPluginConfig([]*model.FilterConfig{
+				{
+					Name: "anonymousConsumer",
+					Config: map[string]interface{}{
+						"consumer": "not_found",
+					},
+				},
+				{
+					Name: "consumerRestriction",
+					Config: map[string]interface{}{
+						"denyIfNoConsumer": true,
+					},
+				},
+			}),
+			run: func(t *testing.T) {
+				resp, err := dp.Get("/echo", nil)
+				require.NoError(t, err)
+				assert.Equal(t, 401, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controlPlane.UseGoPluginConfig(t, tt.config, dp)
+			tt.run(t)
+		})
+	}
+}
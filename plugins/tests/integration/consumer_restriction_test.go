@@ -34,6 +34,9 @@ func TestConsumerRestriction(t *testing.T) {
 			"auth": map[string]interface{}{
 				"keyAuth": `{"key":"tom"}`,
 			},
+			"metadata": map[string]interface{}{
+				"tier": "gold",
+			},
 		}).AddConsumer("with_filter", map[string]interface{}{
 			"auth": map[string]interface{}{
 				"keyAuth": `{"key":"marvin"}`,
@@ -90,6 +93,51 @@ func TestConsumerRestriction(t *testing.T) {
 				assert.Equal(t, 200, resp.StatusCode)
 			},
 		},
+		{
+			name: "allow by metadata",
+			config: controlplane.NewPluginConfig([]*model.FilterConfig{
+				{
+					Name: "keyAuth",
+					Config: map[string]interface{}{
+						"keys": []interface{}{
+							map[string]interface{}{
+								"name": "Authorization",
+							},
+						},
+					},
+				},
+				{
+					Name: "consumerRestriction",
+					Config: map[string]interface{}{
+						"allow": map[string]interface{}{
+							"rules": []interface{}{
+								map[string]interface{}{
+									"metadata": map[string]interface{}{
+										"tier": "gold",
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "consumerMetadataHeaders",
+					Config: map[string]interface{}{
+						"headers": map[string]interface{}{
+							"tier": "x-consumer-tier",
+						},
+					},
+				},
+			}),
+			run: func(t *testing.T) {
+				resp, err := dp.Get("/echo", http.Header{"Authorization": []string{"marvin"}})
+				require.NoError(t, err)
+				assert.Equal(t, 403, resp.StatusCode)
+				resp, _ = dp.Get("/echo", http.Header{"Authorization": []string{"tom"}, "X-Consumer-Tier": []string{"silver"}})
+				assert.Equal(t, 200, resp.StatusCode)
+				assert.Equal(t, []string{"gold"}, resp.Header.Values("Echo-X-Consumer-Tier"))
+			},
+		},
 		{
 			name: "allowed by method",
 			config: controlplane.NewPluginConfig([]*model.FilterConfig{
@@ -15,6 +15,7 @@
 package integration
 
 import (
+	"io"
 	"net/http"
 	"testing"
 
@@ -37,6 +38,14 @@ func TestKeyAuth(t *testing.T) {
 			"auth": map[string]interface{}{
 				"keyAuth": `{"key":"tom"}`,
 			},
+		}).AddConsumer("summer", map[string]interface{}{
+			"auth": map[string]interface{}{
+				"keyAuth": `{"credentials":[
+					{"key":"summer"},
+					{"key":"summer-old","expiresAt":"2020-01-01T00:00:00Z"},
+					{"key":"summer-next","notBefore":"2999-01-01T00:00:00Z"}
+				]}`,
+			},
 		}),
 	})
 	if err != nil {
@@ -121,6 +130,33 @@ func TestKeyAuth(t *testing.T) {
 				assert.Equal(t, 401, resp.StatusCode)
 			},
 		},
+		{
+			name: "multiple credentials",
+			config: controlplane.NewPluginConfig([]*model.FilterConfig{
+				{
+					Name: "keyAuth",
+					Config: map[string]interface{}{
+						"keys": []interface{}{
+							map[string]interface{}{
+								"name": "Authorization",
+							},
+						},
+					},
+				},
+			}),
+			run: func(t *testing.T) {
+				resp, _ := dp.Get("/echo", http.Header{"Authorization": []string{"summer"}})
+				assert.Equal(t, 200, resp.StatusCode)
+				resp, _ = dp.Get("/echo", http.Header{"Authorization": []string{"summer-old"}})
+				assert.Equal(t, 401, resp.StatusCode)
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, `{"msg":"expired key"}`, string(body))
+				resp, _ = dp.Get("/echo", http.Header{"Authorization": []string{"summer-next"}})
+				assert.Equal(t, 401, resp.StatusCode)
+				body, _ = io.ReadAll(resp.Body)
+				assert.Equal(t, `{"msg":"invalid key"}`, string(body))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -24,13 +24,21 @@ type ConditionType string
 
 const (
 	ConditionAccepted ConditionType = "Accepted"
+	ConditionApplied  ConditionType = "Applied"
+	// ConditionSizeLimitApproached is reported when the configuration generated from the policy
+	// is close to the size limit
+	ConditionSizeLimitApproached ConditionType = "SizeLimitApproached"
 )
 
 type ConditionReason string
 
 const (
-	ReasonAccepted ConditionReason = "Accepted"
-	ReasonInvalid  ConditionReason = "Invalid"
+	ReasonAccepted            ConditionReason = "Accepted"
+	ReasonInvalid             ConditionReason = "Invalid"
+	ReasonApplied             ConditionReason = "Applied"
+	ReasonPending             ConditionReason = "Pending"
+	ReasonApplyFailed         ConditionReason = "ApplyFailed"
+	ReasonSizeLimitApproached ConditionReason = "SizeLimitApproached"
 )
 
 func needUpdateCondition(a, b metav1.Condition) bool {
@@ -87,6 +95,27 @@ func addOrUpdateAcceptedCondition(conditions []metav1.Condition,
 	return addOrUpdateCondition(conditions, c)
 }
 
+func addOrUpdateAppliedCondition(conditions []metav1.Condition,
+	observedGeneration int64, reason ConditionReason, msg string) ([]metav1.Condition, bool) {
+
+	c := metav1.Condition{
+		Type:               string(ConditionApplied),
+		Reason:             string(reason),
+		Message:            msg,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+		ObservedGeneration: observedGeneration,
+	}
+	switch reason {
+	case ReasonApplied:
+		c.Status = metav1.ConditionTrue
+	case ReasonApplyFailed:
+		c.Status = metav1.ConditionFalse
+	default:
+		c.Status = metav1.ConditionUnknown
+	}
+	return addOrUpdateCondition(conditions, c)
+}
+
 type ChangeDetector struct {
 	changed bool
 }
@@ -50,3 +50,55 @@ func TestAddOrUpdate(t *testing.T) {
 	assert.Equal(t, update, p.Status.Conditions[0])
 	assert.True(t, changed)
 }
+
+func TestDynamicConfigSetApplied(t *testing.T) {
+	c := &DynamicConfig{}
+	c.Generation = 1
+	c.SetAccepted(ReasonAccepted)
+	c.Status.Reset()
+
+	c.SetApplied(ReasonPending, "0/1 proxies applied version 1")
+	assert.True(t, c.Status.IsChanged())
+	assert.Equal(t, 2, len(c.Status.Conditions))
+	assert.Equal(t, metav1.ConditionUnknown, c.Status.Conditions[1].Status)
+
+	c.Status.Reset()
+	c.SetApplied(ReasonApplied, "1/1 proxies applied version 1")
+	assert.True(t, c.Status.IsChanged())
+	assert.Equal(t, metav1.ConditionTrue, c.Status.Conditions[1].Status)
+
+	c.Status.Reset()
+	c.SetApplied(ReasonApplied, "1/1 proxies applied version 1")
+	assert.False(t, c.Status.IsChanged())
+
+	// the Applied condition falls behind doesn't mean the spec is changed
+	c.Status.Conditions[1].ObservedGeneration = 0
+	assert.False(t, c.IsSpecChanged())
+
+	c.SetApplied(ReasonApplyFailed, "failed")
+	assert.Equal(t, metav1.ConditionFalse, c.Status.Conditions[1].Status)
+}
+
+func TestFilterPolicySetSizeLimitApproached(t *testing.T) {
+	p := &FilterPolicy{}
+	p.Generation = 1
+	p.SetAccepted(gwapiv1a2.PolicyReasonAccepted)
+	p.Status.Reset()
+
+	p.SetSizeLimitApproached("")
+	assert.False(t, p.Status.IsChanged())
+
+	p.SetSizeLimitApproached("route is large")
+	assert.True(t, p.Status.IsChanged())
+	assert.Equal(t, 2, len(p.Status.Conditions))
+	assert.Equal(t, metav1.ConditionTrue, p.Status.Conditions[1].Status)
+	assert.Equal(t, "route is large", p.Status.Conditions[1].Message)
+
+	p.Status.Reset()
+	p.SetSizeLimitApproached("route is large")
+	assert.False(t, p.Status.IsChanged())
+
+	p.SetSizeLimitApproached("")
+	assert.True(t, p.Status.IsChanged())
+	assert.Equal(t, 1, len(p.Status.Conditions))
+}
@@ -51,6 +51,12 @@ type ConsumerSpec struct {
 	//
 	// +optional
 	Name string `json:"name,omitempty"`
+
+	// Metadata is a map of free-form attributes of the consumer, like tenant, tier and org.
+	// It can be used by the plugins which run after the consumer is authenticated.
+	//
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // ConsumerStatus defines the observed state of Consumer
@@ -85,7 +91,8 @@ func (c *Consumer) Marshal() string {
 	}
 
 	consumer := &csModel.Consumer{
-		Auth: auth,
+		Auth:     auth,
+		Metadata: c.Spec.Metadata,
 	}
 
 	if len(c.Spec.Filters) > 0 {
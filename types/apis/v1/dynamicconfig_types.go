@@ -17,14 +17,53 @@ limitations under the License.
 package v1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DefaultRevisionHistoryLimit is the default number of prior versions kept for rollback
+const DefaultRevisionHistoryLimit int32 = 3
+
+const (
+	revisionConfigMapHashLen = 10
+	// 253 is the max length of a DNS subdomain
+	revisionConfigMapNameMaxPrefixLen = 253 - len("-rev-") - revisionConfigMapHashLen
+)
+
 // DynamicConfigSpec defines the desired state of DynamicConfig
 type DynamicConfigSpec struct {
 	Type   string               `json:"type"`
 	Config runtime.RawExtension `json:"config"`
+
+	// Version names the config. The generation is used as the version if it is not specified.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+	// RollbackTo is the version recorded in the history to roll back to. When it is set,
+	// the config of that version is dispatched instead of the config in the spec.
+	//
+	// +optional
+	RollbackTo string `json:"rollbackTo,omitempty"`
+	// RevisionHistoryLimit is the number of prior versions kept for rollback. Defaults to 3.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// DynamicConfigRevision is a version of the config which has been dispatched.
+// The config itself is stored in a ConfigMap to keep the DynamicConfig small.
+type DynamicConfigRevision struct {
+	Version string `json:"version"`
+	// Hash is the sha256 of the config
+	Hash string `json:"hash"`
+	// ConfigMap is the name of the ConfigMap which stores the config, in the same namespace of the DynamicConfig
+	ConfigMap string `json:"configMap"`
 }
 
 // DynamicConfigStatus defines the observed state of DynamicConfig
@@ -36,6 +75,11 @@ type DynamicConfigStatus struct {
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// History records the dispatched versions, the newest one at the end.
+	//
+	// +optional
+	History []DynamicConfigRevision `json:"history,omitempty"`
+
 	ChangeDetector `json:",inline"`
 }
 
@@ -58,6 +102,10 @@ func (c *DynamicConfig) IsSpecChanged() bool {
 		return true
 	}
 	for _, cond := range c.Status.Conditions {
+		// The Applied condition is updated after the data plane reports, so it may fall behind
+		if cond.Type == string(ConditionApplied) {
+			continue
+		}
 		if cond.ObservedGeneration != c.Generation {
 			return true
 		}
@@ -74,6 +122,109 @@ func (c *DynamicConfig) SetAccepted(reason ConditionReason, msg ...string) {
 	}
 }
 
+// SetApplied records whether the data plane has applied the DynamicConfig
+func (c *DynamicConfig) SetApplied(reason ConditionReason, msg string) {
+	conds, changed := addOrUpdateAppliedCondition(c.Status.Conditions, c.Generation, reason, msg)
+	c.Status.Conditions = conds
+
+	if changed {
+		c.Status.MarkAsChanged()
+	}
+}
+
+// CurrentVersion returns the version of the config in the spec
+func (c *DynamicConfig) CurrentVersion() string {
+	if c.Spec.Version != "" {
+		return c.Spec.Version
+	}
+	return strconv.FormatInt(c.Generation, 10)
+}
+
+// CurrentRevision returns the revision of the config in the spec
+func (c *DynamicConfig) CurrentRevision() *DynamicConfigRevision {
+	sum := sha256.Sum256(c.Spec.Config.Raw)
+	hash := hex.EncodeToString(sum[:])
+	name := c.Name
+	// leave room for the suffix, so the name is still a valid DNS subdomain
+	if len(name) > revisionConfigMapNameMaxPrefixLen {
+		name = name[:revisionConfigMapNameMaxPrefixLen]
+	}
+	return &DynamicConfigRevision{
+		Version:   c.CurrentVersion(),
+		Hash:      hash,
+		ConfigMap: strings.TrimRight(name, ".-") + "-rev-" + hash[:revisionConfigMapHashLen],
+	}
+}
+
+// DispatchedRevision returns the revision which should be dispatched to the data plane.
+// It returns nil if the version to roll back to is not found in the history.
+func (c *DynamicConfig) DispatchedRevision() *DynamicConfigRevision {
+	if c.Spec.RollbackTo == "" {
+		return c.CurrentRevision()
+	}
+
+	for i := len(c.Status.History) - 1; i >= 0; i-- {
+		if c.Status.History[i].Version == c.Spec.RollbackTo {
+			return &c.Status.History[i]
+		}
+	}
+	return nil
+}
+
+// IsRevisionRecorded returns true if the config in the spec is the newest one in the history
+func (c *DynamicConfig) IsRevisionRecorded() bool {
+	n := len(c.Status.History)
+	return n > 0 && c.Status.History[n-1] == *c.CurrentRevision()
+}
+
+// RecordRevision records the config in the spec into the history, and drops the versions beyond the limit.
+// It returns the dropped revisions whose ConfigMaps are no longer referred by the history.
+func (c *DynamicConfig) RecordRevision() []DynamicConfigRevision {
+	if c.IsRevisionRecorded() {
+		return nil
+	}
+
+	cur := c.CurrentRevision()
+	history := c.Status.History
+	revisions := make([]DynamicConfigRevision, 0, len(history)+1)
+	var dropped []DynamicConfigRevision
+	for _, rev := range history {
+		// the version name is reused
+		if rev.Version != cur.Version {
+			revisions = append(revisions, rev)
+		} else {
+			dropped = append(dropped, rev)
+		}
+	}
+	revisions = append(revisions, *cur)
+
+	limit := int(DefaultRevisionHistoryLimit)
+	if c.Spec.RevisionHistoryLimit != nil {
+		limit = int(*c.Spec.RevisionHistoryLimit)
+	}
+	// keep the current version and the prior versions
+	if len(revisions) > limit+1 {
+		dropped = append(dropped, revisions[:len(revisions)-limit-1]...)
+		revisions = revisions[len(revisions)-limit-1:]
+	}
+	c.Status.History = revisions
+	c.Status.MarkAsChanged()
+
+	// the same config may be recorded with different versions
+	referred := make(map[string]bool, len(revisions))
+	for _, rev := range revisions {
+		referred[rev.ConfigMap] = true
+	}
+	unreferred := dropped[:0]
+	for _, rev := range dropped {
+		if !referred[rev.ConfigMap] {
+			referred[rev.ConfigMap] = true
+			unreferred = append(unreferred, rev)
+		}
+	}
+	return unreferred
+}
+
 func (c *DynamicConfig) IsValid() bool {
 	for _, cond := range c.Status.Conditions {
 		if cond.ObservedGeneration != c.Generation {
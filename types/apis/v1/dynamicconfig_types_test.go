@@ -0,0 +1,89 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDynamicConfigRevision(t *testing.T) {
+	c := &DynamicConfig{}
+	c.Name = "dc"
+	update := func(generation int64, cfg string) []DynamicConfigRevision {
+		c.Generation = generation
+		c.Spec.Config = runtime.RawExtension{Raw: []byte(cfg)}
+		return c.RecordRevision()
+	}
+
+	assert.Empty(t, update(1, `{"a":1}`))
+	assert.True(t, c.Status.IsChanged())
+	rev := c.DispatchedRevision()
+	assert.Equal(t, "1", rev.Version)
+	assert.Equal(t, "015abd7f5cc57a2dd94b7590f04ad8084273905ee33ec5cebeae62276a97f862", rev.Hash)
+	assert.Equal(t, "dc-rev-015abd7f5c", rev.ConfigMap)
+	assert.True(t, c.IsRevisionRecorded())
+
+	c.Status.Reset()
+	c.RecordRevision()
+	assert.False(t, c.Status.IsChanged())
+
+	update(2, `{"a":2}`)
+	update(3, `{"a":3}`)
+	update(4, `{"a":4}`)
+	dropped := update(5, `{"a":5}`)
+	// the current one and 3 prior versions
+	assert.Equal(t, 4, len(c.Status.History))
+	assert.Equal(t, "2", c.Status.History[0].Version)
+	assert.Equal(t, 1, len(dropped))
+	assert.Equal(t, "1", dropped[0].Version)
+
+	c.Spec.RollbackTo = "3"
+	rev = c.DispatchedRevision()
+	assert.Equal(t, "3", rev.Version)
+	assert.Equal(t, c.Status.History[1].ConfigMap, rev.ConfigMap)
+
+	c.Spec.RollbackTo = "1"
+	assert.Nil(t, c.DispatchedRevision())
+	c.Spec.RollbackTo = ""
+
+	// named version
+	c.Spec.Version = "stable"
+	limit := int32(1)
+	c.Spec.RevisionHistoryLimit = &limit
+	update(6, `{"a":6}`)
+	assert.Equal(t, 2, len(c.Status.History))
+	assert.Equal(t, "stable", c.DispatchedRevision().Version)
+
+	// reuse the version name
+	dropped = update(7, `{"a":7}`)
+	assert.Equal(t, 2, len(c.Status.History))
+	assert.Equal(t, "5", c.Status.History[0].Version)
+	assert.Equal(t, c.CurrentRevision().Hash, c.Status.History[1].Hash)
+	assert.Equal(t, 1, len(dropped))
+	assert.Equal(t, "stable", dropped[0].Version)
+
+	// the dropped config is still referred
+	c.Spec.Version = "5"
+	assert.Empty(t, update(8, `{"a":5}`))
+	assert.Equal(t, 2, len(c.Status.History))
+
+	// long name
+	c.Name = strings.Repeat("a", 253)
+	assert.Equal(t, 253, len(c.CurrentRevision().ConfigMap))
+}
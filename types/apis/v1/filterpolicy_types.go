@@ -17,8 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"reflect"
+	"slices"
 	"time"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -35,9 +38,28 @@ type FilterPolicySpec struct {
 	// +optional
 	TargetRef *gwapiv1a2.PolicyTargetReferenceWithSectionName `json:"targetRef"`
 
+	// TargetSelector selects the resources this policy is being attached to by labels.
+	// Only the resources in the same namespace as the policy are selected.
+	// TargetSelector can't be used together with TargetRef.
+	//
+	// +optional
+	TargetSelector *FilterPolicyTargetSelector `json:"targetSelector,omitempty"`
+
 	// Filters is a map of filter names to filter configurations.
 	Filters map[string]Plugin `json:"filters,omitempty"`
 
+	// Defaults is a map of filter names to filter configurations, which can be overridden by
+	// the policies targeting a more specific scope. It works the same as Filters.
+	//
+	// +optional
+	Defaults map[string]Plugin `json:"defaults,omitempty"`
+
+	// Overrides is a map of filter names to filter configurations, which can't be overridden by
+	// the policies targeting a more specific scope.
+	//
+	// +optional
+	Overrides map[string]Plugin `json:"overrides,omitempty"`
+
 	// SubPolicies is an array of sub-policies to specific section name.
 	// If the specific section name is not found, the FilterPolicy will still be
 	// treated as accepted.
@@ -48,6 +70,17 @@ type FilterPolicySpec struct {
 	SubPolicies []FilterSubPolicy `json:"subPolicies,omitempty"`
 }
 
+// FilterPolicyTargetSelector selects the target resources by labels
+type FilterPolicyTargetSelector struct {
+	// Group is the group of the target resources.
+	Group gwapiv1.Group `json:"group"`
+	// Kind is kind of the target resources. Only VirtualService and HTTPRoute are supported.
+	Kind gwapiv1.Kind `json:"kind"`
+	// Selector is the label selector of the target resources.
+	// An empty selector selects all the resources with the given group and kind.
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
 // FilterSubPolicy defines the sub-policy
 type FilterSubPolicy struct {
 	// SectionName is the name of a section within the target resource.
@@ -68,9 +101,79 @@ type FilterPolicyStatus struct {
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// Targets are the resources this policy is attached to.
+	// It is only reported when the policy uses TargetSelector.
+	//
+	// +optional
+	Targets []FilterPolicyTargetStatus `json:"targets,omitempty"`
+
+	// Attachments are the routes and listeners where the policy is resolved to,
+	// with the plugins taking effect there and the plugins shadowed by other policies.
+	// At most 64 attachments are reported.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=64
+	Attachments []FilterPolicyAttachment `json:"attachments,omitempty"`
+
+	// TruncatedAttachments is the number of attachments which are not reported
+	// because the number of attachments exceeds the limit.
+	//
+	// +optional
+	TruncatedAttachments int32 `json:"truncatedAttachments,omitempty"`
+
 	ChangeDetector `json:",inline"`
 }
 
+// FilterPolicyTargetStatus describes a resource the policy is attached to
+type FilterPolicyTargetStatus struct {
+	Group gwapiv1.Group      `json:"group"`
+	Kind  gwapiv1.Kind       `json:"kind"`
+	Name  gwapiv1.ObjectName `json:"name"`
+}
+
+// MaxFilterPolicyAttachments is the max number of attachments reported in the status, so that the status
+// of a policy resolved to lots of routes doesn't exceed the size limit of the object.
+const MaxFilterPolicyAttachments = 64
+
+// FilterPolicyAttachmentKind is the kind of the place where the policy is attached
+type FilterPolicyAttachmentKind string
+
+const (
+	FilterPolicyAttachmentKindRoute    FilterPolicyAttachmentKind = "Route"
+	FilterPolicyAttachmentKindListener FilterPolicyAttachmentKind = "Listener"
+)
+
+// FilterPolicyAttachment describes a route or a listener the policy is resolved to
+type FilterPolicyAttachment struct {
+	// Kind is the kind of the attachment, either Route or Listener.
+	Kind FilterPolicyAttachmentKind `json:"kind"`
+	// Namespace is the namespace of the gateway workload.
+	Namespace string `json:"namespace"`
+	// VirtualHost is the name of the virtual host which contains the route.
+	// It is only set when the kind is Route.
+	//
+	// +optional
+	VirtualHost string `json:"virtualHost,omitempty"`
+	// Name is the name of the route or the listener.
+	Name string `json:"name"`
+	// Plugins are the plugins of this policy which take effect here.
+	//
+	// +optional
+	Plugins []string `json:"plugins,omitempty"`
+	// ShadowedPlugins are the plugins of this policy which are overridden by other policies.
+	//
+	// +optional
+	ShadowedPlugins []FilterPolicyShadowedPlugin `json:"shadowedPlugins,omitempty"`
+}
+
+// FilterPolicyShadowedPlugin describes a plugin overridden by another policy
+type FilterPolicyShadowedPlugin struct {
+	// Name is the name of the plugin.
+	Name string `json:"name"`
+	// By is the {namespace}/{name} of the policy whose plugin takes effect.
+	By string `json:"by"`
+}
+
 //+genclient
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
@@ -100,6 +203,11 @@ func (p *FilterPolicy) IsSpecChanged() bool {
 	return false
 }
 
+// PolicyReasonRejected is used in the Accepted condition when the policy is valid but can't be applied in the
+// current environment, for example, it uses a plugin which is not supported by the output. Unlike the Invalid
+// reason, which is kept until the policy is changed, the rejection is re-evaluated in each reconciliation.
+const PolicyReasonRejected gwapiv1a2.PolicyConditionReason = "Rejected"
+
 func (p *FilterPolicy) SetAccepted(reason gwapiv1a2.PolicyConditionReason, msg ...string) {
 	c := metav1.Condition{
 		Type:               string(gwapiv1a2.PolicyConditionAccepted),
@@ -125,6 +233,13 @@ func (p *FilterPolicy) SetAccepted(reason gwapiv1a2.PolicyConditionReason, msg .
 		} else {
 			c.Message = "The policy targets non-existent resource"
 		}
+	case PolicyReasonRejected:
+		c.Status = metav1.ConditionFalse
+		if len(msg) > 0 {
+			c.Message = msg[0]
+		} else {
+			c.Message = "The policy is rejected"
+		}
 	}
 	conds, changed := addOrUpdateCondition(p.Status.Conditions, c)
 	p.Status.Conditions = conds
@@ -134,6 +249,59 @@ func (p *FilterPolicy) SetAccepted(reason gwapiv1a2.PolicyConditionReason, msg .
 	}
 }
 
+// SetSizeLimitApproached reports that the configuration generated from the policy is close to the
+// size limit. The condition is removed if the message is empty.
+func (p *FilterPolicy) SetSizeLimitApproached(msg string) {
+	if msg == "" {
+		if apimeta.RemoveStatusCondition(&p.Status.Conditions, string(ConditionSizeLimitApproached)) {
+			p.Status.MarkAsChanged()
+		}
+		return
+	}
+
+	c := metav1.Condition{
+		Type:               string(ConditionSizeLimitApproached),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(ReasonSizeLimitApproached),
+		Message:            msg,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+		ObservedGeneration: p.Generation,
+	}
+	conds, changed := addOrUpdateCondition(p.Status.Conditions, c)
+	p.Status.Conditions = conds
+
+	if changed {
+		p.Status.MarkAsChanged()
+	}
+}
+
+// SetTargets updates the attached targets in the status. The targets should be sorted.
+func (p *FilterPolicy) SetTargets(targets []FilterPolicyTargetStatus) {
+	if slices.Equal(p.Status.Targets, targets) {
+		return
+	}
+
+	p.Status.Targets = targets
+	p.Status.MarkAsChanged()
+}
+
+// SetAttachments updates the attachments in the status. The attachments should be sorted.
+// Only the first MaxFilterPolicyAttachments attachments are kept, and the rest are counted.
+func (p *FilterPolicy) SetAttachments(attachments []FilterPolicyAttachment) {
+	var truncated int32
+	if len(attachments) > MaxFilterPolicyAttachments {
+		truncated = int32(len(attachments) - MaxFilterPolicyAttachments)
+		attachments = attachments[:MaxFilterPolicyAttachments]
+	}
+	if reflect.DeepEqual(p.Status.Attachments, attachments) && p.Status.TruncatedAttachments == truncated {
+		return
+	}
+
+	p.Status.Attachments = attachments
+	p.Status.TruncatedAttachments = truncated
+	p.Status.MarkAsChanged()
+}
+
 func (p *FilterPolicy) IsValid() bool {
 	for _, cond := range p.Status.Conditions {
 		if cond.ObservedGeneration != p.Generation {
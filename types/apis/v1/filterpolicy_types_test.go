@@ -0,0 +1,69 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestFilterPolicySetAttachments(t *testing.T) {
+	attachments := make([]FilterPolicyAttachment, MaxFilterPolicyAttachments+2)
+	for i := range attachments {
+		attachments[i] = FilterPolicyAttachment{
+			Kind:      FilterPolicyAttachmentKindRoute,
+			Namespace: "default",
+			Name:      fmt.Sprintf("route-%d", i),
+		}
+	}
+
+	p := &FilterPolicy{}
+	p.SetAttachments(attachments[:1])
+	assert.True(t, p.Status.IsChanged())
+	assert.Equal(t, 1, len(p.Status.Attachments))
+	assert.Equal(t, int32(0), p.Status.TruncatedAttachments)
+
+	p.Status.Reset()
+	p.SetAttachments(attachments)
+	assert.True(t, p.Status.IsChanged())
+	assert.Equal(t, MaxFilterPolicyAttachments, len(p.Status.Attachments))
+	assert.Equal(t, int32(2), p.Status.TruncatedAttachments)
+
+	p.Status.Reset()
+	p.SetAttachments(attachments)
+	assert.False(t, p.Status.IsChanged())
+
+	// only the truncated number is changed
+	p.SetAttachments(attachments[:MaxFilterPolicyAttachments+1])
+	assert.True(t, p.Status.IsChanged())
+	assert.Equal(t, int32(1), p.Status.TruncatedAttachments)
+}
+
+func TestFilterPolicyRejected(t *testing.T) {
+	p := &FilterPolicy{}
+	p.Generation = 1
+	p.SetAccepted(PolicyReasonRejected, "unsupported plugin")
+	assert.True(t, p.Status.IsChanged())
+	assert.Equal(t, "unsupported plugin", p.Status.Conditions[0].Message)
+	// the rejected policy is re-evaluated, so it's not treated as invalid
+	assert.True(t, p.IsValid())
+	assert.False(t, p.IsSpecChanged())
+
+	p.SetAccepted(gwapiv1a2.PolicyReasonInvalid)
+	assert.False(t, p.IsValid())
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	istiov1a3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -121,7 +122,15 @@ func ValidateHTTPFilterPolicyStrictly(policy *HTTPFilterPolicy) error {
 	return ValidateFilterPolicyStrictly(&p)
 }
 
-func validateFilter(name string, filter Plugin, strict bool, targetGateway bool) error {
+type policyTarget int
+
+const (
+	policyTargetRoute   policyTarget = iota // VirtualService, HTTPRoute and GRPCRoute
+	policyTargetL4Route                     // TCPRoute and TLSRoute
+	policyTargetGateway                     // Istio/k8s Gateway
+)
+
+func validateFilter(name string, filter Plugin, strict bool, target policyTarget) error {
 	p := plugins.LoadPluginType(name)
 	if p == nil {
 		if strict {
@@ -130,7 +139,8 @@ func validateFilter(name string, filter Plugin, strict bool, targetGateway bool)
 		return nil
 	}
 
-	if targetGateway {
+	switch target {
+	case policyTargetGateway:
 		switch p.Order().Position {
 		case plugins.OrderPositionOuter, plugins.OrderPositionInner:
 			// We can't directly provide different ECDS for every native plugins. There will
@@ -139,7 +149,14 @@ func validateFilter(name string, filter Plugin, strict bool, targetGateway bool)
 			// composite filter to solve this problem?
 			return errors.New("configure native plugins to the Gateway is not implemented")
 		}
-	} else {
+	case policyTargetL4Route:
+		// TCPRoute and TLSRoute don't have HTTP routes, so only the layer 4 plugins make sense
+		switch p.Order().Position {
+		case plugins.OrderPositionListener, plugins.OrderPositionNetwork:
+		default:
+			return errors.New("only layer 4 plugins can be configured to TCPRoute or TLSRoute")
+		}
+	default:
 		switch p.Order().Position {
 		case plugins.OrderPositionListener, plugins.OrderPositionNetwork:
 			return errors.New("configure layer 4 plugins to route is invalid")
@@ -164,8 +181,42 @@ func validateFilter(name string, filter Plugin, strict bool, targetGateway bool)
 	return nil
 }
 
+// validateFilterPolicyPlugins validates the plugins in filters, defaults and overrides.
+// A plugin can only be configured in one of them.
+func validateFilterPolicyPlugins(spec *FilterPolicySpec, strict bool, target policyTarget) error {
+	sections := []struct {
+		name    string
+		filters map[string]Plugin
+	}{
+		{"filters", spec.Filters},
+		{"defaults", spec.Defaults},
+		{"overrides", spec.Overrides},
+	}
+	seen := map[string]string{}
+	for _, section := range sections {
+		for name, filter := range section.filters {
+			if prev, ok := seen[name]; ok {
+				return fmt.Errorf("filter %s can not be configured in both %s and %s", name, prev, section.name)
+			}
+			seen[name] = section.name
+
+			err := validateFilter(name, filter, strict, target)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func validateFilterPolicy(policy *FilterPolicy, strict bool) error {
-	targetGateway := false
+	if policy.Spec.TargetSelector != nil {
+		if policy.Spec.TargetRef != nil {
+			return errors.New("targetRef and targetSelector can not be used together")
+		}
+		return validateFilterPolicyWithTargetSelector(policy, strict)
+	}
+
 	ref := policy.Spec.TargetRef
 	if ref == nil {
 		return errors.New("targetRef is required")
@@ -183,8 +234,9 @@ func validateFilterPolicy(policy *FilterPolicy, strict bool) error {
 			return errors.New("targetRef.SectionName and SubPolicies can not be used together")
 		}
 
-		if ref.Kind == "HTTPRoute" {
-			return errors.New("targetRef.SectionName is not supported for HTTPRoute")
+		switch ref.Kind {
+		case "HTTPRoute", "GRPCRoute", "TCPRoute", "TLSRoute":
+			return fmt.Errorf("targetRef.SectionName is not supported for %s", ref.Kind)
 		}
 	}
 
@@ -207,13 +259,25 @@ func validateFilterPolicy(policy *FilterPolicy, strict bool) error {
 		switch ref.Kind {
 		case "HTTPRoute", "Gateway":
 			validTarget = true
+		case "GRPCRoute", "TCPRoute", "TLSRoute":
+			// These routes are only available in the experimental channel. To target FilterPolicy to them,
+			// ensure environment variable "HTNN_ENABLE_ALPHA_GATEWAY_API" is set to "true" in the controller.
+			// For TCPRoute and TLSRoute, the "HTNN_ENABLE_LDS_PLUGIN_VIA_ECDS" is also required, as the policy
+			// is applied to the listeners which the route is attached to.
+			validTarget = true
 		}
 	}
 	if !validTarget {
 		return errors.New("unsupported targetRef.group or targetRef.kind")
 	}
 
-	targetGateway = ref.Kind == "Gateway"
+	target := policyTargetRoute
+	switch ref.Kind {
+	case "Gateway":
+		target = policyTargetGateway
+	case "TCPRoute", "TLSRoute":
+		target = policyTargetL4Route
+	}
 
 	if len(policy.Spec.SubPolicies) > 0 {
 		if ref.Kind != "VirtualService" {
@@ -221,11 +285,8 @@ func validateFilterPolicy(policy *FilterPolicy, strict bool) error {
 		}
 	}
 
-	for name, filter := range policy.Spec.Filters {
-		err := validateFilter(name, filter, strict, targetGateway)
-		if err != nil {
-			return err
-		}
+	if err := validateFilterPolicyPlugins(&policy.Spec, strict, target); err != nil {
+		return err
 	}
 
 	names := map[string]struct{}{}
@@ -244,7 +305,7 @@ func validateFilterPolicy(policy *FilterPolicy, strict bool) error {
 		names[string(policy.SectionName)] = struct{}{}
 
 		for name, filter := range policy.Filters {
-			err := validateFilter(name, filter, strict, targetGateway)
+			err := validateFilter(name, filter, strict, target)
 			if err != nil {
 				return err
 			}
@@ -255,6 +316,26 @@ func validateFilterPolicy(policy *FilterPolicy, strict bool) error {
 	return nil
 }
 
+func validateFilterPolicyWithTargetSelector(policy *FilterPolicy, strict bool) error {
+	sel := policy.Spec.TargetSelector
+	validTarget := (sel.Group == "networking.istio.io" && sel.Kind == "VirtualService") ||
+		(sel.Group == "gateway.networking.k8s.io" && sel.Kind == "HTTPRoute")
+	if !validTarget {
+		return errors.New("unsupported targetSelector.group or targetSelector.kind")
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&sel.Selector); err != nil {
+		return fmt.Errorf("invalid targetSelector.selector: %w", err)
+	}
+
+	// The section names are different between the selected resources
+	if len(policy.Spec.SubPolicies) > 0 {
+		return errors.New("subPolicies can not be used with targetSelector")
+	}
+
+	return validateFilterPolicyPlugins(&policy.Spec, strict, policyTargetRoute)
+}
+
 func ValidateVirtualService(vs *istiov1a3.VirtualService) error {
 	if len(vs.Spec.Http) == 0 {
 		return errors.New("only http route is supported")
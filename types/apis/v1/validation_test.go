@@ -59,6 +59,134 @@ func TestValidateFilterPolicy(t *testing.T) {
 			},
 			err: "targetRef is required",
 		},
+		{
+			name: "ok, TargetSelector with HTTPRoute",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetSelector: &FilterPolicyTargetSelector{
+						Group:    "gateway.networking.k8s.io",
+						Kind:     "HTTPRoute",
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "public"}},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ok, TargetSelector with VirtualService",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetSelector: &FilterPolicyTargetSelector{
+						Group:    "networking.istio.io",
+						Kind:     "VirtualService",
+						Selector: metav1.LabelSelector{},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TargetSelector with TargetRef",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "HTTPRoute",
+						},
+					},
+					TargetSelector: &FilterPolicyTargetSelector{
+						Group:    "gateway.networking.k8s.io",
+						Kind:     "HTTPRoute",
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "public"}},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+			err: "targetRef and targetSelector can not be used together",
+		},
+		{
+			name: "unsupported kind, TargetSelector",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetSelector: &FilterPolicyTargetSelector{
+						Group:    "gateway.networking.k8s.io",
+						Kind:     "Gateway",
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "public"}},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+			err: "unsupported targetSelector.group or targetSelector.kind",
+		},
+		{
+			name: "invalid selector, TargetSelector",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetSelector: &FilterPolicyTargetSelector{
+						Group: "gateway.networking.k8s.io",
+						Kind:  "HTTPRoute",
+						Selector: metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "tier", Operator: "Unknown"},
+							},
+						},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+			err: "invalid targetSelector.selector: \"Unknown\" is not a valid label selector operator",
+		},
+		{
+			name: "l4 plugin, TargetSelector",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetSelector: &FilterPolicyTargetSelector{
+						Group:    "gateway.networking.k8s.io",
+						Kind:     "HTTPRoute",
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "public"}},
+					},
+					Filters: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+			err: "configure layer 4 plugins to route is invalid",
+		},
 		{
 			name: "ok, VirtualService",
 			policy: &FilterPolicy{
@@ -205,6 +333,228 @@ func TestValidateFilterPolicy(t *testing.T) {
 			},
 			strictErr: "unknown field \"unknown_fields\"",
 		},
+		{
+			name: "ok, GRPCRoute",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "GRPCRoute",
+						},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unsupported, GRPCRoute with sectionName",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "GRPCRoute",
+						},
+						SectionName: &sectionName,
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+			err: "targetRef.SectionName is not supported for GRPCRoute",
+		},
+		{
+			name: "l4 plugin, GRPCRoute",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "GRPCRoute",
+						},
+					},
+					Filters: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+			err: "configure layer 4 plugins to route is invalid",
+		},
+		{
+			name: "ok, TCPRoute",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "TCPRoute",
+						},
+					},
+					Filters: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ok, TLSRoute",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "TLSRoute",
+						},
+					},
+					Filters: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "http plugin, TCPRoute",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "TCPRoute",
+						},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+				},
+			},
+			err: "only layer 4 plugins can be configured to TCPRoute or TLSRoute",
+		},
+		{
+			name: "defaults and overrides",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "Gateway",
+						},
+					},
+					Defaults: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+					Overrides: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid plugin in overrides",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "HTTPRoute",
+						},
+					},
+					Overrides: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+			err: "configure layer 4 plugins to route is invalid",
+		},
+		{
+			name: "same plugin in filters and overrides",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "HTTPRoute",
+						},
+					},
+					Filters: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"cat"}`),
+							},
+						},
+					},
+					Overrides: map[string]Plugin{
+						"animal": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"pet":"dog"}`),
+							},
+						},
+					},
+				},
+			},
+			err: "filter animal can not be configured in both filters and overrides",
+		},
+		{
+			name: "unsupported, TLSRoute with sectionName",
+			policy: &FilterPolicy{
+				Spec: FilterPolicySpec{
+					TargetRef: &gwapiv1a2.PolicyTargetReferenceWithSectionName{
+						PolicyTargetReference: gwapiv1a2.PolicyTargetReference{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "TLSRoute",
+						},
+						SectionName: &sectionName,
+					},
+					Filters: map[string]Plugin{
+						"networkNative": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{}`),
+							},
+						},
+					},
+				},
+			},
+			err: "targetRef.SectionName is not supported for TLSRoute",
+		},
 		{
 			name: "unknown",
 			policy: &FilterPolicy{
@@ -827,6 +1177,23 @@ func TestValidateConsumer(t *testing.T) {
 			},
 			err: "this http filter can not be added by the consumer: keyAuth",
 		},
+		{
+			name: "metadata",
+			consumer: &Consumer{
+				Spec: ConsumerSpec{
+					Auth: map[string]ConsumerPlugin{
+						"keyAuth": {
+							Config: runtime.RawExtension{
+								Raw: []byte(`{"key":"cat"}`),
+							},
+						},
+					},
+					Metadata: map[string]string{
+						"tenant": "t1",
+					},
+				},
+			},
+		},
 		{
 			name: "empty",
 			consumer: &Consumer{
@@ -133,6 +133,13 @@ func (in *ConsumerSpec) DeepCopyInto(out *ConsumerSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerSpec.
@@ -227,10 +234,30 @@ func (in *DynamicConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicConfigRevision) DeepCopyInto(out *DynamicConfigRevision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfigRevision.
+func (in *DynamicConfigRevision) DeepCopy() *DynamicConfigRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicConfigRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicConfigSpec) DeepCopyInto(out *DynamicConfigSpec) {
 	*out = *in
 	in.Config.DeepCopyInto(&out.Config)
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicConfigSpec.
@@ -253,6 +280,11 @@ func (in *DynamicConfigStatus) DeepCopyInto(out *DynamicConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DynamicConfigRevision, len(*in))
+		copy(*out, *in)
+	}
 	out.ChangeDetector = in.ChangeDetector
 }
 
@@ -293,6 +325,31 @@ func (in *FilterPolicy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterPolicyAttachment) DeepCopyInto(out *FilterPolicyAttachment) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ShadowedPlugins != nil {
+		in, out := &in.ShadowedPlugins, &out.ShadowedPlugins
+		*out = make([]FilterPolicyShadowedPlugin, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterPolicyAttachment.
+func (in *FilterPolicyAttachment) DeepCopy() *FilterPolicyAttachment {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterPolicyAttachment)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilterPolicyList) DeepCopyInto(out *FilterPolicyList) {
 	*out = *in
@@ -325,6 +382,21 @@ func (in *FilterPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterPolicyShadowedPlugin) DeepCopyInto(out *FilterPolicyShadowedPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterPolicyShadowedPlugin.
+func (in *FilterPolicyShadowedPlugin) DeepCopy() *FilterPolicyShadowedPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterPolicyShadowedPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilterPolicySpec) DeepCopyInto(out *FilterPolicySpec) {
 	*out = *in
@@ -333,6 +405,11 @@ func (in *FilterPolicySpec) DeepCopyInto(out *FilterPolicySpec) {
 		*out = new(v1alpha2.PolicyTargetReferenceWithSectionName)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TargetSelector != nil {
+		in, out := &in.TargetSelector, &out.TargetSelector
+		*out = new(FilterPolicyTargetSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Filters != nil {
 		in, out := &in.Filters, &out.Filters
 		*out = make(map[string]Plugin, len(*in))
@@ -340,6 +417,20 @@ func (in *FilterPolicySpec) DeepCopyInto(out *FilterPolicySpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = make(map[string]Plugin, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make(map[string]Plugin, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.SubPolicies != nil {
 		in, out := &in.SubPolicies, &out.SubPolicies
 		*out = make([]FilterSubPolicy, len(*in))
@@ -369,6 +460,18 @@ func (in *FilterPolicyStatus) DeepCopyInto(out *FilterPolicyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]FilterPolicyTargetStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attachments != nil {
+		in, out := &in.Attachments, &out.Attachments
+		*out = make([]FilterPolicyAttachment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.ChangeDetector = in.ChangeDetector
 }
 
@@ -382,6 +485,37 @@ func (in *FilterPolicyStatus) DeepCopy() *FilterPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterPolicyTargetSelector) DeepCopyInto(out *FilterPolicyTargetSelector) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterPolicyTargetSelector.
+func (in *FilterPolicyTargetSelector) DeepCopy() *FilterPolicyTargetSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterPolicyTargetSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterPolicyTargetStatus) DeepCopyInto(out *FilterPolicyTargetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterPolicyTargetStatus.
+func (in *FilterPolicyTargetStatus) DeepCopy() *FilterPolicyTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterPolicyTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilterSubPolicy) DeepCopyInto(out *FilterSubPolicy) {
 	*out = *in
@@ -15,5 +15,8 @@
 package dynamicconfigs
 
 import (
+	_ "mosn.io/htnn/types/dynamicconfigs/consumerstore"
 	_ "mosn.io/htnn/types/dynamicconfigs/demo"
+	_ "mosn.io/htnn/types/dynamicconfigs/featureflags"
+	_ "mosn.io/htnn/types/dynamicconfigs/iplist"
 )
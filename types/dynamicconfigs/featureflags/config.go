@@ -0,0 +1,64 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/types/pkg/expr"
+)
+
+const (
+	Name = "featureFlags"
+)
+
+func init() {
+	dynamicconfig.RegisterDynamicConfigProvider(Name, &Provider{})
+}
+
+type Provider struct {
+}
+
+func (p *Provider) Config() dynamicconfig.DynamicConfig {
+	return &CustomConfig{}
+}
+
+type CustomConfig struct {
+	Config
+}
+
+func (conf *CustomConfig) Validate() error {
+	err := conf.Config.Validate()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(conf.Flags))
+	for _, flag := range conf.Flags {
+		if seen[flag.Name] {
+			return fmt.Errorf("duplicate flag %s", flag.Name)
+		}
+		seen[flag.Name] = true
+
+		_, err = expr.CompileCel(flag.Rule, cel.BoolType)
+		if err != nil {
+			return fmt.Errorf("invalid rule of flag %s: %w", flag.Name, err)
+		}
+	}
+	return nil
+}
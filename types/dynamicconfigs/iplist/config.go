@@ -0,0 +1,84 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iplist
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+)
+
+const (
+	Name = "ipList"
+)
+
+func init() {
+	dynamicconfig.RegisterDynamicConfigProvider(Name, &Provider{})
+}
+
+type Provider struct {
+}
+
+func (p *Provider) Config() dynamicconfig.DynamicConfig {
+	return &CustomConfig{}
+}
+
+type CustomConfig struct {
+	Config
+}
+
+// ParseCIDR parses the IP address or CIDR into a masked prefix. An IP address is treated as
+// a prefix which only contains itself.
+func ParseCIDR(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return p.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func (conf *CustomConfig) Validate() error {
+	err := conf.Config.Validate()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(conf.Lists))
+	for _, list := range conf.Lists {
+		if seen[list.Name] {
+			return fmt.Errorf("duplicate list %s", list.Name)
+		}
+		seen[list.Name] = true
+
+		for _, cidr := range list.Cidrs {
+			_, err = ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("invalid cidr in list %s: %w", list.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ package expr
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net"
 	"reflect"
 	"strconv"
@@ -42,6 +43,8 @@ var (
 			cel.CustomTypeAdapter(&customTypeAdapter{}),
 			defineRequest(),
 			defineSource(),
+			defineConsumer(),
+			defineFunctions(),
 		}
 
 		var err error
@@ -87,8 +90,9 @@ func CompileCel(expr string, returnType *cel.Type) (Script, error) {
 var varsPool = sync.Pool{
 	New: func() any {
 		return map[string]any{
-			"request": &request{},
-			"source":  &source{},
+			"request":  &request{},
+			"source":   &source{},
+			"consumer": &consumer{},
 		}
 	},
 }
@@ -110,11 +114,17 @@ func (s *CelScript) EvalWithRequest(cb api.FilterCallbackHandler, headers api.Re
 		return nil, fmt.Errorf("unexpected source type: %s", reflect.TypeOf(vars["source"]))
 	}
 	so.callback = cb
+	c, ok := vars["consumer"].(*consumer)
+	if !ok {
+		return nil, fmt.Errorf("unexpected consumer type: %s", reflect.TypeOf(vars["consumer"]))
+	}
+	c.callback = cb
 
 	res, _, err := s.program.Eval(vars)
 	r.headers = nil
 	r.callback = nil
 	so.callback = nil
+	c.callback = nil
 	varsPool.Put(vars)
 
 	if err != nil {
@@ -341,6 +351,95 @@ func (s *source) TypeName() string {
 	return sourceType.TypeName()
 }
 
+type consumer struct {
+	customType
+	callback api.FilterCallbackHandler
+}
+
+var consumerType = cel.ObjectType("htnn.consumer", traits.ReceiverType)
+var consumerExprType = decls.NewObjectType("htnn.consumer")
+
+func defineConsumer() cel.EnvOption {
+	cls := "consumer"
+	declarations := []*exprpb.Decl{
+		decls.NewConst(cls, consumerExprType, nil),
+	}
+
+	for _, dec := range []struct {
+		method         string
+		parameterTypes []*exprpb.Type
+		returnType     *exprpb.Type
+	}{
+		{
+			method:         "name",
+			parameterTypes: []*exprpb.Type{},
+			returnType:     decls.String,
+		},
+		{
+			method:         "labels",
+			parameterTypes: []*exprpb.Type{},
+			returnType:     decls.NewMapType(decls.String, decls.String),
+		},
+	} {
+		declarations = append(declarations,
+			decls.NewFunction(dec.method,
+				decls.NewInstanceOverload(fmt.Sprintf("%s_%s", cls, dec.method),
+					append([]*exprpb.Type{consumerExprType}, dec.parameterTypes...), dec.returnType)),
+		)
+	}
+	return cel.Declarations(declarations...)
+}
+
+func (c *consumer) Receive(function string, overload string, args []ref.Val) ref.Val {
+	// The consumer is only available after the authentication. Return the zero value if no consumer is set.
+	cs := c.callback.GetConsumer()
+	switch function {
+	case "name":
+		if cs == nil {
+			return types.String("")
+		}
+		return types.String(cs.Name())
+	case "labels":
+		var labels map[string]string
+		if cs != nil {
+			labels = cs.Metadata()
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		return types.DefaultTypeAdapter.NativeToValue(labels)
+	}
+
+	return types.NewErr("no such function - %s", function)
+}
+
+func (c *consumer) TypeName() string {
+	return consumerType.TypeName()
+}
+
+func defineFunctions() cel.EnvOption {
+	// percent maps the given string to [0, 100) consistently, which can be used to do percent rollout
+	// by the hash of consumer name or client IP.
+	return cel.Function("percent",
+		cel.Overload("percent_string", []*cel.Type{cel.StringType}, cel.IntType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val {
+				s, ok := v.Value().(string)
+				if !ok {
+					return types.NewErr("unexpected type: %s", reflect.TypeOf(v.Value()))
+				}
+				return types.Int(Percent(s))
+			}),
+		),
+	)
+}
+
+// Percent maps the given string to [0, 100) consistently
+func Percent(s string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return int(h.Sum32() % 100)
+}
+
 type customType struct {
 }
 
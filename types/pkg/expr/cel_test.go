@@ -16,6 +16,7 @@ package expr
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 	"testing"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/google/cel-go/common/types"
 	"github.com/stretchr/testify/require"
 
+	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
 )
 
@@ -257,3 +259,101 @@ func TestCelWithSource(t *testing.T) {
 		})
 	}
 }
+
+type celTestConsumer struct {
+	name     string
+	metadata map[string]string
+}
+
+func (c *celTestConsumer) Name() string {
+	return c.name
+}
+
+func (c *celTestConsumer) PluginConfig(_ string) api.PluginConsumerConfig {
+	return nil
+}
+
+func (c *celTestConsumer) Metadata() map[string]string {
+	return c.metadata
+}
+
+func TestCelWithConsumer(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		consumer api.Consumer
+		expect   any
+	}{
+		{
+			name: "name",
+			code: `consumer.name()`,
+			consumer: &celTestConsumer{
+				name: "leo",
+			},
+			expect: "leo",
+		},
+		{
+			name:   "no consumer",
+			code:   `consumer.name()`,
+			expect: "",
+		},
+		{
+			name: "labels",
+			code: `consumer.labels()["tier"] == "gold" && !("org" in consumer.labels())`,
+			consumer: &celTestConsumer{
+				metadata: map[string]string{
+					"tier": "gold",
+				},
+			},
+			expect: true,
+		},
+		{
+			name:   "labels without consumer",
+			code:   `"tier" in consumer.labels()`,
+			expect: false,
+		},
+		{
+			name: "percent",
+			code: `percent(consumer.name()) == ` + strconv.Itoa(Percent("leo")),
+			consumer: &celTestConsumer{
+				name: "leo",
+			},
+			expect: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var typ *cel.Type
+			switch tt.expect.(type) {
+			case string:
+				typ = cel.StringType
+			case bool:
+				typ = cel.BoolType
+			}
+			s, err := CompileCel(tt.code, typ)
+			require.NoError(t, err)
+			cb := envoy.NewFilterCallbackHandler()
+			if tt.consumer != nil {
+				cb.SetConsumer(tt.consumer)
+			}
+			res, err := s.EvalWithRequest(cb, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, res)
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	hit := 0
+	for i := 0; i < 10000; i++ {
+		p := Percent(strconv.Itoa(i))
+		require.True(t, p >= 0 && p < 100)
+		if p < 10 {
+			hit++
+		}
+	}
+	// roughly 10%
+	require.InDelta(t, 1000, hit, 200)
+	require.Equal(t, Percent("a"), Percent("a"))
+}
@@ -0,0 +1,303 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema generates JSON Schema from the protobuf messages, including the constraints
+// defined via protoc-gen-validate. The generated schema describes the JSON form of the message
+// accepted by protojson, which is how the plugin configuration is parsed.
+package jsonschema
+
+import (
+	"sort"
+
+	"github.com/envoyproxy/protoc-gen-validate/validate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Draft is the JSON Schema draft used by the generated schema. Draft-07 is chosen as it's widely
+// supported by the editors and the validators.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema document. It can be marshaled to JSON directly.
+type Schema map[string]interface{}
+
+type generator struct {
+	defs map[string]Schema
+}
+
+// FromMessage returns the JSON Schema of the given message. The nested messages are put into `$defs`
+// and referred by their full names, so recursive messages are supported.
+func FromMessage(msg proto.Message) Schema {
+	g := &generator{defs: map[string]Schema{}}
+	schema := g.message(msg.ProtoReflect().Descriptor())
+	schema["$schema"] = Draft
+	if len(g.defs) > 0 {
+		defs := make(map[string]interface{}, len(g.defs))
+		for name, def := range g.defs {
+			defs[name] = def
+		}
+		schema["$defs"] = defs
+	}
+	return schema
+}
+
+func (g *generator) ref(md protoreflect.MessageDescriptor) Schema {
+	if s, ok := wellKnownTypes[md.FullName()]; ok {
+		return s()
+	}
+
+	name := string(md.FullName())
+	if _, ok := g.defs[name]; !ok {
+		// put a placeholder first to break the recursion
+		g.defs[name] = nil
+		g.defs[name] = g.message(md)
+	}
+	return Schema{"$ref": "#/$defs/" + name}
+}
+
+func (g *generator) message(md protoreflect.MessageDescriptor) Schema {
+	props := map[string]interface{}{}
+	var required []string
+	var constraints []interface{}
+
+	disabled := proto.GetExtension(md.Options(), validate.E_Disabled).(bool) ||
+		proto.GetExtension(md.Options(), validate.E_Ignored).(bool)
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		var rules *validate.FieldRules
+		if !disabled {
+			rules, _ = proto.GetExtension(fd.Options(), validate.E_Rules).(*validate.FieldRules)
+		}
+
+		s := g.field(fd, rules)
+		props[fd.JSONName()] = s
+		// The field in oneof or with explicit presence is only validated when it's set
+		if fd.ContainingOneof() == nil && isRequired(fd, rules) {
+			required = append(required, fd.JSONName())
+		}
+	}
+
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		oneofRequired := !disabled && proto.GetExtension(od.Options(), validate.E_Required).(bool)
+		if c := oneofConstraint(od, oneofRequired); c != nil {
+			constraints = append(constraints, c)
+		}
+	}
+
+	schema := Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	if len(constraints) == 1 {
+		for k, v := range constraints[0].(Schema) {
+			schema[k] = v
+		}
+	} else if len(constraints) > 1 {
+		schema["allOf"] = constraints
+	}
+	return schema
+}
+
+// oneofConstraint returns the constraint that at most one field of the oneof is set. If the oneof is
+// required, exactly one field should be set.
+func oneofConstraint(od protoreflect.OneofDescriptor, required bool) Schema {
+	fields := od.Fields()
+	if fields.Len() < 2 && !required {
+		return nil
+	}
+
+	choices := make([]interface{}, 0, fields.Len()+1)
+	for i := 0; i < fields.Len(); i++ {
+		choices = append(choices, Schema{"required": []string{fields.Get(i).JSONName()}})
+	}
+	if !required {
+		choices = append(choices, Schema{"not": Schema{"anyOf": choices[:len(choices):len(choices)]}})
+	}
+	return Schema{"oneOf": choices}
+}
+
+func (g *generator) field(fd protoreflect.FieldDescriptor, rules *validate.FieldRules) Schema {
+	switch {
+	case fd.IsMap():
+		s := Schema{
+			"type":                 "object",
+			"additionalProperties": g.singular(fd.MapValue(), rules.GetMap().GetValues()),
+		}
+		applyMapRules(s, fd.MapKey(), rules.GetMap())
+		return s
+	case fd.IsList():
+		s := Schema{
+			"type":  "array",
+			"items": g.singular(fd, rules.GetRepeated().GetItems()),
+		}
+		applyRepeatedRules(s, rules.GetRepeated())
+		return s
+	default:
+		return g.singular(fd, rules)
+	}
+}
+
+// singular returns the schema of a single value of the field
+func (g *generator) singular(fd protoreflect.FieldDescriptor, rules *validate.FieldRules) Schema {
+	var s Schema
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		s = g.ref(fd.Message())
+		if len(s) == 1 && s["$ref"] != nil {
+			// the rules of message (required and skip) are not part of the value
+			return s
+		}
+	case protoreflect.EnumKind:
+		s = enumSchema(fd.Enum(), rules.GetEnum())
+		return s
+	default:
+		s = scalarSchema(fd.Kind())
+	}
+	applyScalarRules(s, rules)
+	return s
+}
+
+func scalarSchema(kind protoreflect.Kind) Schema {
+	switch kind {
+	case protoreflect.BoolKind:
+		return Schema{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return Schema{"type": "integer"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return Schema{"type": "integer", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson accepts both number and string for 64-bit integer
+		s := Schema{"type": []string{"integer", "string"}}
+		if kind == protoreflect.Uint64Kind || kind == protoreflect.Fixed64Kind {
+			s["minimum"] = 0
+		}
+		return s
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return Schema{"type": "number"}
+	case protoreflect.BytesKind:
+		return Schema{"type": "string", "contentEncoding": "base64"}
+	default:
+		return Schema{"type": "string"}
+	}
+}
+
+func enumSchema(ed protoreflect.EnumDescriptor, rules *validate.EnumRules) Schema {
+	allowed := func(n protoreflect.EnumNumber) bool {
+		if rules == nil {
+			return true
+		}
+		if rules.Const != nil && int32(n) != rules.GetConst() {
+			return false
+		}
+		if len(rules.In) > 0 && !containsInt32(rules.In, int32(n)) {
+			return false
+		}
+		return !containsInt32(rules.NotIn, int32(n))
+	}
+
+	values := ed.Values()
+	names := make([]string, 0, values.Len())
+	numbers := make([]int32, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		if allowed(v.Number()) {
+			names = append(names, string(v.Name()))
+			numbers = append(numbers, int32(v.Number()))
+		}
+	}
+	// protojson accepts both the name and the number of the enum value
+	return Schema{"anyOf": []interface{}{
+		Schema{"type": "string", "enum": names},
+		Schema{"type": "integer", "enum": numbers},
+	}}
+}
+
+func containsInt32(ns []int32, n int32) bool {
+	for _, v := range ns {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+var durationPattern = `^-?[0-9]+(\.[0-9]{0,9})?s$`
+
+// wellKnownTypes are the types which have special JSON mapping in protojson
+var wellKnownTypes = map[protoreflect.FullName]func() Schema{
+	"google.protobuf.Duration": func() Schema {
+		return Schema{"type": "string", "pattern": durationPattern}
+	},
+	"google.protobuf.Timestamp": func() Schema {
+		return Schema{"type": "string", "format": "date-time"}
+	},
+	"google.protobuf.FieldMask": func() Schema {
+		return Schema{"type": "string"}
+	},
+	"google.protobuf.Struct": func() Schema {
+		return Schema{"type": "object"}
+	},
+	"google.protobuf.Value": func() Schema {
+		return Schema{}
+	},
+	"google.protobuf.ListValue": func() Schema {
+		return Schema{"type": "array"}
+	},
+	"google.protobuf.Empty": func() Schema {
+		return Schema{"type": "object", "additionalProperties": false}
+	},
+	"google.protobuf.Any": func() Schema {
+		return Schema{"type": "object", "required": []string{"@type"}}
+	},
+	"google.protobuf.BoolValue": func() Schema {
+		return scalarSchema(protoreflect.BoolKind)
+	},
+	"google.protobuf.StringValue": func() Schema {
+		return scalarSchema(protoreflect.StringKind)
+	},
+	"google.protobuf.BytesValue": func() Schema {
+		return scalarSchema(protoreflect.BytesKind)
+	},
+	"google.protobuf.Int32Value": func() Schema {
+		return scalarSchema(protoreflect.Int32Kind)
+	},
+	"google.protobuf.UInt32Value": func() Schema {
+		return scalarSchema(protoreflect.Uint32Kind)
+	},
+	"google.protobuf.Int64Value": func() Schema {
+		return scalarSchema(protoreflect.Int64Kind)
+	},
+	"google.protobuf.UInt64Value": func() Schema {
+		return scalarSchema(protoreflect.Uint64Kind)
+	},
+	"google.protobuf.FloatValue": func() Schema {
+		return scalarSchema(protoreflect.FloatKind)
+	},
+	"google.protobuf.DoubleValue": func() Schema {
+		return scalarSchema(protoreflect.DoubleKind)
+	},
+}
@@ -0,0 +1,139 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "mosn.io/htnn/types/plugins"
+	api "mosn.io/htnn/types/plugins/api/v1"
+	"mosn.io/htnn/types/plugins/consumerrestriction"
+	"mosn.io/htnn/types/plugins/keyauth"
+	"mosn.io/htnn/types/plugins/limitcountredis"
+	"mosn.io/htnn/types/plugins/limitreq"
+	"mosn.io/htnn/types/plugins/limittoken"
+)
+
+// normalize converts the schema to the form after JSON round trip, so it's easier to compare
+func normalize(t *testing.T, s interface{}) map[string]interface{} {
+	d, err := json.Marshal(s)
+	require.NoError(t, err)
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(d, &res))
+	return res
+}
+
+func TestFromMessage(t *testing.T) {
+	s := normalize(t, FromMessage(&limitreq.Config{}))
+	assert.Equal(t, Draft, s["$schema"])
+	assert.Equal(t, false, s["additionalProperties"])
+	assert.Equal(t, []interface{}{"average"}, s["required"])
+	props := s["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type":             "integer",
+		"minimum":          float64(0),
+		"exclusiveMinimum": float64(0),
+	}, props["average"])
+	assert.Equal(t, "string", props["period"].(map[string]interface{})["type"])
+	assert.NotNil(t, props["period"].(map[string]interface{})["pattern"])
+
+	s = normalize(t, FromMessage(&keyauth.Config{}))
+	props = s["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type":     "array",
+		"minItems": float64(1),
+		"items":    map[string]interface{}{"$ref": "#/$defs/types.plugins.keyauth.Key"},
+	}, props["keys"])
+	key := s["$defs"].(map[string]interface{})["types.plugins.keyauth.Key"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"name"}, key["required"])
+	assert.Equal(t, map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"type": "string", "enum": []interface{}{"HEADER", "QUERY"}},
+			map[string]interface{}{"type": "integer", "enum": []interface{}{float64(0), float64(1)}},
+		},
+	}, key["properties"].(map[string]interface{})["source"])
+}
+
+func TestFromMessageOneof(t *testing.T) {
+	// required oneof
+	s := normalize(t, FromMessage(&limitcountredis.Config{}))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"required": []interface{}{"address"}},
+		map[string]interface{}{"required": []interface{}{"cluster"}},
+	}, s["oneOf"])
+
+	s = normalize(t, FromMessage(&api.StringMatcher{}))
+	assert.Len(t, s["oneOf"], 5)
+	// the field in oneof is not required by itself
+	assert.Nil(t, s["required"])
+
+	s = normalize(t, FromMessage(&consumerrestriction.Config{}))
+	assert.Len(t, s["oneOf"], 3)
+
+	// optional oneof, at most one field is set
+	s = normalize(t, FromMessage(&limittoken.Rule{}))
+	oneOf := s["oneOf"].([]interface{})
+	require.Len(t, oneOf, 10)
+	assert.Contains(t, oneOf[9], "not")
+}
+
+func collectRefs(v interface{}, refs map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			if k == "$ref" {
+				refs[sub.(string)] = true
+				continue
+			}
+			collectRefs(sub, refs)
+		}
+	case []interface{}:
+		for _, sub := range v {
+			collectRefs(sub, refs)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	s := normalize(t, Filters())
+	props := s["properties"].(map[string]interface{})
+	require.Contains(t, props, "limitReq")
+	require.Contains(t, props, "keyAuth")
+	limitReq := props["limitReq"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"config"}, limitReq["required"])
+	config := limitReq["properties"].(map[string]interface{})["config"].(map[string]interface{})
+	assert.NotContains(t, config, "$schema")
+	assert.Contains(t, config["properties"], "average")
+
+	// all the references can be resolved from the top level
+	defs := s["$defs"].(map[string]interface{})
+	refs := map[string]bool{}
+	collectRefs(s, refs)
+	assert.NotEmpty(t, refs)
+	for ref := range refs {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		assert.Contains(t, defs, name, ref)
+	}
+
+	s = normalize(t, ConsumerAuth())
+	props = s["properties"].(map[string]interface{})
+	require.Contains(t, props, "keyAuth")
+	assert.NotContains(t, props, "limitReq")
+}
@@ -0,0 +1,93 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"mosn.io/htnn/api/pkg/plugins"
+)
+
+// PluginConfigs returns the JSON Schema of the configuration of each registered plugin type,
+// keyed by the plugin name
+func PluginConfigs() map[string]Schema {
+	schemas := map[string]Schema{}
+	plugins.IteratePluginType(func(name string, p plugins.Plugin) bool {
+		schemas[name] = FromMessage(p.Config())
+		return true
+	})
+	return schemas
+}
+
+// ConsumerConfigs returns the JSON Schema of the consumer configuration of each registered
+// Consumer plugin type, keyed by the plugin name
+func ConsumerConfigs() map[string]Schema {
+	schemas := map[string]Schema{}
+	plugins.IteratePluginType(func(name string, p plugins.Plugin) bool {
+		if cp, ok := p.(plugins.ConsumerPlugin); ok {
+			schemas[name] = FromMessage(cp.ConsumerConfig())
+		}
+		return true
+	})
+	return schemas
+}
+
+// Filters returns the JSON Schema of the `filters` field in FilterPolicy, which is a map from
+// the plugin name to the plugin configuration wrapped in `config`.
+func Filters() Schema {
+	return pluginMap(PluginConfigs())
+}
+
+// ConsumerAuth returns the JSON Schema of the `auth` field in Consumer
+func ConsumerAuth() Schema {
+	return pluginMap(ConsumerConfigs())
+}
+
+// pluginMap combines the schemas into the schema of a map. The `$defs` of each schema are moved
+// to the top level, which is fine as they are keyed by the full name of the message.
+func pluginMap(schemas map[string]Schema) Schema {
+	defs := map[string]interface{}{}
+	props := make(map[string]interface{}, len(schemas))
+	for name, schema := range schemas {
+		if d, ok := schema["$defs"].(map[string]interface{}); ok {
+			for k, v := range d {
+				defs[k] = v
+			}
+		}
+		config := make(Schema, len(schema))
+		for k, v := range schema {
+			if k != "$defs" && k != "$schema" {
+				config[k] = v
+			}
+		}
+		props[name] = Schema{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"config": config,
+			},
+			"required":             []string{"config"},
+			"additionalProperties": false,
+		}
+	}
+
+	s := Schema{
+		"$schema":              Draft,
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(defs) > 0 {
+		s["$defs"] = defs
+	}
+	return s
+}
@@ -0,0 +1,322 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"regexp"
+
+	"github.com/envoyproxy/protoc-gen-validate/validate"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// numericRules returns the rules of the numeric type, like *validate.UInt32Rules. All of them share
+// the same field names.
+func numericRules(rules *validate.FieldRules) protoreflect.Message {
+	if rules == nil {
+		return nil
+	}
+	m := rules.ProtoReflect()
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("type"))
+	if fd == nil {
+		return nil
+	}
+	switch fd.Name() {
+	case "float", "double", "int32", "int64", "uint32", "uint64",
+		"sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64":
+		return m.Get(fd).Message()
+	}
+	return nil
+}
+
+func toFloat(v protoreflect.Value) float64 {
+	switch n := v.Interface().(type) {
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint32:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+func listValues(l protoreflect.List) []interface{} {
+	res := make([]interface{}, l.Len())
+	for i := 0; i < l.Len(); i++ {
+		res[i] = l.Get(i).Interface()
+	}
+	return res
+}
+
+func applyNumericRules(s Schema, r protoreflect.Message) {
+	fields := r.Descriptor().Fields()
+	get := func(name protoreflect.Name) (protoreflect.Value, bool) {
+		fd := fields.ByName(name)
+		if fd == nil || !r.Has(fd) {
+			return protoreflect.Value{}, false
+		}
+		return r.Get(fd), true
+	}
+
+	if v, ok := get("const"); ok {
+		s["const"] = v.Interface()
+	}
+	lt, hasLt := get("lt")
+	lte, hasLte := get("lte")
+	gt, hasGt := get("gt")
+	gte, hasGte := get("gte")
+	upper, hasUpper := lt, hasLt
+	if !hasUpper {
+		upper, hasUpper = lte, hasLte
+	}
+	lower, hasLower := gt, hasGt
+	if !hasLower {
+		lower, hasLower = gte, hasGte
+	}
+	// When the upper bound is less than the lower bound, protoc-gen-validate treats the range as
+	// exclusive, which can't be expressed with the keywords below
+	if !hasUpper || !hasLower || toFloat(upper) >= toFloat(lower) {
+		if hasLt {
+			s["exclusiveMaximum"] = lt.Interface()
+		}
+		if hasLte {
+			s["maximum"] = lte.Interface()
+		}
+		if hasGt {
+			s["exclusiveMinimum"] = gt.Interface()
+		}
+		if hasGte {
+			s["minimum"] = gte.Interface()
+		}
+	}
+	if fd := fields.ByName("in"); fd != nil && r.Get(fd).List().Len() > 0 {
+		s["enum"] = listValues(r.Get(fd).List())
+	}
+	if fd := fields.ByName("not_in"); fd != nil && r.Get(fd).List().Len() > 0 {
+		s["not"] = Schema{"enum": listValues(r.Get(fd).List())}
+	}
+}
+
+var wellKnownStringFormats = []struct {
+	format string
+	check  func(r *validate.StringRules) bool
+}{
+	{"email", (*validate.StringRules).GetEmail},
+	{"hostname", (*validate.StringRules).GetHostname},
+	{"ip", (*validate.StringRules).GetIp},
+	{"ipv4", (*validate.StringRules).GetIpv4},
+	{"ipv6", (*validate.StringRules).GetIpv6},
+	{"uri", (*validate.StringRules).GetUri},
+	{"uri-reference", (*validate.StringRules).GetUriRef},
+	{"uuid", (*validate.StringRules).GetUuid},
+}
+
+func applyStringRules(s Schema, r *validate.StringRules) {
+	if r.Const != nil {
+		s["const"] = r.GetConst()
+	}
+	if r.Len != nil {
+		s["minLength"] = r.GetLen()
+		s["maxLength"] = r.GetLen()
+	}
+	if r.MinLen != nil {
+		s["minLength"] = r.GetMinLen()
+	}
+	if r.MaxLen != nil {
+		s["maxLength"] = r.GetMaxLen()
+	}
+
+	var patterns []string
+	if r.Pattern != nil {
+		patterns = append(patterns, r.GetPattern())
+	}
+	if r.Prefix != nil {
+		patterns = append(patterns, "^"+regexp.QuoteMeta(r.GetPrefix()))
+	}
+	if r.Suffix != nil {
+		patterns = append(patterns, regexp.QuoteMeta(r.GetSuffix())+"$")
+	}
+	if r.Contains != nil {
+		patterns = append(patterns, regexp.QuoteMeta(r.GetContains()))
+	}
+	if len(patterns) == 1 {
+		s["pattern"] = patterns[0]
+	} else if len(patterns) > 1 {
+		all := make([]interface{}, len(patterns))
+		for i, p := range patterns {
+			all[i] = Schema{"pattern": p}
+		}
+		s["allOf"] = all
+	}
+
+	if len(r.In) > 0 {
+		s["enum"] = r.In
+	}
+	if len(r.NotIn) > 0 {
+		s["not"] = Schema{"enum": r.NotIn}
+	}
+	for _, f := range wellKnownStringFormats {
+		if f.check(r) {
+			s["format"] = f.format
+			break
+		}
+	}
+}
+
+func applyScalarRules(s Schema, rules *validate.FieldRules) {
+	if rules == nil {
+		return
+	}
+	if r := numericRules(rules); r != nil {
+		applyNumericRules(s, r)
+		return
+	}
+	if r := rules.GetString_(); r != nil {
+		applyStringRules(s, r)
+		return
+	}
+	if r := rules.GetBool(); r != nil && r.Const != nil {
+		s["const"] = r.GetConst()
+	}
+}
+
+func applyRepeatedRules(s Schema, r *validate.RepeatedRules) {
+	if r == nil {
+		return
+	}
+	if r.MinItems != nil {
+		s["minItems"] = r.GetMinItems()
+	}
+	if r.MaxItems != nil {
+		s["maxItems"] = r.GetMaxItems()
+	}
+	if r.GetUnique() {
+		s["uniqueItems"] = true
+	}
+}
+
+func applyMapRules(s Schema, key protoreflect.FieldDescriptor, r *validate.MapRules) {
+	if r == nil {
+		return
+	}
+	if r.MinPairs != nil {
+		s["minProperties"] = r.GetMinPairs()
+	}
+	if r.MaxPairs != nil {
+		s["maxProperties"] = r.GetMaxPairs()
+	}
+	if key.Kind() == protoreflect.StringKind && r.GetKeys().GetString_() != nil {
+		names := Schema{}
+		applyStringRules(names, r.GetKeys().GetString_())
+		s["propertyNames"] = names
+	}
+}
+
+// isRequired returns true if the field must be set. Besides the `required` rule of the message,
+// a scalar field is required when its zero value is rejected, like a string with `min_len: 1`.
+func isRequired(fd protoreflect.FieldDescriptor, rules *validate.FieldRules) bool {
+	if rules == nil {
+		return false
+	}
+	if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+		return rules.GetMessage().GetRequired()
+	}
+	if fd.HasPresence() {
+		// proto3 optional field is only validated when it's set
+		return false
+	}
+
+	switch {
+	case fd.IsMap():
+		return rules.GetMap().GetMinPairs() > 0 && !rules.GetMap().GetIgnoreEmpty()
+	case fd.IsList():
+		return rules.GetRepeated().GetMinItems() > 0 && !rules.GetRepeated().GetIgnoreEmpty()
+	}
+
+	if r := rules.GetString_(); r != nil {
+		if r.GetIgnoreEmpty() {
+			return false
+		}
+		return r.GetMinLen() > 0 || r.GetLen() > 0 || r.GetMinBytes() > 0 || r.GetLenBytes() > 0 ||
+			(r.Const != nil && r.GetConst() != "")
+	}
+	if r := rules.GetBytes(); r != nil {
+		return !r.GetIgnoreEmpty() && (r.GetMinLen() > 0 || r.GetLen() > 0)
+	}
+	if r := rules.GetEnum(); r != nil {
+		return (r.Const != nil && r.GetConst() != 0) || (len(r.In) > 0 && !containsInt32(r.In, 0))
+	}
+	if r := numericRules(rules); r != nil {
+		return zeroRejected(r)
+	}
+	return false
+}
+
+func zeroRejected(r protoreflect.Message) bool {
+	fields := r.Descriptor().Fields()
+	value := func(name protoreflect.Name) (float64, bool) {
+		fd := fields.ByName(name)
+		if fd == nil || !r.Has(fd) {
+			return 0, false
+		}
+		return toFloat(r.Get(fd)), true
+	}
+
+	if fd := fields.ByName("ignore_empty"); fd != nil && r.Get(fd).Bool() {
+		return false
+	}
+	if v, ok := value("const"); ok {
+		return v != 0
+	}
+	lt, hasLt := value("lt")
+	lte, hasLte := value("lte")
+	gt, hasGt := value("gt")
+	gte, hasGte := value("gte")
+	if (hasLt || hasLte) && (hasGt || hasGte) {
+		upper, lower := lt, gt
+		if !hasLt {
+			upper = lte
+		}
+		if !hasGt {
+			lower = gte
+		}
+		if upper < lower {
+			// exclusive range, leave it to the validation
+			return false
+		}
+	}
+	if (hasGt && gt >= 0) || (hasGte && gte > 0) || (hasLt && lt <= 0) || (hasLte && lte < 0) {
+		return true
+	}
+	if fd := fields.ByName("in"); fd != nil {
+		l := r.Get(fd).List()
+		if l.Len() == 0 {
+			return false
+		}
+		for i := 0; i < l.Len(); i++ {
+			if toFloat(l.Get(i)) == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.24.4
+// source: types/plugins/authnpolicy/config.proto
+
+package authnpolicy
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Mode int32
+
+const (
+	// The first Consumer plugin which authenticates the request wins, the others are skipped
+	Mode_ANY Mode = 0
+	// All the Consumer plugins must authenticate the request as the same consumer
+	Mode_ALL Mode = 1
+)
+
+// Enum value maps for Mode.
+var (
+	Mode_name = map[int32]string{
+		0: "ANY",
+		1: "ALL",
+	}
+	Mode_value = map[string]int32{
+		"ANY": 0,
+		"ALL": 1,
+	}
+)
+
+func (x Mode) Enum() *Mode {
+	p := new(Mode)
+	*p = x
+	return p
+}
+
+func (x Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_types_plugins_authnpolicy_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Mode) Type() protoreflect.EnumType {
+	return &file_types_plugins_authnpolicy_config_proto_enumTypes[0]
+}
+
+func (x Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Mode.Descriptor instead.
+func (Mode) EnumDescriptor() ([]byte, []int) {
+	return file_types_plugins_authnpolicy_config_proto_rawDescGZIP(), []int{0}
+}
+
+type Config struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode Mode `protobuf:"varint,1,opt,name=mode,proto3,enum=types.plugins.authnpolicy.Mode" json:"mode,omitempty"`
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_plugins_authnpolicy_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_types_plugins_authnpolicy_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_types_plugins_authnpolicy_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetMode() Mode {
+	if x != nil {
+		return x.Mode
+	}
+	return Mode_ANY
+}
+
+var File_types_plugins_authnpolicy_config_proto protoreflect.FileDescriptor
+
+var file_types_plugins_authnpolicy_config_proto_rawDesc = []byte{
+	0x0a, 0x26, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2f,
+	0x61, 0x75, 0x74, 0x68, 0x6e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x19, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e,
+	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x6e, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x22, 0x3d, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x74, 0x79,
+	0x70, 0x65, 0x73, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2e, 0x61, 0x75, 0x74, 0x68,
+	0x6e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x2a, 0x18, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4e,
+	0x59, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x01, 0x42, 0x28, 0x5a, 0x26,
+	0x6d, 0x6f, 0x73, 0x6e, 0x2e, 0x69, 0x6f, 0x2f, 0x68, 0x74, 0x6e, 0x6e, 0x2f, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x6e,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_types_plugins_authnpolicy_config_proto_rawDescOnce sync.Once
+	file_types_plugins_authnpolicy_config_proto_rawDescData = file_types_plugins_authnpolicy_config_proto_rawDesc
+)
+
+func file_types_plugins_authnpolicy_config_proto_rawDescGZIP() []byte {
+	file_types_plugins_authnpolicy_config_proto_rawDescOnce.Do(func() {
+		file_types_plugins_authnpolicy_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_types_plugins_authnpolicy_config_proto_rawDescData)
+	})
+	return file_types_plugins_authnpolicy_config_proto_rawDescData
+}
+
+var file_types_plugins_authnpolicy_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_types_plugins_authnpolicy_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_types_plugins_authnpolicy_config_proto_goTypes = []interface{}{
+	(Mode)(0),      // 0: types.plugins.authnpolicy.Mode
+	(*Config)(nil), // 1: types.plugins.authnpolicy.Config
+}
+var file_types_plugins_authnpolicy_config_proto_depIdxs = []int32{
+	0, // 0: types.plugins.authnpolicy.Config.mode:type_name -> types.plugins.authnpolicy.Mode
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_types_plugins_authnpolicy_config_proto_init() }
+func file_types_plugins_authnpolicy_config_proto_init() {
+	if File_types_plugins_authnpolicy_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_types_plugins_authnpolicy_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Config); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_types_plugins_authnpolicy_config_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_types_plugins_authnpolicy_config_proto_goTypes,
+		DependencyIndexes: file_types_plugins_authnpolicy_config_proto_depIdxs,
+		EnumInfos:         file_types_plugins_authnpolicy_config_proto_enumTypes,
+		MessageInfos:      file_types_plugins_authnpolicy_config_proto_msgTypes,
+	}.Build()
+	File_types_plugins_authnpolicy_config_proto = out.File
+	file_types_plugins_authnpolicy_config_proto_rawDesc = nil
+	file_types_plugins_authnpolicy_config_proto_goTypes = nil
+	file_types_plugins_authnpolicy_config_proto_depIdxs = nil
+}
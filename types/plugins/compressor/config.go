@@ -0,0 +1,67 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compressor
+
+import (
+	compressor "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+)
+
+const (
+	GzipName   = "gzip"
+	BrotliName = "brotli"
+	ZstdName   = "zstd"
+)
+
+func init() {
+	plugins.RegisterPluginType(GzipName, &GzipPlugin{})
+	plugins.RegisterPluginType(BrotliName, &BrotliPlugin{})
+	plugins.RegisterPluginType(ZstdName, &ZstdPlugin{})
+}
+
+type plugin struct {
+	plugins.PluginMethodDefaultImpl
+}
+
+func (p *plugin) Type() plugins.PluginType {
+	return plugins.TypeTransform
+}
+
+func (p *plugin) Order() plugins.PluginOrder {
+	// The compressor should be the first one in the encode path, so it's put at the
+	// beginning of the filter chain.
+	return plugins.PluginOrder{
+		Position:  plugins.OrderPositionOuter,
+		Operation: plugins.OrderOperationInsertFirst,
+	}
+}
+
+func (p *plugin) Config() api.PluginConfig {
+	return &compressor.CompressorOverrides{}
+}
+
+type GzipPlugin struct {
+	plugin
+}
+
+type BrotliPlugin struct {
+	plugin
+}
+
+type ZstdPlugin struct {
+	plugin
+}
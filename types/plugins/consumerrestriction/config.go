@@ -15,6 +15,8 @@
 package consumerrestriction
 
 import (
+	"errors"
+
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/api/pkg/plugins"
 )
@@ -42,5 +44,27 @@ func (p *Plugin) Order() plugins.PluginOrder {
 }
 
 func (p *Plugin) Config() api.PluginConfig {
-	return &Config{}
+	return &CustomConfig{}
+}
+
+type CustomConfig struct {
+	Config
+}
+
+func (conf *CustomConfig) Validate() error {
+	err := conf.Config.Validate()
+	if err != nil {
+		return err
+	}
+
+	rules := conf.GetAllow()
+	if rules == nil {
+		rules = conf.GetDeny()
+	}
+	for _, r := range rules.GetRules() {
+		if r.Name == "" && len(r.Metadata) == 0 {
+			return errors.New("either name or metadata is required in the rule")
+		}
+	}
+	return nil
 }
@@ -56,16 +56,7 @@ func (m *Rule) validate(all bool) error {
 
 	var errors []error
 
-	if utf8.RuneCountInString(m.GetName()) < 1 {
-		err := RuleValidationError{
-			field:  "Name",
-			reason: "value length must be at least 1 runes",
-		}
-		if !all {
-			return err
-		}
-		errors = append(errors, err)
-	}
+	// no validation rules for Name
 
 	for idx, item := range m.GetMethods() {
 		_, _ = idx, item
@@ -83,6 +74,8 @@ func (m *Rule) validate(all bool) error {
 
 	}
 
+	// no validation rules for Metadata
+
 	if len(errors) > 0 {
 		return RuleMultiError(errors)
 	}
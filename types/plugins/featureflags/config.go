@@ -0,0 +1,80 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"fmt"
+
+	"mosn.io/htnn/api/pkg/dynamicconfig"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+	"mosn.io/htnn/types/dynamicconfigs/featureflags"
+)
+
+const (
+	Name = "featureFlags"
+)
+
+func init() {
+	plugins.RegisterPluginType(Name, &Plugin{})
+}
+
+type Plugin struct {
+	plugins.PluginMethodDefaultImpl
+}
+
+func (p *Plugin) Type() plugins.PluginType {
+	return plugins.TypeTraffic
+}
+
+func (p *Plugin) Order() plugins.PluginOrder {
+	return plugins.PluginOrder{
+		Position:  plugins.OrderPositionTraffic,
+		Operation: plugins.OrderOperationInsertFirst,
+	}
+}
+
+func (p *Plugin) Config() api.PluginConfig {
+	return &CustomConfig{}
+}
+
+type CustomConfig struct {
+	Config
+}
+
+// DynamicConfigName returns the name of the referred featureFlags DynamicConfig
+func (conf *CustomConfig) DynamicConfigName() string {
+	if conf.DynamicConfigRef == "" {
+		return featureflags.Name
+	}
+	return conf.DynamicConfigRef
+}
+
+func (conf *CustomConfig) Validate() error {
+	err := conf.Config.Validate()
+	if err != nil {
+		return err
+	}
+
+	name := conf.DynamicConfigName()
+	p := dynamicconfig.LoadDynamicConfigProvider(name)
+	if p == nil {
+		return fmt.Errorf("unknown dynamic config %s", name)
+	}
+	if _, ok := p.Config().(*featureflags.CustomConfig); !ok {
+		return fmt.Errorf("dynamic config %s is not a featureFlags DynamicConfig", name)
+	}
+	return nil
+}
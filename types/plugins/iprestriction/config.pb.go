@@ -0,0 +1,347 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.24.4
+// source: types/plugins/iprestriction/config.proto
+
+package iprestriction
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	_ "github.com/envoyproxy/protoc-gen-validate/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DenyList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the list in the ipList DynamicConfig.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The status code returned when the IP is in the list. Default to the `status_code` in the Config.
+	StatusCode uint32 `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+}
+
+func (x *DenyList) Reset() {
+	*x = DenyList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_plugins_iprestriction_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DenyList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DenyList) ProtoMessage() {}
+
+func (x *DenyList) ProtoReflect() protoreflect.Message {
+	mi := &file_types_plugins_iprestriction_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DenyList.ProtoReflect.Descriptor instead.
+func (*DenyList) Descriptor() ([]byte, []int) {
+	return file_types_plugins_iprestriction_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DenyList) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DenyList) GetStatusCode() uint32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+type XForwardedFor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The position of the address in the `X-Forwarded-For` header, counted from the right and
+	// starting from 1. Default to 1, which is the address appended by the nearest proxy.
+	Hop uint32 `protobuf:"varint,1,opt,name=hop,proto3" json:"hop,omitempty"`
+}
+
+func (x *XForwardedFor) Reset() {
+	*x = XForwardedFor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_plugins_iprestriction_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *XForwardedFor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*XForwardedFor) ProtoMessage() {}
+
+func (x *XForwardedFor) ProtoReflect() protoreflect.Message {
+	mi := &file_types_plugins_iprestriction_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use XForwardedFor.ProtoReflect.Descriptor instead.
+func (*XForwardedFor) Descriptor() ([]byte, []int) {
+	return file_types_plugins_iprestriction_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *XForwardedFor) GetHop() uint32 {
+	if x != nil {
+		return x.Hop
+	}
+	return 0
+}
+
+type Config struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The request whose IP is in any of the lists is rejected with the status code of the list.
+	Deny []*DenyList `protobuf:"bytes,1,rep,name=deny,proto3" json:"deny,omitempty"`
+	// The names of the lists in the ipList DynamicConfig. If it's not empty, the request whose IP
+	// is not in any of the lists is rejected.
+	Allow []string `protobuf:"bytes,2,rep,name=allow,proto3" json:"allow,omitempty"`
+	// The status code returned when the IP is rejected. Default to 403.
+	StatusCode uint32 `protobuf:"varint,3,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	// Use the address in the `X-Forwarded-For` header instead of the downstream remote address.
+	XForwardedFor *XForwardedFor `protobuf:"bytes,4,opt,name=x_forwarded_for,json=xForwardedFor,proto3" json:"x_forwarded_for,omitempty"`
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_types_plugins_iprestriction_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_types_plugins_iprestriction_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_types_plugins_iprestriction_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Config) GetDeny() []*DenyList {
+	if x != nil {
+		return x.Deny
+	}
+	return nil
+}
+
+func (x *Config) GetAllow() []string {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *Config) GetStatusCode() uint32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *Config) GetXForwardedFor() *XForwardedFor {
+	if x != nil {
+		return x.XForwardedFor
+	}
+	return nil
+}
+
+var File_types_plugins_iprestriction_config_proto protoreflect.FileDescriptor
+
+var file_types_plugins_iprestriction_config_proto_rawDesc = []byte{
+	0x0a, 0x28, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2f,
+	0x69, 0x70, 0x72, 0x65, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1b, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2e, 0x69, 0x70, 0x72, 0x65, 0x73, 0x74,
+	0x72, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x57, 0x0a, 0x08, 0x44, 0x65, 0x6e, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72,
+	0x02, 0x10, 0x01, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x0b, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0d,
+	0xfa, 0x42, 0x0a, 0x2a, 0x08, 0x10, 0xd8, 0x04, 0x28, 0x90, 0x03, 0x40, 0x01, 0x52, 0x0a, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x21, 0x0a, 0x0d, 0x58, 0x46, 0x6f,
+	0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x46, 0x6f, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x68, 0x6f,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x68, 0x6f, 0x70, 0x22, 0xed, 0x01, 0x0a,
+	0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x39, 0x0a, 0x04, 0x64, 0x65, 0x6e, 0x79, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x73, 0x2e, 0x69, 0x70, 0x72, 0x65, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x44, 0x65, 0x6e, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x04, 0x64, 0x65,
+	0x6e, 0x79, 0x12, 0x24, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x42, 0x0e, 0xfa, 0x42, 0x0b, 0x92, 0x01, 0x08, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01, 0x28,
+	0x01, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x2e, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0d, 0xfa,
+	0x42, 0x0a, 0x2a, 0x08, 0x10, 0xd8, 0x04, 0x28, 0x90, 0x03, 0x40, 0x01, 0x52, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x78, 0x5f, 0x66, 0x6f,
+	0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x66, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x73, 0x2e, 0x69, 0x70, 0x72, 0x65, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x58, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x46, 0x6f, 0x72, 0x52, 0x0d, 0x78,
+	0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x46, 0x6f, 0x72, 0x42, 0x2a, 0x5a, 0x28,
+	0x6d, 0x6f, 0x73, 0x6e, 0x2e, 0x69, 0x6f, 0x2f, 0x68, 0x74, 0x6e, 0x6e, 0x2f, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2f, 0x69, 0x70, 0x72, 0x65, 0x73,
+	0x74, 0x72, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_types_plugins_iprestriction_config_proto_rawDescOnce sync.Once
+	file_types_plugins_iprestriction_config_proto_rawDescData = file_types_plugins_iprestriction_config_proto_rawDesc
+)
+
+func file_types_plugins_iprestriction_config_proto_rawDescGZIP() []byte {
+	file_types_plugins_iprestriction_config_proto_rawDescOnce.Do(func() {
+		file_types_plugins_iprestriction_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_types_plugins_iprestriction_config_proto_rawDescData)
+	})
+	return file_types_plugins_iprestriction_config_proto_rawDescData
+}
+
+var file_types_plugins_iprestriction_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_types_plugins_iprestriction_config_proto_goTypes = []interface{}{
+	(*DenyList)(nil),      // 0: types.plugins.iprestriction.DenyList
+	(*XForwardedFor)(nil), // 1: types.plugins.iprestriction.XForwardedFor
+	(*Config)(nil),        // 2: types.plugins.iprestriction.Config
+}
+var file_types_plugins_iprestriction_config_proto_depIdxs = []int32{
+	0, // 0: types.plugins.iprestriction.Config.deny:type_name -> types.plugins.iprestriction.DenyList
+	1, // 1: types.plugins.iprestriction.Config.x_forwarded_for:type_name -> types.plugins.iprestriction.XForwardedFor
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_types_plugins_iprestriction_config_proto_init() }
+func file_types_plugins_iprestriction_config_proto_init() {
+	if File_types_plugins_iprestriction_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_types_plugins_iprestriction_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DenyList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_plugins_iprestriction_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*XForwardedFor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_types_plugins_iprestriction_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Config); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_types_plugins_iprestriction_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_types_plugins_iprestriction_config_proto_goTypes,
+		DependencyIndexes: file_types_plugins_iprestriction_config_proto_depIdxs,
+		MessageInfos:      file_types_plugins_iprestriction_config_proto_msgTypes,
+	}.Build()
+	File_types_plugins_iprestriction_config_proto = out.File
+	file_types_plugins_iprestriction_config_proto_rawDesc = nil
+	file_types_plugins_iprestriction_config_proto_goTypes = nil
+	file_types_plugins_iprestriction_config_proto_depIdxs = nil
+}
@@ -0,0 +1,119 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtauthn
+
+import (
+	"errors"
+	"fmt"
+
+	jwt_authn "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+)
+
+const (
+	Name = "jwtAuthn"
+)
+
+func init() {
+	plugins.RegisterPluginType(Name, &Plugin{})
+}
+
+type Plugin struct {
+	plugins.PluginMethodDefaultImpl
+}
+
+func (p *Plugin) Type() plugins.PluginType {
+	return plugins.TypeAuthn
+}
+
+func (p *Plugin) Order() plugins.PluginOrder {
+	// Run after the traffic control plugins and before the authorization plugins like rbac
+	return plugins.PluginOrder{
+		Position:  plugins.OrderPositionOuter,
+		Operation: plugins.OrderOperationInsertLast,
+	}
+}
+
+type CustomConfig struct {
+	jwt_authn.JwtAuthentication
+}
+
+func (conf *CustomConfig) Validate() error {
+	err := conf.JwtAuthentication.Validate()
+	if err != nil {
+		return err
+	}
+
+	// The providers of all the routes are merged into the same HTTP filter, so the fields which
+	// affect the whole filter are not allowed.
+	var unsupported error
+	conf.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		switch fd.Name() {
+		case "providers", "requirement_map":
+			return true
+		}
+		unsupported = fmt.Errorf("%s is not supported, only providers and requirementMap can be configured", fd.JSONName())
+		return false
+	})
+	if unsupported != nil {
+		return unsupported
+	}
+
+	if len(conf.Providers) == 0 {
+		return errors.New("providers is required")
+	}
+	if len(conf.RequirementMap) > 1 {
+		return errors.New("requirementMap can contain at most one requirement, which is applied to the route")
+	}
+	for _, req := range conf.RequirementMap {
+		err = conf.validateRequirement(req)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (conf *CustomConfig) validateRequirement(req *jwt_authn.JwtRequirement) error {
+	name := req.GetProviderName()
+	if name == "" {
+		name = req.GetProviderAndAudiences().GetProviderName()
+	}
+	if name != "" {
+		if _, ok := conf.Providers[name]; !ok {
+			return fmt.Errorf("provider %s is not defined", name)
+		}
+		return nil
+	}
+
+	reqs := req.GetRequiresAny().GetRequirements()
+	if len(reqs) == 0 {
+		reqs = req.GetRequiresAll().GetRequirements()
+	}
+	for _, r := range reqs {
+		err := conf.validateRequirement(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) Config() api.PluginConfig {
+	return &CustomConfig{}
+}
@@ -0,0 +1,121 @@
+// Copyright The HTNN Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtauthn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name: "ok",
+			input: `
+{
+  "providers": {
+    "example": {
+      "issuer": "https://example.com",
+      "localJwks": {"inlineString": "{}"}
+    }
+  },
+  "requirementMap": {
+    "default": {"providerName": "example"}
+  }
+}
+			`,
+		},
+		{
+			name:  "providers required",
+			input: `{}`,
+			err:   "providers is required",
+		},
+		{
+			name: "filter level field",
+			input: `
+{
+  "providers": {
+    "example": {
+      "localJwks": {"inlineString": "{}"}
+    }
+  },
+  "bypassCorsPreflight": true
+}
+			`,
+			err: "bypassCorsPreflight is not supported",
+		},
+		{
+			name: "too many requirements",
+			input: `
+{
+  "providers": {
+    "example": {
+      "localJwks": {"inlineString": "{}"}
+    }
+  },
+  "requirementMap": {
+    "a": {"providerName": "example"},
+    "b": {"allowMissing": {}}
+  }
+}
+			`,
+			err: "requirementMap can contain at most one requirement",
+		},
+		{
+			name: "unknown provider",
+			input: `
+{
+  "providers": {
+    "example": {
+      "localJwks": {"inlineString": "{}"}
+    }
+  },
+  "requirementMap": {
+    "default": {
+      "requiresAny": {
+        "requirements": [
+          {"providerName": "example"},
+          {"providerAndAudiences": {"providerName": "unknown"}}
+        ]
+      }
+    }
+  }
+}
+			`,
+			err: "provider unknown is not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &CustomConfig{}
+			err := protojson.Unmarshal([]byte(tt.input), conf)
+			if err == nil {
+				err = conf.Validate()
+			}
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
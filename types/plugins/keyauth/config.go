@@ -15,6 +15,10 @@
 package keyauth
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 	"mosn.io/htnn/api/pkg/plugins"
 )
@@ -46,9 +50,82 @@ func (p *Plugin) Config() api.PluginConfig {
 }
 
 func (p *Plugin) ConsumerConfig() api.PluginConsumerConfig {
-	return &ConsumerConfig{}
+	return &CustomConsumerConfig{}
+}
+
+type CustomConsumerConfig struct {
+	ConsumerConfig
+}
+
+func (conf *CustomConsumerConfig) Validate() error {
+	err := conf.ConsumerConfig.Validate()
+	if err != nil {
+		return err
+	}
+
+	if conf.Key == "" && len(conf.Credentials) == 0 {
+		return errors.New("either key or credentials is required")
+	}
+
+	keys := make(map[string]struct{}, len(conf.Credentials)+1)
+	if conf.Key != "" {
+		keys[conf.Key] = struct{}{}
+	}
+	for _, cred := range conf.Credentials {
+		if _, ok := keys[cred.Key]; ok {
+			return fmt.Errorf("duplicate key %s", cred.Key)
+		}
+		keys[cred.Key] = struct{}{}
+
+		if cred.NotBefore != nil && cred.ExpiresAt != nil &&
+			!cred.NotBefore.AsTime().Before(cred.ExpiresAt.AsTime()) {
+			return fmt.Errorf("notBefore should be earlier than expiresAt for key %s", cred.Key)
+		}
+	}
+	return nil
 }
 
-func (conf *ConsumerConfig) Index() string {
+func (conf *CustomConsumerConfig) Index() string {
 	return conf.Key
 }
+
+func (conf *CustomConsumerConfig) Indexes() []string {
+	idx := make([]string, 0, len(conf.Credentials)+1)
+	if conf.Key != "" {
+		idx = append(idx, conf.Key)
+	}
+	for _, cred := range conf.Credentials {
+		idx = append(idx, cred.Key)
+	}
+	return idx
+}
+
+type CredentialStatus int
+
+const (
+	CredentialValid CredentialStatus = iota
+	CredentialNotYetValid
+	CredentialExpired
+	CredentialNotFound
+)
+
+// CredentialStatus returns the status of the given key at the given time.
+// The key should be one of the indexes of this config.
+func (conf *CustomConsumerConfig) CredentialStatus(key string, now time.Time) CredentialStatus {
+	if key == conf.Key {
+		return CredentialValid
+	}
+	for _, cred := range conf.Credentials {
+		if cred.Key != key {
+			continue
+		}
+		if cred.NotBefore != nil && now.Before(cred.NotBefore.AsTime()) {
+			return CredentialNotYetValid
+		}
+		if cred.ExpiresAt != nil && !now.Before(cred.ExpiresAt.AsTime()) {
+			return CredentialExpired
+		}
+		return CredentialValid
+	}
+	return CredentialNotFound
+}
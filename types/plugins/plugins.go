@@ -17,18 +17,29 @@ package plugins
 import (
 	_ "mosn.io/htnn/types/dynamicconfigs"
 	_ "mosn.io/htnn/types/plugins/aicontentsecurity"
+	_ "mosn.io/htnn/types/plugins/anonymousconsumer"
+	_ "mosn.io/htnn/types/plugins/authnpolicy"
 	_ "mosn.io/htnn/types/plugins/bandwidthlimit"
 	_ "mosn.io/htnn/types/plugins/buffer"
 	_ "mosn.io/htnn/types/plugins/casbin"
 	_ "mosn.io/htnn/types/plugins/celscript"
+	_ "mosn.io/htnn/types/plugins/compressor"
+	_ "mosn.io/htnn/types/plugins/consumermetadataheaders"
 	_ "mosn.io/htnn/types/plugins/consumerrestriction"
 	_ "mosn.io/htnn/types/plugins/cors"
+	_ "mosn.io/htnn/types/plugins/csrf"
 	_ "mosn.io/htnn/types/plugins/debugmode"
 	_ "mosn.io/htnn/types/plugins/demo"
 	_ "mosn.io/htnn/types/plugins/extauth"
 	_ "mosn.io/htnn/types/plugins/extproc"
 	_ "mosn.io/htnn/types/plugins/fault"
+	_ "mosn.io/htnn/types/plugins/featureflags"
+	_ "mosn.io/htnn/types/plugins/grpcjsontranscoder"
+	_ "mosn.io/htnn/types/plugins/grpcweb"
+	_ "mosn.io/htnn/types/plugins/headertometadata"
 	_ "mosn.io/htnn/types/plugins/hmacauth"
+	_ "mosn.io/htnn/types/plugins/iprestriction"
+	_ "mosn.io/htnn/types/plugins/jwtauthn"
 	_ "mosn.io/htnn/types/plugins/keyauth"
 	_ "mosn.io/htnn/types/plugins/limitcountredis"
 	_ "mosn.io/htnn/types/plugins/limitreq"
@@ -39,6 +50,8 @@ import (
 	_ "mosn.io/htnn/types/plugins/networkrbac"
 	_ "mosn.io/htnn/types/plugins/oidc"
 	_ "mosn.io/htnn/types/plugins/opa"
+	_ "mosn.io/htnn/types/plugins/ratelimit"
+	_ "mosn.io/htnn/types/plugins/rbac"
 	_ "mosn.io/htnn/types/plugins/routepatch"
 	_ "mosn.io/htnn/types/plugins/sentinel"
 	_ "mosn.io/htnn/types/plugins/tlsinspector"